@@ -0,0 +1,103 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	// challengeTag is the tag of the BIP-340 challenge hash
+	challengeTag = "BIP0340/challenge"
+)
+
+// XOnlyPubKey returns the 32-byte x-only encoding of `pub` used by BIP-340. The key it encodes is the point with the
+// same x and an even y, which is either pub or -pub; the signers use the matching sign of their shares.
+func XOnlyPubKey(pub *crypto.ECPoint) []byte {
+	if pub == nil || !pub.ValidateBasic() {
+		return nil
+	}
+	return pad32(pub.X())
+}
+
+// Verify checks a 64-byte BIP-340 signature of `msg` against the x-only public key of `pub` following the
+// verification algorithm of BIP-340
+func Verify(pub *crypto.ECPoint, msg, sig []byte) bool {
+	ec := tss.S256()
+	params := ec.Params()
+	if pub == nil || !pub.ValidateBasic() || !tss.SameCurve(pub.Curve(), ec) || len(sig) != 64 {
+		return false
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Cmp(params.P) >= 0 || s.Cmp(params.N) >= 0 {
+		return false
+	}
+	P := evenY(pub)
+	e := challenge(r, P.X(), msg)
+
+	// R = s*G - e*P
+	sGx, sGy := ec.ScalarBaseMult(pad32(s))
+	ePx, ePy := ec.ScalarMult(P.X(), P.Y(), pad32(e))
+	Rx, Ry := ec.Add(sGx, sGy, ePx, new(big.Int).Sub(params.P, ePy))
+	if !ec.IsOnCurve(Rx, Ry) || Ry.Bit(0) != 0 {
+		return false // the point at infinity is not on the curve
+	}
+	return Rx.Cmp(r) == 0
+}
+
+// ----- //
+
+// challenge returns e = int(hash_BIP0340/challenge(bytes(r) || bytes(px) || m)) mod n
+func challenge(r, px *big.Int, m []byte) *big.Int {
+	h := taggedHash(challengeTag, pad32(r), pad32(px), m)
+	e := new(big.Int).SetBytes(h)
+	return e.Mod(e, tss.S256().Params().N)
+}
+
+// taggedHash returns SHA256(SHA256(tag) || SHA256(tag) || x...)
+func taggedHash(tag string, x ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, bz := range x {
+		h.Write(bz)
+	}
+	return h.Sum(nil)
+}
+
+// hasEvenY reports whether the y coordinate of p is even
+func hasEvenY(p *crypto.ECPoint) bool {
+	return p.Y().Bit(0) == 0
+}
+
+// evenY returns p if its y coordinate is even and -p otherwise
+func evenY(p *crypto.ECPoint) *crypto.ECPoint {
+	if hasEvenY(p) {
+		return p
+	}
+	return negate(p)
+}
+
+func negate(p *crypto.ECPoint) *crypto.ECPoint {
+	return crypto.NewECPointNoCurveCheck(p.Curve(), p.X(), new(big.Int).Sub(p.Curve().Params().P, p.Y()))
+}
+
+// pad32 returns the 32-byte big-endian encoding of x, see crypto.ScalarToBE32. It panics if x is negative or longer
+// than 32 bytes, which the callers rule out.
+func pad32(x *big.Int) []byte {
+	bz, err := crypto.ScalarToBE32(x, nil)
+	if err != nil {
+		panic(err)
+	}
+	return bz[:]
+}
@@ -0,0 +1,87 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 4
+	round.started = true
+	round.resetOK()
+
+	ec := round.Params().EC()
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	e := challenge(round.temp.bigR.X(), round.key.ECDSAPub.X(), round.temp.m)
+
+	// 1. check the share of every signer against its Rj and Wj: sj*G == Rj + e*Wj
+	s := round.temp.si
+	var multiErr error
+	culprits := make([]*tss.PartyID, 0, len(round.ok))
+	for j, Pj := range round.Parties().IDs() {
+		round.ok[j] = true
+		if j == round.PartyID().Index {
+			continue
+		}
+		sj := round.temp.signRound3Messages[j].Content().(*SignRound3Message).UnmarshalS()
+		if sj.Cmp(q) >= 0 {
+			multiErr = multierror.Append(multiErr, fmt.Errorf("the signature share of party %d is malformed", j))
+			culprits = append(culprits, Pj)
+			continue
+		}
+		expected, err := round.temp.bigRjs[j].Add(round.temp.bigWs[j].ScalarMult(e))
+		if err != nil || !crypto.ScalarBaseMult(ec, sj).Equal(expected) {
+			multiErr = multierror.Append(multiErr, fmt.Errorf("the signature share of party %d does not verify", j))
+			culprits = append(culprits, Pj)
+			continue
+		}
+		s = modQ.Add(s, sj)
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(multiErr, culprits...).WithMessageType(&SignRound3Message{})
+	}
+
+	// 2. the signature is bytes(R) || bytes(s)
+	rBz, sBz := pad32(round.temp.bigR.X()), pad32(s)
+	round.data.Signature = append(append(make([]byte, 0, 64), rBz...), sBz...)
+	round.data.R = rBz
+	round.data.S = sBz
+	round.data.M = round.temp.m
+
+	if !Verify(round.key.ECDSAPub, round.temp.m, round.data.Signature) {
+		return round.WrapError(errors.New("signature verification failed"))
+	}
+	round.end <- round.data
+
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -0,0 +1,194 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+// signMessage runs a signing session of all the `keys` and returns the signature, or the first error of a party.
+// `tamper` may replace the messages of the parties before they are delivered.
+func signMessage(keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, msg []byte,
+	tamper func(tss.Message) tss.Message) (*common.SignatureData, *tss.Error) {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			return nil, err
+		}
+	}
+
+	var data *common.SignatureData
+	for ended := 0; ended < len(signPIDs); {
+		select {
+		case err := <-errCh:
+			return nil, err
+		case msg := <-outCh:
+			if tamper != nil {
+				msg = tamper(msg)
+			}
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				go test.SharedPartyUpdater(P, msg, errCh)
+			}
+		case data = <-endCh:
+			ended++
+		}
+	}
+	return data, nil
+}
+
+// verifyReference checks a signature with the BIP-340 implementation of btcec
+func verifyReference(t *testing.T, pub *crypto.ECPoint, msg, sig []byte) {
+	pk, err := schnorr.ParsePubKey(XOnlyPubKey(pub))
+	if !assert.NoError(t, err) {
+		return
+	}
+	parsed, err := schnorr.ParseSignature(sig)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, parsed.Verify(msg, pk), "the BIP-340 reference verifier must accept the signature")
+}
+
+func TestE2EConcurrent(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pub := keys[0].ECDSAPub
+
+	// R has an odd y in about half of the sessions
+	for i := 0; i < 4; i++ {
+		msg := sha256.Sum256([]byte{byte(i)})
+		data, tssErr := signMessage(keys, signPIDs, msg[:], nil)
+		if tssErr != nil {
+			assert.FailNow(t, tssErr.Error())
+		}
+		assert.Len(t, data.Signature, 64)
+		assert.Equal(t, msg[:], data.M)
+		assert.True(t, Verify(pub, msg[:], data.Signature))
+		verifyReference(t, pub, msg[:], data.Signature)
+	}
+}
+
+func TestE2EOddAndEvenKeys(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	// the negated shares form a valid sharing of the negated key, whose y has the other parity
+	q := tss.S256().Params().N
+	negated := make([]keygen.LocalPartySaveData, len(keys))
+	for i, key := range keys {
+		negated[i] = key
+		negated[i].Xi = new(big.Int).Sub(q, key.Xi)
+		negated[i].BigXj = make([]*crypto.ECPoint, len(key.BigXj))
+		for j, bigXj := range key.BigXj {
+			negated[i].BigXj[j] = negate(bigXj)
+		}
+		negated[i].ECDSAPub = negate(key.ECDSAPub)
+	}
+	assert.NotEqual(t, hasEvenY(keys[0].ECDSAPub), hasEvenY(negated[0].ECDSAPub))
+
+	msg := sha256.Sum256([]byte("taproot"))
+	for _, set := range [][]keygen.LocalPartySaveData{keys, negated} {
+		data, tssErr := signMessage(set, signPIDs, msg[:], nil)
+		if tssErr != nil {
+			assert.FailNow(t, tssErr.Error())
+		}
+		verifyReference(t, set[0].ECDSAPub, msg[:], data.Signature)
+	}
+	assert.Equal(t, XOnlyPubKey(keys[0].ECDSAPub), XOnlyPubKey(negated[0].ECDSAPub), "both keys have the same x-only key")
+}
+
+func TestE2ESignatureShareCulprit(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	culprit := signPIDs[1]
+	tamper := func(msg tss.Message) tss.Message {
+		if msg.GetFrom().Index != culprit.Index || msg.Type() != "binance.tsslib.bip340.signing.SignRound3Message" {
+			return msg
+		}
+		si := msg.(tss.ParsedMessage).Content().(*SignRound3Message).UnmarshalS()
+		return NewSignRound3Message(culprit, new(big.Int).Add(si, big.NewInt(1)))
+	}
+	msg := sha256.Sum256([]byte("culprit"))
+	_, tssErr := signMessage(keys, signPIDs, msg[:], tamper)
+	if assert.NotNil(t, tssErr, "signing must abort") {
+		assert.Equal(t, []*tss.PartyID{culprit}, tssErr.Culprits(), tssErr.Error())
+		assert.Equal(t, "binance.tsslib.bip340.signing.SignRound3Message", tssErr.MessageType())
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ec := tss.S256()
+	sk := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+	pub := crypto.ScalarBaseMult(ec, sk)
+	msg := sha256.Sum256([]byte("message"))
+
+	// the reference signer negates an odd-y key itself
+	privKey, pubKey := btcec.PrivKeyFromBytes(pad32(sk))
+	sig, err := schnorr.Sign(privKey, msg[:])
+	assert.NoError(t, err)
+	sigBz := sig.Serialize()
+	assert.Equal(t, schnorr.SerializePubKey(pubKey), XOnlyPubKey(pub))
+	assert.True(t, Verify(pub, msg[:], sigBz))
+	assert.True(t, Verify(negate(pub), msg[:], sigBz), "only the x of the key is used")
+
+	other := sha256.Sum256([]byte("other message"))
+	assert.False(t, Verify(pub, other[:], sigBz))
+	for _, i := range []int{0, 31, 32, 63} {
+		tampered := append([]byte{}, sigBz...)
+		tampered[i] ^= 1
+		assert.False(t, Verify(pub, msg[:], tampered), "byte %d", i)
+	}
+	unreduced := append(append([]byte{}, sigBz[:32]...), pad32(ec.Params().N)...)
+	assert.False(t, Verify(pub, msg[:], unreduced), "s must be below n")
+	unreducedR := append(pad32(ec.Params().P), sigBz[32:]...)
+	assert.False(t, Verify(pub, msg[:], unreducedR), "r must be below p")
+	assert.False(t, Verify(crypto.ScalarBaseMult(ec, big.NewInt(42)), msg[:], sigBz))
+	assert.False(t, Verify(pub, msg[:], sigBz[:63]))
+	assert.False(t, Verify(nil, msg[:], sigBz))
+}
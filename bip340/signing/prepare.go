@@ -0,0 +1,50 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+)
+
+// PrepareForSigning returns the additive share wi of the key held by the signer i and the public shares Wj = wj*G of
+// all the signers, as for ECDSA signing
+func PrepareForSigning(ec elliptic.Curve, i, pax int, xi *big.Int, ks []*big.Int, bigXs []*crypto.ECPoint) (wi *big.Int, bigWs []*crypto.ECPoint) {
+	modQ := common.ModInt(ec.Params().N)
+	if len(ks) != len(bigXs) {
+		panic(fmt.Errorf("PrepareForSigning: len(ks) != len(bigXs) (%d != %d)", len(ks), len(bigXs)))
+	}
+	if len(ks) != pax {
+		panic(fmt.Errorf("PrepareForSigning: len(ks) != pax (%d != %d)", len(ks), pax))
+	}
+	if len(ks) <= i {
+		panic(fmt.Errorf("PrepareForSigning: len(ks) <= i (%d <= %d)", len(ks), i))
+	}
+
+	coefs, err := vss.LagrangeCoefficients(ec.Params().N, ks)
+	if err != nil {
+		panic(err)
+	}
+
+	// 1. wi = xi * prod_{j != i} kj / (kj - ki)
+	wi = new(big.Int).Set(xi)
+	if pax > 1 {
+		wi = modQ.Mul(xi, coefs[i])
+	}
+
+	// 2. the same coefficients applied to every Xj
+	bigWs = make([]*crypto.ECPoint, len(ks))
+	for j := 0; j < pax; j++ {
+		bigWs[j] = bigXs[j].ScalarMult(coefs[j])
+	}
+	return
+}
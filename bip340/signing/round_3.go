@@ -0,0 +1,168 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"math/big"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	// the nonce ri and the share wi are not needed after this round, whether it succeeds or not
+	defer func() {
+		common.ZeroizeBigInt(round.temp.ri)
+		common.ZeroizeBigInt(round.temp.wi)
+	}()
+
+	// 1-4. verify the Rj of the other parties and compute R
+	if err := round.computeR(); err != nil {
+		return err
+	}
+
+	// 5. BIP-340 uses the nonce whose R has an even y, so all the nonces are negated along with an odd R
+	q := round.Params().EC().Params().N
+	modQ := common.ModInt(q)
+	ri := round.temp.ri
+	if !hasEvenY(round.temp.bigR) {
+		ri = new(big.Int).Sub(q, ri)
+		defer common.ZeroizeBigInt(ri)
+		round.temp.bigR = negate(round.temp.bigR)
+		for j, bigRj := range round.temp.bigRjs {
+			round.temp.bigRjs[j] = negate(bigRj)
+		}
+	}
+
+	// 6. e = hash_BIP0340/challenge(bytes(R) || bytes(P) || m) mod n
+	e := challenge(round.temp.bigR.X(), round.key.ECDSAPub.X(), round.temp.m)
+
+	// 7. si = ri + e * wi mod n
+	si := modQ.Add(ri, modQ.Mul(e, round.temp.wi))
+	round.temp.si = si
+
+	// 8. broadcast si to other parties
+	r3msg := NewSignRound3Message(round.PartyID(), si)
+	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
+	round.out <- r3msg
+
+	return nil
+}
+
+// computeR verifies the de-commitments and proofs of the other parties' Rj and stores them with their sum R
+func (round *round3) computeR() *tss.Error {
+	i := round.PartyID().Index
+	Ps := round.Parties().IDs()
+	pairs := make([]commitments.HashCommitDecommit, 0, len(Ps)-1)
+	owners := make([]int, 0, len(Ps)-1)
+	for j := range Ps {
+		if j == i {
+			continue
+		}
+		r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
+		pairs = append(pairs, commitments.HashCommitDecommit{C: round.temp.cjs[j], D: r2msg.UnmarshalDeCommitment(), Ctx: round.temp.ssid})
+		owners = append(owners, j)
+	}
+	if results, err := commitments.BatchDeCommit(pairs); err != nil {
+		culprits := make([]*tss.PartyID, 0, len(Ps))
+		for k, ok := range results {
+			if !ok {
+				culprits = append(culprits, Ps[owners[k]])
+			}
+		}
+		return round.WrapError(commitments.ErrDeCommitVerify, culprits...).WithMessageType(&SignRound2Message{})
+	}
+
+	bigRjs := make([]*crypto.ECPoint, len(Ps))
+	bigRjs[i] = round.temp.pointRi
+	var multiErr error
+	culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+	for k, j := range owners {
+		Rj, err := round.verifyRj(j, pairs[k].D[1:]) // already de-committed; [1:] skips the randomness r
+		if err != nil {
+			multiErr = multierror.Append(multiErr, err)
+			culprits = append(culprits, Ps[j])
+			continue
+		}
+		bigRjs[j] = Rj
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(multiErr, culprits...).WithMessageType(&SignRound2Message{})
+	}
+
+	R := bigRjs[0]
+	for _, Rj := range bigRjs[1:] {
+		var err error
+		if R, err = R.Add(Rj); err != nil {
+			return round.WrapError(errors.Wrapf(err, "the nonce commitments sum to the point at infinity"))
+		}
+	}
+	round.temp.bigRjs = bigRjs
+	round.temp.bigR = R
+	return nil
+}
+
+// verifyRj decodes party j's Rj from its de-committed `coordinates` and verifies its proof of knowledge of rj
+func (round *round3) verifyRj(j int, coordinates []*big.Int) (*crypto.ECPoint, error) {
+	if len(coordinates) != 2 {
+		return nil, commitments.NewDeCommitArityError(2, len(coordinates))
+	}
+	Rj, err := crypto.NewECPoint(round.Params().EC(), coordinates[0], coordinates[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "NewECPoint(Rj)")
+	}
+	r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
+	proof, err := r2msg.UnmarshalZKProof(round.Params().EC())
+	if err != nil {
+		return nil, errors.New("failed to unmarshal Rj proof")
+	}
+	ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+	if ok := proof.Verify(ContextJ, Rj); !ok {
+		return nil, errors.New("failed to prove Rj")
+	}
+	return Rj, nil
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	ret := true
+	for j, msg := range round.temp.signRound3Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			ret = false
+			continue
+		}
+		round.ok[j] = true
+	}
+	return ret, nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound3Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round3) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
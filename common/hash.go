@@ -8,9 +8,11 @@ package common
 
 import (
 	"crypto"
-	_ "crypto/sha512"
+	"crypto/sha512"
 	"encoding/binary"
+	"hash"
 	"math/big"
+	"sync"
 )
 
 const (
@@ -95,48 +97,145 @@ func SHA512_256i(in ...*big.Int) *big.Int {
 
 // SHA512_256i_TAGGED tagged version of SHA512_256i
 func SHA512_256i_TAGGED(tag []byte, in ...*big.Int) *big.Int {
-	tagBz := SHA512_256(tag)
-	var data []byte
-	state := crypto.SHA512_256.New()
-	state.Write(tagBz)
-	state.Write(tagBz)
-	inLen := len(in)
-	if inLen == 0 {
+	if len(in) == 0 {
 		return nil
 	}
-	bzSize := 0
-	// prevent hash collisions with this prefix containing the block count
-	inLenBz := make([]byte, 64/8)
-	// converting between int and uint64 doesn't change the sign bit, but it may be interpreted as a larger value.
-	// this prefix is never read/interpreted, so that doesn't matter.
-	binary.LittleEndian.PutUint64(inLenBz, uint64(inLen))
-	ptrs := make([][]byte, inLen)
-	for i, n := range in {
-		if n == nil {
-			ptrs[i] = zero.Bytes()
-		} else {
-			ptrs[i] = n.Bytes()
+	h := taggedHasherPool.Get().(*taggedHasher)
+	defer taggedHasherPool.Put(h)
+	return h.sum(new(big.Int), tag, in)
+}
+
+// SHA512_256i_TAGGED_FE is SHA512_256i_TAGGED for inputs that are already encoded as field elements of a known size,
+// such as curve coordinates: `in` holds the inputs as consecutive big-endian elements of `width` bytes each, as filled
+// by big.Int.FillBytes. The result is identical to SHA512_256i_TAGGED of the non-negative values that they encode;
+// it returns nil if `in` is empty or not a whole number of elements.
+func SHA512_256i_TAGGED_FE(tag []byte, width int, in []byte) *big.Int {
+	if width <= 0 || len(in) == 0 || len(in)%width != 0 {
+		return nil
+	}
+	h := taggedHasherPool.Get().(*taggedHasher)
+	defer taggedHasherPool.Put(h)
+	return h.sumFE(new(big.Int), tag, width, in)
+}
+
+// TaggedHashi is SHA512_256i_TAGGED computed with the hash function `newHash` in place of SHA-512/256, e.g. SHA-256
+// or Keccak-256 for interoperability. The tag and the inputs are framed the same way. The hash must have 32-byte
+// digests; it returns nil otherwise or if `in` is empty.
+func TaggedHashi(newHash func() hash.Hash, tag []byte, in ...*big.Int) *big.Int {
+	if len(in) == 0 || newHash == nil {
+		return nil
+	}
+	h := &taggedHasher{state: newHash()}
+	if h.state.Size() != sha512.Size256 {
+		return nil
+	}
+	return h.sum(new(big.Int), tag, in)
+}
+
+// TaggedHashiFE is SHA512_256i_TAGGED_FE computed with the hash function `newHash`, see TaggedHashi. The result is
+// identical to TaggedHashi of the non-negative values that the elements encode.
+func TaggedHashiFE(newHash func() hash.Hash, tag []byte, width int, in []byte) *big.Int {
+	if width <= 0 || len(in) == 0 || len(in)%width != 0 || newHash == nil {
+		return nil
+	}
+	h := &taggedHasher{state: newHash()}
+	if h.state.Size() != sha512.Size256 {
+		return nil
+	}
+	return h.sumFE(new(big.Int), tag, width, in)
+}
+
+// taggedHasher holds a SHA-512/256 state and scratch space that SHA512_256i_TAGGED reuses through taggedHasherPool,
+// so that only its result is allocated
+type taggedHasher struct {
+	state   hash.Hash
+	data    []byte
+	tagHash [sha512.Size256]byte
+	digest  [sha512.Size256]byte
+}
+
+var taggedHasherPool = sync.Pool{
+	New: func() interface{} { return &taggedHasher{state: sha512.New512_256()} },
+}
+
+// sum sets dst to the output of SHA512_256i_TAGGED(tag, in...) and returns it. The input is framed exactly as in
+// SHA512_256 and SHA512_256i: the input count, then every input followed by the delimiter and its length, with a nil
+// input hashed as zero.
+func (h *taggedHasher) sum(dst *big.Int, tag []byte, in []*big.Int) *big.Int {
+	// the tag is hashed as SHA512_256(tag)
+	h.hashTag(tag)
+
+	h.data = appendUint64(h.data[:0], uint64(len(in)))
+	for _, n := range in {
+		size := 0
+		if n != nil {
+			size = (n.BitLen() + 7) / 8
 		}
-		bzSize += len(ptrs[i])
+		start := len(h.data)
+		h.data = growBytes(h.data, size)
+		if n != nil {
+			n.FillBytes(h.data[start:])
+		}
+		h.data = append(h.data, hashInputDelimiter)
+		h.data = appendUint64(h.data, uint64(size))
 	}
-	dataCap := len(inLenBz) + bzSize + inLen + (inLen * 8)
-	data = make([]byte, 0, dataCap)
-	data = append(data, inLenBz...)
-	for i := range in {
-		data = append(data, ptrs[i]...)
-		data = append(data, hashInputDelimiter) // safety delimiter
-		dataLen := make([]byte, 8)              // 64-bits
-		binary.LittleEndian.PutUint64(dataLen, uint64(len(ptrs[i])))
-		data = append(data, dataLen...) // Security audit: length of each byte buffer should be added after
-		// each security delimiters in order to enforce proper domain separation
+	return h.finish(dst)
+}
+
+// sumFE sets dst to the output of SHA512_256i_TAGGED_FE(tag, width, in) and returns it. Every element is framed by
+// the bytes that follow its leading zeros, which are the bytes that big.Int.Bytes returns for its value.
+func (h *taggedHasher) sumFE(dst *big.Int, tag []byte, width int, in []byte) *big.Int {
+	h.hashTag(tag)
+	h.data = appendUint64(h.data[:0], uint64(len(in)/width))
+	for start := 0; start < len(in); start += width {
+		elem := in[start : start+width]
+		for len(elem) > 0 && elem[0] == 0 {
+			elem = elem[1:]
+		}
+		h.data = append(h.data, elem...)
+		h.data = append(h.data, hashInputDelimiter)
+		h.data = appendUint64(h.data, uint64(len(elem)))
 	}
+	return h.finish(dst)
+}
+
+// hashTag sets h.tagHash to SHA512_256(tag)
+func (h *taggedHasher) hashTag(tag []byte) {
+	h.data = appendUint64(h.data[:0], 1)
+	h.data = append(h.data, tag...)
+	h.data = append(h.data, hashInputDelimiter)
+	h.data = appendUint64(h.data, uint64(len(tag)))
+	h.state.Reset()
+	h.state.Write(h.data)
+	h.state.Sum(h.tagHash[:0])
+}
+
+// finish hashes the tag hash twice followed by the framed inputs in h.data and sets dst to the digest
+func (h *taggedHasher) finish(dst *big.Int) *big.Int {
+	h.state.Reset()
+	h.state.Write(h.tagHash[:])
+	h.state.Write(h.tagHash[:])
 	// n < len(data) or an error will never happen.
 	// see: https://golang.org/pkg/hash/#Hash and https://github.com/golang/go/wiki/Hashing#the-hashhash-interface
-	if _, err := state.Write(data); err != nil {
-		Logger.Error(err)
-		return nil
+	h.state.Write(h.data)
+	h.state.Sum(h.digest[:0])
+	return dst.SetBytes(h.digest[:])
+}
+
+func appendUint64(bz []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(bz, buf[:]...)
+}
+
+// growBytes extends bz by n bytes, reusing its capacity when it suffices
+func growBytes(bz []byte, n int) []byte {
+	if len(bz)+n <= cap(bz) {
+		return bz[:len(bz)+n]
 	}
-	return new(big.Int).SetBytes(state.Sum(nil))
+	grown := make([]byte, len(bz)+n, 2*(len(bz)+n))
+	copy(grown, bz)
+	return grown
 }
 
 func SHA512_256iOne(in *big.Int) *big.Int {
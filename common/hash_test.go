@@ -0,0 +1,178 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSHA512_256i_TAGGEDUnchanged(t *testing.T) {
+	p, _ := new(big.Int).SetString("57896044618658097711785492504343953926634992332820282019728792003956564819949", 10)
+	// digests of the implementation that serialized every input with big.Int.Bytes
+	for _, tc := range []struct {
+		tag      []byte
+		in       []*big.Int
+		expected string
+	}{
+		{[]byte("tss-lib/golden"), []*big.Int{big.NewInt(0), nil, big.NewInt(1), p, big.NewInt(-300)},
+			"b40c312ed4b09c8923effb91c4058c5a2422a629a00afdd2c0c88fc45d16f7a7"},
+		{nil, []*big.Int{big.NewInt(42)}, "4321946cb1bfb7c957561a77ecdbcb1f327238541bdf549f4105844597bc31f0"},
+	} {
+		assert.Equal(t, tc.expected, hex.EncodeToString(SHA512_256i_TAGGED(tc.tag, tc.in...).Bytes()))
+	}
+	assert.Nil(t, SHA512_256i_TAGGED([]byte("tag")))
+}
+
+func TestSHA512_256i_TAGGEDMatchesUntaggedFraming(t *testing.T) {
+	// the tagged hash is the SHA512_256i framing of the inputs' Bytes, prefixed with SHA512_256(tag) twice
+	tag := []byte("framing")
+	in := []*big.Int{MustGetRandomInt(rand.Reader, 256), big.NewInt(7), MustGetRandomInt(rand.Reader, 2048)}
+	tagBz := SHA512_256(tag)
+	bzs := make([][]byte, len(in))
+	for i, n := range in {
+		bzs[i] = n.Bytes()
+	}
+	h := taggedHasherPool.Get().(*taggedHasher)
+	defer taggedHasherPool.Put(h)
+	h.state.Reset()
+	h.state.Write(tagBz)
+	h.state.Write(tagBz)
+	h.data = appendUint64(h.data[:0], uint64(len(bzs)))
+	for _, bz := range bzs {
+		h.data = append(h.data, bz...)
+		h.data = append(h.data, hashInputDelimiter)
+		h.data = appendUint64(h.data, uint64(len(bz)))
+	}
+	h.state.Write(h.data)
+	expected := new(big.Int).SetBytes(h.state.Sum(nil))
+	assert.Equal(t, 0, expected.Cmp(SHA512_256i_TAGGED(tag, in...)))
+}
+
+func TestSHA512_256i_TAGGEDConcurrent(t *testing.T) {
+	in := []*big.Int{MustGetRandomInt(rand.Reader, 256), MustGetRandomInt(rand.Reader, 512)}
+	expected := SHA512_256i_TAGGED([]byte("concurrent"), in...)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.Equal(t, 0, expected.Cmp(SHA512_256i_TAGGED([]byte("concurrent"), in...)))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTaggedHasherDoesNotAllocate(t *testing.T) {
+	tag := []byte("allocs")
+	in := []*big.Int{MustGetRandomInt(rand.Reader, 256), MustGetRandomInt(rand.Reader, 256), nil}
+	h := &taggedHasher{state: taggedHasherPool.New().(*taggedHasher).state}
+	dst := new(big.Int)
+	h.sum(dst, tag, in) // warm up the scratch space and dst
+	allocs := testing.AllocsPerRun(100, func() {
+		h.sum(dst, tag, in)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestSHA512_256i_TAGGED_FEMatchesTagged(t *testing.T) {
+	const width = 32
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 8*width), big.NewInt(1))
+	for _, in := range [][]*big.Int{
+		{MustGetRandomInt(rand.Reader, 256), MustGetRandomInt(rand.Reader, 256)},
+		{big.NewInt(0)},
+		{big.NewInt(1), big.NewInt(0x100), max},
+		// the leading zero bytes of the encodings are not hashed
+		{MustGetRandomInt(rand.Reader, 8), MustGetRandomInt(rand.Reader, 130), big.NewInt(0), MustGetRandomInt(rand.Reader, 249)},
+	} {
+		encoded := make([]byte, width*len(in))
+		for i, n := range in {
+			n.FillBytes(encoded[i*width : (i+1)*width])
+		}
+		for _, tag := range [][]byte{nil, []byte("fe")} {
+			expected := SHA512_256i_TAGGED(tag, in...)
+			assert.Equal(t, 0, expected.Cmp(SHA512_256i_TAGGED_FE(tag, width, encoded)), "%v", in)
+		}
+	}
+	assert.Nil(t, SHA512_256i_TAGGED_FE([]byte("tag"), width, nil))
+	assert.Nil(t, SHA512_256i_TAGGED_FE([]byte("tag"), width, make([]byte, width+1)), "a partial element")
+	assert.Nil(t, SHA512_256i_TAGGED_FE([]byte("tag"), 0, make([]byte, width)))
+}
+
+func TestTaggedHashi(t *testing.T) {
+	tag := []byte("tag")
+	in := []*big.Int{big.NewInt(1), nil, new(big.Int).Lsh(big.NewInt(1), 255)}
+	assert.Equal(t, SHA512_256i_TAGGED(tag, in...), TaggedHashi(sha512.New512_256, tag, in...))
+	withSHA256 := TaggedHashi(sha256.New, tag, in...)
+	assert.NotNil(t, withSHA256)
+	assert.NotEqual(t, SHA512_256i_TAGGED(tag, in...), withSHA256)
+
+	encoded := make([]byte, 3*32)
+	in[2].FillBytes(encoded[64:])
+	big.NewInt(1).FillBytes(encoded[:32])
+	assert.Equal(t, withSHA256, TaggedHashiFE(sha256.New, tag, 32, encoded))
+
+	assert.Nil(t, TaggedHashi(sha512.New, tag, in...), "a hash with 64-byte digests")
+	assert.Nil(t, TaggedHashi(sha256.New, tag))
+	assert.Nil(t, TaggedHashiFE(sha256.New, tag, 32, encoded[1:]))
+}
+
+func BenchmarkSHA512_256i_TAGGED(b *testing.B) {
+	tag := []byte("benchmark")
+	in := make([]*big.Int, 8)
+	for i := range in {
+		in[i] = MustGetRandomInt(rand.Reader, 256)
+	}
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			SHA512_256i_TAGGED(tag, in...)
+		}
+	})
+	b.Run("reused hasher", func(b *testing.B) {
+		h := &taggedHasher{state: taggedHasherPool.New().(*taggedHasher).state}
+		dst := new(big.Int)
+		h.sum(dst, tag, in)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			h.sum(dst, tag, in)
+		}
+	})
+}
+
+func BenchmarkSHA512_256i_TAGGED_FE(b *testing.B) {
+	const width = 32
+	tag := []byte("benchmark")
+	in := make([]*big.Int, 8)
+	encoded := make([]byte, width*len(in))
+	for i := range in {
+		in[i] = MustGetRandomInt(rand.Reader, 255)
+		in[i].FillBytes(encoded[i*width : (i+1)*width])
+	}
+	b.Run("big.Int", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			SHA512_256i_TAGGED(tag, in...)
+		}
+	})
+	b.Run("field elements", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			SHA512_256i_TAGGED_FE(tag, width, encoded)
+		}
+	})
+}
@@ -7,12 +7,73 @@
 package common
 
 import (
+	"encoding/binary"
+	"errors"
 	"math/big"
 )
 
 // RejectionSample implements the rejection sampling logic for converting a
-// SHA512/256 hash to a value between 0-q
+// SHA512/256 hash to a value between 0-q. It returns nil where RejectionSampleChecked fails.
 func RejectionSample(q *big.Int, eHash *big.Int) *big.Int { // e' = eHash
-	e := eHash.Mod(eHash, q)
+	e, err := RejectionSampleChecked(q, eHash)
+	if err != nil {
+		return nil
+	}
 	return e
 }
+
+// RejectionSampleChecked converts the hash eHash to a value strictly in [0, q). It fails if q is nil or not above 1,
+// or if eHash is nil or negative; eHash is not modified.
+//
+// Despite its name it never rejects: the value is eHash reduced mod q in a single step, so it takes exactly one
+// iteration whatever q is. For a hash uniform in [0, 2^L) the result is off uniform by a statistical distance of at
+// most (2^L mod q) / 2^L: below 2^-127 for the secp256k1 order and a 256-bit hash, but up to 2^-4 for the ed25519
+// order. RejectionSampleN returns uniform values and expects fewer than 2 attempts per value.
+func RejectionSampleChecked(q *big.Int, eHash *big.Int) (*big.Int, error) {
+	if q == nil || q.Cmp(one) <= 0 {
+		return nil, errors.New("RejectionSampleChecked(): q must be greater than 1")
+	}
+	if eHash == nil || eHash.Sign() < 0 {
+		return nil, errors.New("RejectionSampleChecked(): the hash must be a non-negative integer")
+	}
+	return new(big.Int).Mod(eHash, q), nil
+}
+
+// rejectionSampleNTag separates the hashes of RejectionSampleN from every other use of SHA512_256
+var rejectionSampleNTag = []byte("tss-lib.RejectionSampleN")
+
+// RejectionSampleN expands `seed` into n independent values uniformly distributed in [0, q). Value i is derived by
+// hashing, for the attempts c = 0, 1, ..., blocks of SHA512_256(tag, seed, i, c, b) until they hold q.BitLen() bits;
+// the excess high bits are masked off and the attempt is rejected if the result is not below q, so each attempt
+// succeeds with probability above 1/2. The tag, the seed and the 4-byte big-endian counters are separate inputs of
+// SHA512_256, which length-prefixes each of them, so the derivation cannot collide with other hashes of the seed.
+// It returns nil if q is not positive or n is negative.
+func RejectionSampleN(q *big.Int, seed []byte, n int) []*big.Int {
+	if q == nil || q.Sign() <= 0 || n < 0 {
+		return nil
+	}
+	bitLen := q.BitLen()
+	byteLen := (bitLen + 7) / 8
+	mask := byte(0xff >> (8*byteLen - bitLen))
+	out := make([]*big.Int, n)
+	buf := make([]byte, 0, byteLen+32)
+	for i := range out {
+		for c := uint32(0); out[i] == nil; c++ {
+			buf = buf[:0]
+			for b := uint32(0); len(buf) < byteLen; b++ {
+				buf = append(buf, SHA512_256(rejectionSampleNTag, seed, uint32Bytes(uint32(i)), uint32Bytes(c), uint32Bytes(b))...)
+			}
+			buf[0] &= mask
+			if v := new(big.Int).SetBytes(buf[:byteLen]); v.Cmp(q) < 0 {
+				out[i] = v
+			}
+		}
+	}
+	return out
+}
+
+func uint32Bytes(v uint32) []byte {
+	bz := make([]byte, 4)
+	binary.BigEndian.PutUint32(bz, v)
+	return bz
+}
@@ -12,7 +12,10 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 func TestRejectionSample(t *testing.T) {
@@ -61,3 +64,120 @@ func TestRejectionSample(t *testing.T) {
 		})
 	}
 }
+
+func TestRejectionSampleChecked(t *testing.T) {
+	q := tss.S256().Params().N
+	hash := common.SHA512_256iOne(big.NewInt(123))
+	hashCopy := new(big.Int).Set(hash)
+	e, err := common.RejectionSampleChecked(q, hash)
+	if assert.NoError(t, err) {
+		assert.True(t, e.Sign() >= 0 && e.Cmp(q) < 0, "the value must be in [0, q)")
+		assert.Equal(t, 0, e.Cmp(new(big.Int).Mod(hashCopy, q)))
+		assert.Equal(t, 0, e.Cmp(common.RejectionSample(q, hash)), "the old function must agree")
+	}
+	assert.Equal(t, 0, hash.Cmp(hashCopy), "the hash must not be modified")
+
+	// the hash equal to q and the zero hash both sample 0
+	for _, h := range []*big.Int{new(big.Int).Set(q), big.NewInt(0)} {
+		e, err = common.RejectionSampleChecked(q, h)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, e.Sign())
+	}
+	e, err = common.RejectionSampleChecked(big.NewInt(2), hash)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, e.Cmp(big.NewInt(int64(hash.Bit(0)))))
+}
+
+func TestRejectionSampleCheckedRejectsBadInput(t *testing.T) {
+	hash := common.SHA512_256iOne(big.NewInt(123))
+	for name, q := range map[string]*big.Int{
+		"nil":      nil,
+		"negative": big.NewInt(-7),
+		"zero":     big.NewInt(0),
+		"one":      big.NewInt(1),
+	} {
+		_, err := common.RejectionSampleChecked(q, hash)
+		assert.Error(t, err, "q %s", name)
+		assert.Nil(t, common.RejectionSample(q, hash), "q %s", name)
+	}
+	// a degenerate hash, e.g. SHA512_256i_TAGGED of no inputs
+	q := tss.Edwards().Params().N
+	for name, h := range map[string]*big.Int{
+		"nil":      common.SHA512_256i_TAGGED([]byte("tag")),
+		"negative": big.NewInt(-1),
+	} {
+		_, err := common.RejectionSampleChecked(q, h)
+		assert.Error(t, err, "hash %s", name)
+		assert.Nil(t, common.RejectionSample(q, h), "hash %s", name)
+	}
+}
+
+func TestRejectionSampleNDeterministic(t *testing.T) {
+	q := tss.Edwards().Params().N
+	seed := []byte("seed")
+	values := common.RejectionSampleN(q, seed, 5)
+	assert.Len(t, values, 5)
+	assert.Equal(t, values, common.RejectionSampleN(q, seed, 5), "the values must only depend on the seed")
+	assert.Equal(t, values[:3], common.RejectionSampleN(q, seed, 3), "fewer values must be a prefix")
+	for i, v := range values {
+		assert.True(t, v.Sign() >= 0 && v.Cmp(q) < 0)
+		for _, w := range values[:i] {
+			assert.NotEqual(t, 0, v.Cmp(w), "the values must be independent")
+		}
+	}
+	other := common.RejectionSampleN(q, []byte("seed2"), 5)
+	for i := range values {
+		assert.NotEqual(t, values[i], other[i])
+	}
+
+	// a known answer, so that the derivation does not change unnoticed
+	assert.Equal(t, "4dea82f965056706bf9a4d239054744ed432696ed3e0a53e01bfaf2615ad53e", values[0].Text(16))
+	assert.Equal(t, "8fbacd1b78cdaf43d2c00c3b0ff3cfedbeba09f13513ac82c99ed9fc2bfcf8f", values[1].Text(16))
+}
+
+func TestRejectionSampleNBounds(t *testing.T) {
+	// a q just above a power of two rejects almost half of the attempts, and a 2048-bit q takes several hash blocks
+	for _, q := range []*big.Int{
+		big.NewInt(1),
+		big.NewInt(2),
+		new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)),
+		common.MustGetRandomInt(rand.Reader, 2048),
+	} {
+		values := common.RejectionSampleN(q, []byte("bounds"), 64)
+		assert.Len(t, values, 64)
+		maxBitLen := 0
+		for _, v := range values {
+			assert.True(t, v.Sign() >= 0 && v.Cmp(q) < 0, "the values must be in [0, q)")
+			if v.BitLen() > maxBitLen {
+				maxBitLen = v.BitLen()
+			}
+		}
+		if q.BitLen() > 64 {
+			assert.True(t, maxBitLen > q.BitLen()-8, "the values must cover the whole range")
+		}
+	}
+	assert.Empty(t, common.RejectionSampleN(big.NewInt(7), []byte("seed"), 0))
+	assert.Nil(t, common.RejectionSampleN(big.NewInt(0), []byte("seed"), 1))
+	assert.Nil(t, common.RejectionSampleN(nil, []byte("seed"), 1))
+	assert.Nil(t, common.RejectionSampleN(big.NewInt(7), []byte("seed"), -1))
+}
+
+func TestRejectionSampleNUniform(t *testing.T) {
+	// chi-squared goodness of fit against the uniform distribution; the seed is fixed, so the test is deterministic
+	for _, bins := range []int64{3, 10, 37} {
+		const samples = 20000
+		counts := make([]int, bins)
+		for _, v := range common.RejectionSampleN(big.NewInt(bins), []byte("uniformity"), samples) {
+			counts[v.Int64()]++
+		}
+		expected := float64(samples) / float64(bins)
+		chi2 := 0.0
+		for _, count := range counts {
+			d := float64(count) - expected
+			chi2 += d * d / expected
+		}
+		// the 0.999 quantiles of the chi-squared distribution with bins-1 degrees of freedom
+		limit := map[int64]float64{3: 13.82, 10: 27.88, 37: 67.99}[bins]
+		assert.Less(t, chi2, limit, "%d bins: %v", bins, counts)
+	}
+}
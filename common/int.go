@@ -19,6 +19,9 @@ var (
 	two  = big.NewInt(2)
 )
 
+// ModInt returns the modular-arithmetic view of `mod`. It is a conversion of the pointer, so it does not allocate and
+// calling it in a loop costs nothing; only the results of the operations are allocated. The methods only read the
+// modulus, so one value may be used from several goroutines as long as nobody modifies `mod`.
 func ModInt(mod *big.Int) *modInt {
 	return (*modInt)(mod)
 }
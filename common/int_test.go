@@ -0,0 +1,74 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestModIntConcurrent(t *testing.T) {
+	q := common.MustGetRandomInt(rand.Reader, 256)
+	modQ := common.ModInt(q)
+	xs := make([]*big.Int, 64)
+	expected := make([]*big.Int, len(xs))
+	for i := range xs {
+		xs[i] = common.GetRandomPositiveRelativelyPrimeInt(rand.Reader, q)
+		expected[i] = new(big.Int).Mod(new(big.Int).Mul(xs[i], xs[i]), q)
+		expected[i].Add(expected[i], new(big.Int).ModInverse(xs[i], q)).Mod(expected[i], q)
+	}
+	qCopy := new(big.Int).Set(q)
+
+	var wg sync.WaitGroup
+	results := make([][]*big.Int, 8)
+	for g := range results {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			results[g] = make([]*big.Int, len(xs))
+			for i, x := range xs {
+				results[g][i] = modQ.Add(modQ.Mul(x, x), modQ.ModInverse(x))
+			}
+		}(g)
+	}
+	wg.Wait()
+	for _, result := range results {
+		assert.Equal(t, expected, result)
+	}
+	assert.Equal(t, qCopy, q, "the modulus must not be modified")
+}
+
+func BenchmarkModInt(b *testing.B) {
+	q := common.MustGetRandomInt(rand.Reader, 256)
+	x := common.MustGetRandomInt(rand.Reader, 256)
+	y := common.MustGetRandomInt(rand.Reader, 256)
+	b.Run("ModInt", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = common.ModInt(q)
+		}
+	})
+	b.Run("Mul", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = common.ModInt(q).Mul(x, y)
+		}
+	})
+	b.Run("MulHoisted", func(b *testing.B) {
+		modQ := common.ModInt(q)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = modQ.Mul(x, y)
+		}
+	})
+}
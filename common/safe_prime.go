@@ -35,6 +35,19 @@ func (sgp *GermainSafePrime) SafePrime() *big.Int {
 	return sgp.p
 }
 
+// NewGermainSafePrime returns the pair of the Sophie Germain prime q and the safe prime 2q + 1, checking that both
+// are prime
+func NewGermainSafePrime(q *big.Int) (*GermainSafePrime, error) {
+	if q == nil {
+		return nil, errors.New("NewGermainSafePrime() received a nil prime")
+	}
+	sgp := &GermainSafePrime{q: q, p: getSafePrime(q)}
+	if !sgp.Validate() {
+		return nil, fmt.Errorf("NewGermainSafePrime(): %s is not a Sophie Germain prime", q)
+	}
+	return sgp, nil
+}
+
 func (sgp *GermainSafePrime) Validate() bool {
 	return probablyPrime(sgp.q) &&
 		getSafePrime(sgp.q).Cmp(sgp.p) == 0 &&
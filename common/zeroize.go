@@ -0,0 +1,34 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"math/big"
+	"runtime"
+)
+
+// Zeroize overwrites b with zeros so that a secret does not linger in memory after use
+func Zeroize(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+	// keep the writes from being optimised away as dead stores
+	runtime.KeepAlive(b)
+}
+
+// ZeroizeBigInt overwrites the words backing x with zeros and sets x to 0. Copies of x made earlier are not affected.
+func ZeroizeBigInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	runtime.KeepAlive(words)
+	x.SetInt64(0)
+}
@@ -0,0 +1,34 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestZeroize(t *testing.T) {
+	b := []byte{1, 2, 3, 4}
+	common.Zeroize(b[1:])
+	assert.Equal(t, []byte{1, 0, 0, 0}, b)
+	common.Zeroize(nil)
+}
+
+func TestZeroizeBigInt(t *testing.T) {
+	x := common.MustGetRandomInt(rand.Reader, 256)
+	words := x.Bits()
+	common.ZeroizeBigInt(x)
+	assert.Equal(t, 0, x.Sign())
+	for _, w := range words {
+		assert.Zero(t, w, "the backing words must be zero")
+	}
+	common.ZeroizeBigInt(nil)
+}
@@ -0,0 +1,60 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package commitments
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+const (
+	// batches smaller than this are verified on the calling goroutine
+	batchParallelThreshold = 8
+)
+
+// BatchDeCommit verifies many commitments at once and returns whether each one holds, index by index, so that a bad
+// commitment can still be attributed to its party. Hash commitments cannot be folded together, so the hashes are
+// spread over the available CPUs instead. The error is nil when all the commitments hold, in which case the results
+// need not be inspected; otherwise it lists the failing indices and matches ErrDeCommitVerify.
+func BatchDeCommit(pairs []HashCommitDecommit) ([]bool, error) {
+	results := make([]bool, len(pairs))
+	workers := runtime.GOMAXPROCS(0)
+	if len(pairs) < batchParallelThreshold || workers < 2 {
+		for j := range pairs {
+			results[j] = pairs[j].Verify()
+		}
+	} else {
+		var wg sync.WaitGroup
+		chunk := (len(pairs) + workers - 1) / workers
+		for lo := 0; lo < len(pairs); lo += chunk {
+			hi := lo + chunk
+			if hi > len(pairs) {
+				hi = len(pairs)
+			}
+			wg.Add(1)
+			go func(lo, hi int) {
+				defer wg.Done()
+				for j := lo; j < hi; j++ {
+					results[j] = pairs[j].Verify()
+				}
+			}(lo, hi)
+		}
+		wg.Wait()
+	}
+
+	var failed []int
+	for j, ok := range results {
+		if !ok {
+			failed = append(failed, j)
+		}
+	}
+	if len(failed) > 0 {
+		return results, &deCommitError{kind: ErrDeCommitVerify, msg: fmt.Sprintf("de-commitment verify failed at indices %v", failed)}
+	}
+	return results, nil
+}
@@ -0,0 +1,106 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package commitments_test
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+)
+
+func newCommitments(n int) []HashCommitDecommit {
+	pairs := make([]HashCommitDecommit, n)
+	for j := range pairs {
+		pairs[j] = *NewHashCommitment(rand.Reader, big.NewInt(int64(j)), big.NewInt(int64(j+1)))
+	}
+	return pairs
+}
+
+func TestBatchDeCommit(t *testing.T) {
+	// both below and above the size at which the batch is spread over goroutines
+	for _, n := range []int{1, 5, 64} {
+		results, err := BatchDeCommit(newCommitments(n))
+		assert.NoError(t, err)
+		assert.Len(t, results, n)
+		for _, ok := range results {
+			assert.True(t, ok)
+		}
+	}
+	results, err := BatchDeCommit(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestBatchDeCommitCorrupted(t *testing.T) {
+	for _, n := range []int{5, 64} {
+		for _, bad := range [][]int{{0}, {n - 1}, {1, 3}, {0, 2, 4}} {
+			t.Run(fmt.Sprintf("%d/%v", n, bad), func(t *testing.T) {
+				pairs := newCommitments(n)
+				for k, j := range bad {
+					switch k % 3 {
+					case 0: // a secret changed
+						pairs[j].D = append(HashDeCommitment(nil), pairs[j].D...)
+						pairs[j].D[1] = new(big.Int).Add(pairs[j].D[1], big.NewInt(1))
+					case 1: // the commitment changed
+						pairs[j].C = new(big.Int).Add(pairs[j].C, big.NewInt(1))
+					default: // the de-commitment is missing
+						pairs[j].D = nil
+					}
+				}
+				results, err := BatchDeCommit(pairs)
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, ErrDeCommitVerify))
+				assert.False(t, errors.Is(err, ErrDeCommitArity))
+				var failed []int
+				for j, ok := range results {
+					if !ok {
+						failed = append(failed, j)
+					}
+				}
+				assert.Equal(t, bad, failed)
+			})
+		}
+	}
+}
+
+func TestDeCommitArityError(t *testing.T) {
+	err := NewDeCommitArityError(2, 3)
+	assert.True(t, errors.Is(err, ErrDeCommitArity))
+	assert.True(t, errors.Is(fmt.Errorf("round 3: %w", err), ErrDeCommitArity))
+	assert.False(t, errors.Is(err, ErrDeCommitVerify))
+	assert.Equal(t, "length of de-commitment should be 2, got 3", err.Error())
+}
+
+func TestValidateDeCommitment(t *testing.T) {
+	bound := big.NewInt(100)
+	r := big.NewInt(12345)
+	assert.NoError(t, ValidateDeCommitment(HashDeCommitment{r, big.NewInt(0), big.NewInt(99)}, 2, bound))
+
+	for name, tc := range map[string]struct {
+		D    HashDeCommitment
+		kind error
+	}{
+		"empty":               {nil, ErrDeCommitArity},
+		"randomness only":     {HashDeCommitment{r}, ErrDeCommitArity},
+		"too few secrets":     {HashDeCommitment{r, big.NewInt(1)}, ErrDeCommitArity},
+		"too many secrets":    {HashDeCommitment{r, big.NewInt(1), big.NewInt(2), big.NewInt(3)}, ErrDeCommitArity},
+		"secret = bound":      {HashDeCommitment{r, big.NewInt(1), big.NewInt(100)}, ErrDeCommitRange},
+		"oversized secret":    {HashDeCommitment{r, new(big.Int).Lsh(big.NewInt(1), 300), big.NewInt(1)}, ErrDeCommitRange},
+		"negative secret":     {HashDeCommitment{r, big.NewInt(-1), big.NewInt(1)}, ErrDeCommitRange},
+		"nil secret":          {HashDeCommitment{r, nil, big.NewInt(1)}, ErrDeCommitRange},
+		"negative randomness": {HashDeCommitment{big.NewInt(-1), big.NewInt(1), big.NewInt(2)}, ErrDeCommitRange},
+	} {
+		err := ValidateDeCommitment(tc.D, 2, bound)
+		assert.True(t, errors.Is(err, tc.kind), "%s: %v", name, err)
+	}
+}
@@ -27,6 +27,11 @@ type (
 	HashCommitDecommit struct {
 		C HashCommitment
 		D HashDeCommitment
+		// Ctx is the context, e.g. a session id, that the commitment is bound to; nil for an unbound commitment. A
+		// bound commitment only verifies with the same context, so it cannot be replayed in another session.
+		Ctx []byte
+		// Hasher is the hash function of the commitment; nil for SHA512_256Hasher
+		Hasher Hasher
 	}
 )
 
@@ -49,6 +54,18 @@ func NewHashCommitment(rand io.Reader, secrets ...*big.Int) *HashCommitDecommit
 	return NewHashCommitmentWithRandomness(r, secrets...)
 }
 
+// NewHashCommitmentWithContext returns a commitment to the secrets that is bound to `ctx`: the context is mixed into
+// the hash, and the de-commitment must be verified with the same context.
+func NewHashCommitmentWithContext(rand io.Reader, ctx []byte, secrets ...*big.Int) *HashCommitDecommit {
+	if ctx == nil {
+		ctx = []byte{}
+	}
+	cmt := NewHashCommitment(rand, secrets...)
+	cmt.Ctx = ctx
+	cmt.C = common.SHA512_256i_TAGGED(ctx, cmt.D...)
+	return cmt
+}
+
 func NewHashDeCommitmentFromBytes(marshalled [][]byte) HashDeCommitment {
 	return common.MultiBytesToBigInts(marshalled)
 }
@@ -58,8 +75,12 @@ func (cmt *HashCommitDecommit) Verify() bool {
 	if C == nil || D == nil {
 		return false
 	}
-	hash := common.SHA512_256i(D...)
-	return hash.Cmp(C) == 0
+	hasher := cmt.Hasher
+	if hasher == nil {
+		hasher = SHA512_256Hasher
+	}
+	hash, err := hasher.Hash(cmt.Ctx, D)
+	return err == nil && hash.Cmp(C) == 0
 }
 
 func (cmt *HashCommitDecommit) DeCommit() (bool, HashDeCommitment) {
@@ -37,3 +37,24 @@ func TestDeCommit(t *testing.T) {
 
 	assert.NotZero(t, len(secrets), "len(secrets) must be non-zero")
 }
+
+func TestHashCommitmentWithContext(t *testing.T) {
+	one := big.NewInt(1)
+	zero := big.NewInt(0)
+
+	commitment := NewHashCommitmentWithContext(rand.Reader, []byte("session 1"), zero, one)
+	ok, secrets := commitment.DeCommit()
+	assert.True(t, ok, "must pass with the same context")
+	assert.Equal(t, []*big.Int{zero, one}, []*big.Int(secrets))
+
+	replayed := HashCommitDecommit{C: commitment.C, D: commitment.D, Ctx: []byte("session 2")}
+	ok, _ = replayed.DeCommit()
+	assert.False(t, ok, "must fail with another context")
+	unbound := HashCommitDecommit{C: commitment.C, D: commitment.D}
+	ok, _ = unbound.DeCommit()
+	assert.False(t, ok, "must fail without a context")
+
+	plain := NewHashCommitment(rand.Reader, zero, one)
+	bound := HashCommitDecommit{C: plain.C, D: plain.D, Ctx: []byte("session 1")}
+	assert.False(t, bound.Verify(), "an unbound commitment must not verify with a context")
+}
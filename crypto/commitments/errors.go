@@ -0,0 +1,65 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package commitments
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The errors of a failed de-commitment, for callers that handle them programmatically. The protocol rounds wrap
+// them in a tss.Error, so that errors.Is(err, ErrDeCommitVerify) matches the error of a party.
+var (
+	// ErrDeCommitVerify means that a de-commitment does not open its commitment
+	ErrDeCommitVerify = errors.New("de-commitment verify failed")
+
+	// ErrDeCommitArity means that a de-commitment opens its commitment but holds a different number of secrets than
+	// the protocol expects
+	ErrDeCommitArity = errors.New("de-commitment has the wrong number of secrets")
+
+	// ErrDeCommitRange means that a de-commitment holds a secret outside of the range that the protocol expects
+	ErrDeCommitRange = errors.New("de-commitment holds a secret out of range")
+)
+
+// deCommitError keeps a detailed message while matching one of the errors above
+type deCommitError struct {
+	kind error
+	msg  string
+}
+
+func (err *deCommitError) Error() string { return err.msg }
+
+func (err *deCommitError) Unwrap() error { return err.kind }
+
+// NewDeCommitArityError returns an error matching ErrDeCommitArity for a de-commitment that holds `got` secrets
+// where `expected` were required
+func NewDeCommitArityError(expected, got int) error {
+	return &deCommitError{kind: ErrDeCommitArity, msg: fmt.Sprintf("length of de-commitment should be %d, got %d", expected, got)}
+}
+
+// ValidateDeCommitment checks that the de-commitment D holds its randomness and exactly `arity` secrets, each in
+// [0, bound), e.g. coordinates below the field prime. The errors match ErrDeCommitArity or ErrDeCommitRange. It does
+// not check that D opens any commitment.
+func ValidateDeCommitment(D HashDeCommitment, arity int, bound *big.Int) error {
+	if len(D) != arity+1 {
+		got := len(D) - 1
+		if got < 0 {
+			got = 0
+		}
+		return NewDeCommitArityError(arity, got)
+	}
+	if D[0] == nil || D[0].Sign() < 0 {
+		return &deCommitError{kind: ErrDeCommitRange, msg: "the randomness of the de-commitment is nil or negative"}
+	}
+	for i, secret := range D[1:] {
+		if secret == nil || secret.Sign() < 0 || secret.Cmp(bound) >= 0 {
+			return &deCommitError{kind: ErrDeCommitRange, msg: fmt.Sprintf("secret %d of the de-commitment is not in [0, %d)", i, bound)}
+		}
+	}
+	return nil
+}
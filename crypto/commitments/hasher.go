@@ -0,0 +1,82 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package commitments
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/poseidon"
+)
+
+type (
+	// Hasher is the hash function that a commitment is computed with. Hash returns the commitment to the
+	// de-commitment D, i.e. the randomness followed by the secrets, bound to the context `ctx` unless it is nil.
+	// A commitment only verifies with the hasher that it was made with.
+	Hasher interface {
+		Hash(ctx []byte, D HashDeCommitment) (*big.Int, error)
+	}
+
+	sha512_256Hasher struct{}
+	poseidonHasher   struct{}
+)
+
+var (
+	// SHA512_256Hasher is the default hasher of the commitments: SHA512_256i, or SHA512_256i_TAGGED with a context
+	SHA512_256Hasher Hasher = sha512_256Hasher{}
+
+	// PoseidonHasher commits with the Poseidon sponge over BN254, for commitments that are opened inside a SNARK
+	// circuit. The context and the big-endian bytes of every element of D are hashed as poseidon.HashInputs, the
+	// context first; a nil context is hashed as an empty one.
+	PoseidonHasher Hasher = poseidonHasher{}
+)
+
+func (sha512_256Hasher) Hash(ctx []byte, D HashDeCommitment) (*big.Int, error) {
+	var hash *big.Int
+	if ctx != nil {
+		hash = common.SHA512_256i_TAGGED(ctx, D...)
+	} else {
+		hash = common.SHA512_256i(D...)
+	}
+	if hash == nil {
+		return nil, errors.New("SHA512_256Hasher: nothing to hash")
+	}
+	return hash, nil
+}
+
+func (poseidonHasher) Hash(ctx []byte, D HashDeCommitment) (*big.Int, error) {
+	if len(D) == 0 {
+		return nil, errors.New("PoseidonHasher: nothing to hash")
+	}
+	inputs := make([][]byte, 0, len(D)+1)
+	inputs = append(inputs, ctx)
+	for _, d := range D {
+		if d == nil || d.Sign() < 0 {
+			return nil, errors.New("PoseidonHasher: the de-commitment holds a nil or negative value")
+		}
+		inputs = append(inputs, d.Bytes())
+	}
+	return poseidon.HashInputs(inputs...)
+}
+
+// NewHashCommitmentWith returns a commitment to the secrets that is computed with `hasher` and bound to `ctx`, which
+// may be nil for an unbound commitment. The context and the hasher are kept with the commitment, so that Verify and
+// DeCommit use the same ones.
+func NewHashCommitmentWith(rand io.Reader, hasher Hasher, ctx []byte, secrets ...*big.Int) (*HashCommitDecommit, error) {
+	if hasher == nil {
+		return nil, errors.New("NewHashCommitmentWith() received a nil hasher")
+	}
+	r := common.MustGetRandomInt(rand, HashLength)
+	D := append(HashDeCommitment{r}, secrets...)
+	C, err := hasher.Hash(ctx, D)
+	if err != nil {
+		return nil, err
+	}
+	return &HashCommitDecommit{C: C, D: D, Ctx: ctx, Hasher: hasher}, nil
+}
@@ -0,0 +1,79 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package commitments_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/poseidon"
+)
+
+func TestPoseidonCommitment(t *testing.T) {
+	secrets := []*big.Int{big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 255)}
+	for _, ctx := range [][]byte{nil, []byte("session")} {
+		commitment, err := NewHashCommitmentWith(rand.Reader, PoseidonHasher, ctx, secrets...)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.True(t, commitment.C.Cmp(poseidon.FieldOrder()) < 0, "a Poseidon commitment is a field element")
+		ok, D := commitment.DeCommit()
+		assert.True(t, ok)
+		assert.Equal(t, secrets, []*big.Int(D))
+
+		// the commitment can be rebuilt from its parts by the verifier
+		rebuilt := HashCommitDecommit{C: commitment.C, D: commitment.D, Ctx: ctx, Hasher: PoseidonHasher}
+		assert.True(t, rebuilt.Verify())
+		rebuilt.D = append(HashDeCommitment{}, commitment.D...)
+		rebuilt.D[1] = big.NewInt(2)
+		assert.False(t, rebuilt.Verify(), "a changed secret must not verify")
+	}
+}
+
+func TestMismatchedHashersFailDeCommit(t *testing.T) {
+	ctx := []byte("session")
+	secrets := []*big.Int{big.NewInt(3), big.NewInt(4)}
+	withPoseidon, err := NewHashCommitmentWith(rand.Reader, PoseidonHasher, ctx, secrets...)
+	assert.NoError(t, err)
+	withSHA, err := NewHashCommitmentWith(rand.Reader, SHA512_256Hasher, ctx, secrets...)
+	assert.NoError(t, err)
+
+	for name, pair := range map[string]HashCommitDecommit{
+		"poseidon opened with sha512/256": {C: withPoseidon.C, D: withPoseidon.D, Ctx: ctx, Hasher: SHA512_256Hasher},
+		"poseidon opened by default":      {C: withPoseidon.C, D: withPoseidon.D, Ctx: ctx},
+		"sha512/256 opened with poseidon": {C: withSHA.C, D: withSHA.D, Ctx: ctx, Hasher: PoseidonHasher},
+		"poseidon in another context":     {C: withPoseidon.C, D: withPoseidon.D, Ctx: []byte("other"), Hasher: PoseidonHasher},
+	} {
+		ok, D := pair.DeCommit()
+		assert.False(t, ok, name)
+		assert.Nil(t, D, name)
+	}
+	_, err = BatchDeCommit([]HashCommitDecommit{*withSHA, {C: withPoseidon.C, D: withPoseidon.D, Ctx: ctx}})
+	assert.ErrorIs(t, err, ErrDeCommitVerify)
+}
+
+func TestSHA512_256HasherIsTheDefault(t *testing.T) {
+	ctx := []byte("session")
+	bound := NewHashCommitmentWithContext(rand.Reader, ctx, big.NewInt(5), big.NewInt(6))
+	C, err := SHA512_256Hasher.Hash(ctx, bound.D)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, bound.C.Cmp(C))
+
+	unbound := NewHashCommitment(rand.Reader, big.NewInt(5), big.NewInt(6))
+	C, err = SHA512_256Hasher.Hash(nil, unbound.D)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, unbound.C.Cmp(C))
+
+	_, err = NewHashCommitmentWith(rand.Reader, nil, ctx, big.NewInt(5))
+	assert.Error(t, err)
+	_, err = PoseidonHasher.Hash(ctx, HashDeCommitment{big.NewInt(1), big.NewInt(-1)})
+	assert.Error(t, err, "negative values have no Poseidon encoding")
+}
@@ -10,12 +10,16 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/subtle"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+
+	"github.com/bnb-chain/tss-lib/v2/babyjubjub"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -27,6 +31,13 @@ type ECPoint struct {
 
 var (
 	eight = big.NewInt(8)
+
+	// ErrPointAtInfinity is returned by Add for a sum that is the point at infinity of a short Weierstrass curve,
+	// which an ECPoint cannot hold, see IsInfinity
+	ErrPointAtInfinity = errors.New("the result is the point at infinity")
+
+	// ErrScalarOutOfRange is returned by ScalarMultChecked and ScalarBaseMultChecked for a scalar outside [0, q)
+	ErrScalarOutOfRange = errors.New("the scalar is not in the range [0, q)")
 )
 
 // Creates a new ECPoint and checks that the given coordinates are on the elliptic curve.
@@ -37,6 +48,20 @@ func NewECPoint(curve elliptic.Curve, X, Y *big.Int) (*ECPoint, error) {
 	return &ECPoint{curve, [2]*big.Int{X, Y}}, nil
 }
 
+// NewECPointChecked creates a new ECPoint like NewECPoint and, on the curves with a cofactor (ed25519 and
+// BabyJubJub), also checks that the point has the prime order q of the group. Points of small order and points with
+// a small-order component are rejected instead of having their cofactor cleared as with EightInvEight.
+func NewECPointChecked(curve elliptic.Curve, X, Y *big.Int) (*ECPoint, error) {
+	p, err := NewECPoint(curve, X, Y)
+	if err != nil {
+		return nil, err
+	}
+	if !p.HasPrimeOrder() {
+		return nil, fmt.Errorf("NewECPointChecked: the given point is not of prime order")
+	}
+	return p, nil
+}
+
 // Creates a new ECPoint without checking that the coordinates are on the elliptic curve.
 // Only use this function when you are completely sure that the point is already on the curve.
 func NewECPointNoCurveCheck(curve elliptic.Curve, X, Y *big.Int) *ECPoint {
@@ -51,11 +76,20 @@ func (p *ECPoint) Y() *big.Int {
 	return new(big.Int).Set(p.coords[1])
 }
 
+// Add returns p + p1. On the short Weierstrass curves it fails with ErrPointAtInfinity for p1 = -p; on the twisted
+// Edwards curves it returns the identity, for which IsInfinity reports true.
 func (p *ECPoint) Add(p1 *ECPoint) (*ECPoint, error) {
 	x, y := p.curve.Add(p.X(), p.Y(), p1.X(), p1.Y())
+	if cofactor(p.curve) == 1 && x.Sign() == 0 && y.Sign() == 0 {
+		return nil, ErrPointAtInfinity
+	}
 	return NewECPoint(p.curve, x, y)
 }
 
+// ScalarMult returns k*p. It panics if the product is the point at infinity of a short Weierstrass curve, i.e. if k
+// is a multiple of q; the callers that take k from a peer must rule that out first. k is not checked otherwise: a k
+// of q or more is used as it is, which suits the internal paths whose scalars are reduced by construction. A verifier
+// taking k from a peer should use ScalarMultChecked.
 func (p *ECPoint) ScalarMult(k *big.Int) *ECPoint {
 	x, y := p.curve.ScalarMult(p.X(), p.Y(), k.Bytes())
 	newP, err := NewECPoint(p.curve, x, y) // it must be on the curve, no need to check.
@@ -65,6 +99,69 @@ func (p *ECPoint) ScalarMult(k *big.Int) *ECPoint {
 	return newP
 }
 
+// ScalarMultChecked returns k*p for a k received from a peer. It fails with ErrScalarOutOfRange unless 0 <= k < q,
+// so that the responses of a proof have a single valid encoding, and with ErrPointAtInfinity instead of panicking if
+// the product is the point at infinity of a short Weierstrass curve.
+func (p *ECPoint) ScalarMultChecked(k *big.Int) (*ECPoint, error) {
+	if err := checkScalar(p.curve, k); err != nil {
+		return nil, err
+	}
+	return p.ScalarMult(k), nil
+}
+
+// ScalarMultConst returns k*p using a Montgomery ladder that performs the same sequence of point additions and
+// doublings and constant-time conditional swaps for every scalar, so that the control flow does not depend on k.
+// The scalar is first shifted by q or 2q to a fixed bit length, which requires p to be in the prime-order subgroup.
+// The field arithmetic of the curve implementations (math/big) is not itself constant-time, so this only removes
+// the scalar-dependent branching of the ladder. Use it for secret scalars; public scalars should use ScalarMult.
+func (p *ECPoint) ScalarMultConst(k *big.Int) *ECPoint {
+	q := p.curve.Params().N
+	n := q.BitLen()
+	coordLen := (p.curve.Params().BitSize + 7) / 8
+
+	// k' = (k mod q) + q or + 2q, whichever has exactly n+1 bits
+	kLen := n/8 + 1
+	k1 := new(big.Int).Mod(k, q)
+	k1.Add(k1, q)
+	k2 := new(big.Int).Add(k1, q)
+	kBz, k2Bz := k1.FillBytes(make([]byte, kLen)), k2.FillBytes(make([]byte, kLen))
+	subtle.ConstantTimeCopy(1-int(k1.Bit(n)), kBz, k2Bz)
+
+	// the top bit of k' is set: R0 = p, R1 = 2p
+	x1, y1 := p.curve.Add(p.X(), p.Y(), p.X(), p.Y())
+	r0 := [2][]byte{p.coords[0].FillBytes(make([]byte, coordLen)), p.coords[1].FillBytes(make([]byte, coordLen))}
+	r1 := [2][]byte{x1.FillBytes(make([]byte, coordLen)), y1.FillBytes(make([]byte, coordLen))}
+	for i := n - 1; i >= 0; i-- {
+		bit := int(kBz[len(kBz)-1-i/8]>>(uint(i)%8)) & 1
+		conditionalSwap(bit, r0[0], r1[0])
+		conditionalSwap(bit, r0[1], r1[1])
+		r0x, r0y := new(big.Int).SetBytes(r0[0]), new(big.Int).SetBytes(r0[1])
+		sx, sy := p.curve.Add(r0x, r0y, new(big.Int).SetBytes(r1[0]), new(big.Int).SetBytes(r1[1]))
+		dx, dy := p.curve.Add(r0x, r0y, r0x, r0y)
+		sx.FillBytes(r1[0])
+		sy.FillBytes(r1[1])
+		dx.FillBytes(r0[0])
+		dy.FillBytes(r0[1])
+		conditionalSwap(bit, r0[0], r1[0])
+		conditionalSwap(bit, r0[1], r1[1])
+	}
+	newP, err := NewECPoint(p.curve, new(big.Int).SetBytes(r0[0]), new(big.Int).SetBytes(r0[1]))
+	if err != nil {
+		panic(fmt.Errorf("scalar mult to an ecpoint %s", err.Error()))
+	}
+	return newP
+}
+
+// conditionalSwap swaps the contents of a and b when swap is 1 and leaves them untouched when it is 0, in constant time
+func conditionalSwap(swap int, a, b []byte) {
+	mask := byte(-swap)
+	for i := range a {
+		t := mask & (a[i] ^ b[i])
+		a[i] ^= t
+		b[i] ^= t
+	}
+}
+
 func (p *ECPoint) ToECDSAPubKey() *ecdsa.PublicKey {
 	return &ecdsa.PublicKey{
 		Curve: p.curve,
@@ -81,6 +178,8 @@ func (p *ECPoint) Curve() elliptic.Curve {
 	return p.curve
 }
 
+// Equals reports whether the two points have the same coordinates. It returns as soon as a coordinate differs and
+// does not look at the curves; use Equal for points derived from secrets.
 func (p *ECPoint) Equals(p2 *ECPoint) bool {
 	if p == nil || p2 == nil {
 		return false
@@ -88,22 +187,120 @@ func (p *ECPoint) Equals(p2 *ECPoint) bool {
 	return p.X().Cmp(p2.X()) == 0 && p.Y().Cmp(p2.Y()) == 0
 }
 
+// Equal reports whether the two points are the same point of the same curve. The coordinates are compared as
+// fixed-length encodings with subtle.ConstantTimeCompare, so the time taken does not depend on where they differ.
+// It returns false for points on different (or unregistered) curves and for coordinates that do not fit the
+// encoding, which are not secret.
+func (p *ECPoint) Equal(p2 *ECPoint) bool {
+	if p == nil || p2 == nil || p.curve == nil || p2.curve == nil || !tss.SameCurve(p.curve, p2.curve) {
+		return false
+	}
+	size := coordinateLen(p.curve)
+	lhs, ok := p.fixedLengthBytes(size)
+	if !ok {
+		return false
+	}
+	rhs, ok := p2.fixedLengthBytes(size)
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare(lhs, rhs) == 1
+}
+
+// fixedLengthBytes returns X || Y with each coordinate encoded big-endian in `size` bytes
+func (p *ECPoint) fixedLengthBytes(size int) ([]byte, bool) {
+	out := make([]byte, 2*size)
+	for i, c := range p.coords {
+		if c == nil || c.Sign() < 0 || c.BitLen() > 8*size {
+			return nil, false
+		}
+		c.FillBytes(out[i*size : (i+1)*size])
+	}
+	return out, true
+}
+
 func (p *ECPoint) SetCurve(curve elliptic.Curve) *ECPoint {
 	p.curve = curve
 	return p
 }
 
+// OnCurve reports whether p is a non-nil point on its own curve. The check is dispatched to the curve of the point,
+// so it applies the right equation to secp256k1, ed25519 and BabyJubJub points alike.
+func (p *ECPoint) OnCurve() bool {
+	return p != nil && p.curve != nil && p.IsOnCurve()
+}
+
 func (p *ECPoint) ValidateBasic() bool {
-	return p != nil && p.coords[0] != nil && p.coords[1] != nil && p.IsOnCurve()
+	return p.OnCurve()
 }
 
+// EightInvEight clears the cofactor of p: it returns (8^-1 mod q) * (8 * p), which lies in the prime-order subgroup
+// and equals p whenever p is already in it. This is only meaningful on the curves with cofactor 8 (ed25519 and
+// BabyJubJub); on curves with cofactor 1 it returns a copy of p. It returns nil if p is nil or not on its curve.
 func (p *ECPoint) EightInvEight() *ECPoint {
+	if !p.ValidateBasic() {
+		return nil
+	}
+	if cofactor(p.curve) == 1 {
+		return NewECPointNoCurveCheck(p.curve, p.X(), p.Y())
+	}
 	eightInv := new(big.Int).ModInverse(eight, p.curve.Params().N)
 	return p.ScalarMult(eight).ScalarMult(eightInv)
 }
 
+// HasPrimeOrder reports whether p is a point of its curve in the subgroup of prime order q, i.e. q * p is the
+// identity while p is not. This holds for every valid point of a curve with cofactor 1; on ed25519 and BabyJubJub it
+// rejects the points of small order and the points with a small-order component.
+func (p *ECPoint) HasPrimeOrder() bool {
+	if !p.ValidateBasic() {
+		return false
+	}
+	if cofactor(p.curve) == 1 {
+		return true
+	}
+	if isEdwardsIdentity(p.coords[0], p.coords[1]) {
+		return false
+	}
+	x, y := p.curve.ScalarMult(p.X(), p.Y(), p.curve.Params().N.Bytes())
+	return isEdwardsIdentity(x, y)
+}
+
+// IsInfinity reports whether p is the neutral element of its group. On the twisted Edwards curves (ed25519 and
+// BabyJubJub) the neutral element is the affine point (0, 1), which lies on the curve: Add returns it for p + (-p)
+// and ScalarMult for a multiple of q. On the short Weierstrass curves (secp256k1 and the NIST curves) it has no
+// affine coordinates; crypto/elliptic writes it as (0, 0), which is not on the curve, so Add fails and NewECPoint
+// never yields it, and IsInfinity only reports true for a point made with NewECPointNoCurveCheck. A nil point or a
+// point without a curve is not the point at infinity.
+func (p *ECPoint) IsInfinity() bool {
+	if p == nil || p.curve == nil || p.coords[0] == nil || p.coords[1] == nil {
+		return false
+	}
+	if cofactor(p.curve) != 1 {
+		return isEdwardsIdentity(p.coords[0], p.coords[1])
+	}
+	return p.coords[0].Sign() == 0 && p.coords[1].Sign() == 0
+}
+
+// isEdwardsIdentity reports whether (x, y) is the identity (0, 1) of a twisted Edwards curve
+func isEdwardsIdentity(x, y *big.Int) bool {
+	return x.Sign() == 0 && y.Cmp(big.NewInt(1)) == 0
+}
+
+// ScalarBaseMult returns k*G. It uses the table built by PrecomputeBase when one exists for the curve, and always
+// goes through ScalarBaseMultBJJ on BabyJubJub and ScalarBaseMultGrumpkin on Grumpkin.
 func ScalarBaseMult(curve elliptic.Curve, k *big.Int) *ECPoint {
-	x, y := curve.ScalarBaseMult(k.Bytes())
+	if isBabyJubJub(curve) {
+		return ScalarBaseMultBJJ(k)
+	}
+	if isGrumpkin(curve) {
+		return ScalarBaseMultGrumpkin(k)
+	}
+	var x, y *big.Int
+	if table, ok := baseTables.Load(curve); ok {
+		x, y = table.(*baseTable).scalarBaseMult(k)
+	} else {
+		x, y = curve.ScalarBaseMult(k.Bytes())
+	}
 	p, err := NewECPoint(curve, x, y) // it must be on the curve, no need to check.
 	if err != nil {
 		panic(fmt.Errorf("scalar mult to an ecpoint %s", err.Error()))
@@ -111,6 +308,37 @@ func ScalarBaseMult(curve elliptic.Curve, k *big.Int) *ECPoint {
 	return p
 }
 
+// ScalarBaseMultChecked is ScalarBaseMult for a k received from a peer, with the checks of ScalarMultChecked
+func ScalarBaseMultChecked(curve elliptic.Curve, k *big.Int) (*ECPoint, error) {
+	if err := checkScalar(curve, k); err != nil {
+		return nil, err
+	}
+	return ScalarBaseMult(curve, k), nil
+}
+
+// checkScalar rules out the scalars for which ScalarMultChecked and ScalarBaseMultChecked fail. On the short
+// Weierstrass curves only k = 0 gives the point at infinity for a point of order q, which every point is.
+func checkScalar(curve elliptic.Curve, k *big.Int) error {
+	if k == nil || k.Sign() < 0 || k.Cmp(curve.Params().N) >= 0 {
+		return ErrScalarOutOfRange
+	}
+	if k.Sign() == 0 && cofactor(curve) == 1 {
+		return ErrPointAtInfinity
+	}
+	return nil
+}
+
+// cofactor returns the cofactor of the Edwards curves supported by this package and 1 for any other curve
+func cofactor(curve elliptic.Curve) int {
+	switch c := curve.(type) {
+	case *edwards.TwistedEdwardsCurve:
+		return c.H
+	case *babyjubjub.BabyJubJubCurve:
+		return babyjubjub.Params().H
+	}
+	return 1
+}
+
 func isOnCurve(c elliptic.Curve, x, y *big.Int) bool {
 	if x == nil || y == nil {
 		return false
@@ -268,3 +496,53 @@ func (p *ECPoint) UnmarshalJSON(payload []byte) error {
 
 	return nil
 }
+
+// ----- //
+// Self-describing binary encoding, for storage and transport without knowledge of the curve.
+
+// MarshalWithCurveID encodes p as the tss.CurveID of its curve followed by X and Y, big-endian and padded to the byte
+// length of the field prime, so that UnmarshalWithCurveID restores the point together with its curve. It fails for a
+// point whose curve has no ID.
+func (p *ECPoint) MarshalWithCurveID() ([]byte, error) {
+	if !p.ValidateBasic() {
+		return nil, errors.New("ECPoint.MarshalWithCurveID: the point is nil or not on its curve")
+	}
+	id, ok := tss.GetCurveID(p.curve)
+	if !ok {
+		return nil, fmt.Errorf("ECPoint.MarshalWithCurveID: the curve %s has no curve ID", p.curve.Params().Name)
+	}
+	coords, ok := p.fixedLengthBytes(coordinateLen(p.curve))
+	if !ok {
+		return nil, errors.New("ECPoint.MarshalWithCurveID: the coordinates do not fit the field")
+	}
+	return append([]byte{byte(id)}, coords...), nil
+}
+
+// UnmarshalWithCurveID decodes a point encoded by MarshalWithCurveID into p, with the curve that the encoding names.
+// It rejects an unknown curve ID, an encoding of another length than the one of the curve and a point that is not on
+// the curve.
+func (p *ECPoint) UnmarshalWithCurveID(bz []byte) error {
+	if len(bz) == 0 {
+		return errors.New("ECPoint.UnmarshalWithCurveID: empty encoding")
+	}
+	curve, ok := tss.GetCurveByID(tss.CurveID(bz[0]))
+	if !ok {
+		return fmt.Errorf("ECPoint.UnmarshalWithCurveID: unknown curve ID %d", bz[0])
+	}
+	size := coordinateLen(curve)
+	if len(bz) != 1+2*size {
+		return fmt.Errorf("ECPoint.UnmarshalWithCurveID: expected %d bytes for %s, got %d", 1+2*size, curve.Params().Name, len(bz))
+	}
+	X, Y := new(big.Int).SetBytes(bz[1:1+size]), new(big.Int).SetBytes(bz[1+size:])
+	point, err := NewECPoint(curve, X, Y)
+	if err != nil {
+		return fmt.Errorf("ECPoint.UnmarshalWithCurveID: the point is not on the elliptic curve (%s)", curve.Params().Name)
+	}
+	*p = *point
+	return nil
+}
+
+// coordinateLen is the byte length of the field prime of the curve
+func coordinateLen(curve elliptic.Curve) int {
+	return (curve.Params().P.BitLen() + 7) / 8
+}
@@ -0,0 +1,116 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// The compressed encodings are the standard ones of each curve: the 33-byte SEC 1 form 0x02/0x03 || X on secp256k1
+// and the NIST curves, and the 32-byte little-endian Y with the sign of X in the top bit on ed25519 (RFC 8032) and
+// BabyJubJub. The point at infinity has no compressed encoding.
+
+// CompressedBytes returns the compressed encoding of p. It fails on the curves that have no supported encoding.
+func (p *ECPoint) CompressedBytes() ([]byte, error) {
+	if p == nil || !p.ValidateBasic() {
+		return nil, errors.New("CompressedBytes() received a nil or invalid point")
+	}
+	switch {
+	case tss.SameCurve(p.curve, tss.Edwards()):
+		return compressEdwards(p)
+	case isBabyJubJub(p.curve):
+		encoded := (&iden3bjj.Point{X: p.X(), Y: p.Y()}).Compress()
+		return encoded[:], nil
+	case tss.SameCurve(p.curve, tss.S256()) || isNISTCurve(p.curve):
+		return elliptic.MarshalCompressed(p.curve, p.X(), p.Y()), nil
+	}
+	return nil, fmt.Errorf("CompressedBytes(): no compressed encoding is supported for %s", p.curve.Params().Name)
+}
+
+// ECPointFromCompressed decodes the compressed encoding bz of a point of `ec`. It rejects encodings of the wrong
+// length, with a bad prefix or sign bit, of coordinates that are not reduced, or of values that are not points.
+func ECPointFromCompressed(ec elliptic.Curve, bz []byte) (*ECPoint, error) {
+	if ec == nil {
+		return nil, errors.New("ECPointFromCompressed() received a nil curve")
+	}
+	var p *ECPoint
+	switch {
+	case tss.SameCurve(ec, tss.Edwards()):
+		if len(bz) != scalarEncodingLen {
+			return nil, fmt.Errorf("ECPointFromCompressed(): expected %d bytes, got %d", scalarEncodingLen, len(bz))
+		}
+		pk, err := edwards.ParsePubKey(bz)
+		if err != nil {
+			return nil, fmt.Errorf("ECPointFromCompressed(): %v", err)
+		}
+		p = NewECPointNoCurveCheck(ec, pk.X, pk.Y)
+	case isBabyJubJub(ec):
+		if len(bz) != scalarEncodingLen {
+			return nil, fmt.Errorf("ECPointFromCompressed(): expected %d bytes, got %d", scalarEncodingLen, len(bz))
+		}
+		var encoded [32]byte
+		copy(encoded[:], bz)
+		point, err := new(iden3bjj.Point).Decompress(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("ECPointFromCompressed(): %v", err)
+		}
+		p = NewECPointNoCurveCheck(ec, point.X, point.Y)
+	case tss.SameCurve(ec, tss.S256()):
+		if len(bz) != 33 || (bz[0] != 0x02 && bz[0] != 0x03) {
+			return nil, errors.New("ECPointFromCompressed(): expected 33 bytes with a 0x02 or 0x03 prefix")
+		}
+		pk, err := btcec.ParsePubKey(bz)
+		if err != nil {
+			return nil, fmt.Errorf("ECPointFromCompressed(): %v", err)
+		}
+		p = NewECPointNoCurveCheck(ec, pk.X(), pk.Y())
+	case isNISTCurve(ec):
+		x, y := elliptic.UnmarshalCompressed(ec, bz)
+		if x == nil {
+			return nil, errors.New("ECPointFromCompressed(): invalid compressed point")
+		}
+		p = NewECPointNoCurveCheck(ec, x, y)
+	default:
+		return nil, fmt.Errorf("ECPointFromCompressed(): no compressed encoding is supported for %s", ec.Params().Name)
+	}
+	if !p.IsOnCurve() {
+		return nil, errors.New("ECPointFromCompressed(): the point is not on the curve")
+	}
+	// a Y that is not reduced or a sign bit set for X = 0 decode to a point with a different encoding
+	if canonical, err := p.CompressedBytes(); err != nil || !bytes.Equal(canonical, bz) {
+		return nil, errors.New("ECPointFromCompressed(): the encoding is not canonical")
+	}
+	return p, nil
+}
+
+// compressEdwards returns the RFC 8032 encoding of a point of ed25519
+func compressEdwards(p *ECPoint) ([]byte, error) {
+	encoded, err := ScalarToLE32(p.Y(), p.curve.Params().P)
+	if err != nil {
+		return nil, fmt.Errorf("CompressedBytes(): %v", err)
+	}
+	encoded[31] |= byte(p.X().Bit(0)) << 7
+	return encoded[:], nil
+}
+
+func isNISTCurve(ec elliptic.Curve) bool {
+	for _, nist := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		if ec == nist {
+			return true
+		}
+	}
+	return false
+}
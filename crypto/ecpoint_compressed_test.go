@@ -0,0 +1,113 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto_test
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	. "github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+var compressedCurves = map[string]struct {
+	ec  elliptic.Curve
+	len int
+}{
+	"secp256k1":  {tss.S256(), 33},
+	"P-256":      {tss.P256(), 33},
+	"ed25519":    {tss.Edwards(), 32},
+	"BabyJubJub": {tss.BabyJubJub(), 32},
+}
+
+func TestCompressedBytesRoundTrip(t *testing.T) {
+	for name, c := range compressedCurves {
+		for i := 0; i < 10; i++ {
+			p := ScalarBaseMult(c.ec, common.GetRandomPositiveInt(rand.Reader, c.ec.Params().N))
+			bz, err := p.CompressedBytes()
+			if !assert.NoError(t, err, name) {
+				continue
+			}
+			assert.Len(t, bz, c.len, name)
+			decoded, err := ECPointFromCompressed(c.ec, bz)
+			if assert.NoError(t, err, name) {
+				assert.True(t, p.Equals(decoded), name)
+			}
+		}
+	}
+}
+
+func TestECPointFromCompressedRejectsMalformedInput(t *testing.T) {
+	for name, c := range compressedCurves {
+		p := ScalarBaseMult(c.ec, big.NewInt(7))
+		bz, err := p.CompressedBytes()
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		_, err = ECPointFromCompressed(c.ec, nil)
+		assert.Error(t, err, name+": empty")
+		_, err = ECPointFromCompressed(c.ec, bz[1:])
+		assert.Error(t, err, name+": too short")
+		_, err = ECPointFromCompressed(c.ec, append(append([]byte{}, bz...), 0))
+		assert.Error(t, err, name+": too long")
+	}
+
+	// SEC 1: prefixes other than 0x02 and 0x03, and an X with no point
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256()} {
+		bz, _ := ScalarBaseMult(ec, big.NewInt(7)).CompressedBytes()
+		for _, prefix := range []byte{0x00, 0x01, 0x04, 0x06, 0xff} {
+			bad := append([]byte{prefix}, bz[1:]...)
+			_, err := ECPointFromCompressed(ec, bad)
+			assert.Error(t, err, "%s: prefix %#x", ec.Params().Name, prefix)
+		}
+		unreduced := make([]byte, 33)
+		unreduced[0] = 0x02
+		new(big.Int).Add(ec.Params().P, big.NewInt(1)).FillBytes(unreduced[1:])
+		_, err := ECPointFromCompressed(ec, unreduced)
+		assert.Error(t, err, "%s: X >= P", ec.Params().Name)
+	}
+	// X = 5 is not the abscissa of a point of secp256k1: 5^3 + 7 is not a square mod P
+	noPoint := make([]byte, 33)
+	noPoint[0], noPoint[32] = 0x02, 5
+	_, err := ECPointFromCompressed(tss.S256(), noPoint)
+	assert.Error(t, err, "secp256k1: X not on the curve")
+
+	// ed25519 and BabyJubJub: an unreduced Y, a sign bit for X = 0 and a Y with no point
+	for _, ec := range []elliptic.Curve{tss.Edwards(), tss.BabyJubJub()} {
+		name := ec.Params().Name
+		unreduced, err := ScalarToLE32(new(big.Int).Add(ec.Params().P, big.NewInt(1)), nil)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		_, err = ECPointFromCompressed(ec, unreduced[:])
+		assert.Error(t, err, "%s: Y >= P", name)
+
+		// Y = 1 is the neutral element (0, 1): X = 0 has no negative
+		negativeZero := make([]byte, 32)
+		negativeZero[0], negativeZero[31] = 1, 0x80
+		_, err = ECPointFromCompressed(ec, negativeZero)
+		assert.Error(t, err, "%s: sign bit set for X = 0", name)
+	}
+	// Y = 2 does not lie on ed25519: (Y^2 - 1) / (d*Y^2 + 1) is not a square
+	noPoint = make([]byte, 32)
+	noPoint[0] = 2
+	_, err = ECPointFromCompressed(tss.Edwards(), noPoint)
+	assert.Error(t, err, "ed25519: Y not on the curve")
+}
+
+func TestCompressedBytesRejectsInvalidPoints(t *testing.T) {
+	var nilPoint *ECPoint
+	_, err := nilPoint.CompressedBytes()
+	assert.Error(t, err)
+	_, err = ECPointFromCompressed(nil, make([]byte, 33))
+	assert.Error(t, err)
+}
@@ -7,16 +7,21 @@
 package crypto_test
 
 import (
+	"crypto/elliptic"
+	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
 	. "github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
@@ -165,3 +170,393 @@ func TestEdwardsEcpointJsonSerialization(t *testing.T) {
 	assert.True(t, point.Equals(&umpoint))
 	assert.True(t, reflect.TypeOf(point.Curve()) == reflect.TypeOf(umpoint.Curve()))
 }
+
+func TestP256EcpointJsonSerialization(t *testing.T) {
+	ec := elliptic.P256()
+	// registering the curve again under another name only adds an alias
+	tss.RegisterCurve("P-256", ec)
+	name, ok := tss.GetCurveName(ec)
+	assert.True(t, ok)
+	assert.Equal(t, tss.Secp256r1, name)
+	// a curve of the same type but with other parameters is not mistaken for P-256
+	_, ok = tss.GetCurveName(&elliptic.CurveParams{Name: "other", N: big.NewInt(7), P: big.NewInt(11),
+		Gx: big.NewInt(1), Gy: big.NewInt(2), B: big.NewInt(3), BitSize: 4})
+	assert.False(t, ok)
+
+	point := ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, ec.Params().N))
+	bz, err := json.Marshal(point)
+	assert.NoError(t, err)
+	assert.Contains(t, string(bz), `"Curve":"secp256r1"`)
+
+	var umpoint ECPoint
+	assert.NoError(t, json.Unmarshal(bz, &umpoint))
+	assert.True(t, point.Equals(&umpoint))
+	assert.True(t, tss.SameCurve(ec, umpoint.Curve()))
+
+	// points serialized under the alias are still read
+	aliased := []byte(strings.Replace(string(bz), "secp256r1", "P-256", 1))
+	var umaliased ECPoint
+	assert.NoError(t, json.Unmarshal(aliased, &umaliased))
+	assert.True(t, point.Equals(&umaliased))
+}
+
+func TestECPointMarshalWithCurveID(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.BabyJubJub(), tss.P256(), tss.Grumpkin()} {
+		name := ec.Params().Name
+		// a small X checks the padding of the coordinates
+		for _, k := range []*big.Int{big.NewInt(1), common.GetRandomPositiveInt(rand.Reader, ec.Params().N)} {
+			point := ScalarBaseMult(ec, k)
+			bz, err := point.MarshalWithCurveID()
+			if !assert.NoError(t, err, name) {
+				continue
+			}
+			id, ok := tss.GetCurveID(ec)
+			assert.True(t, ok, name)
+			assert.Equal(t, byte(id), bz[0], name)
+			assert.Len(t, bz, 1+2*((ec.Params().P.BitLen()+7)/8), name)
+
+			var decoded ECPoint
+			if assert.NoError(t, decoded.UnmarshalWithCurveID(bz), name) {
+				assert.True(t, point.Equal(&decoded), name)
+				assert.True(t, tss.SameCurve(ec, decoded.Curve()), name)
+			}
+		}
+	}
+
+	// the IDs are fixed
+	for id, ec := range map[tss.CurveID]elliptic.Curve{
+		1: tss.S256(), 2: tss.Edwards(), 3: tss.BabyJubJub(), 4: tss.P256(), 5: tss.Grumpkin(),
+	} {
+		curve, ok := tss.GetCurveByID(id)
+		if assert.True(t, ok, "curve ID %d", id) {
+			assert.True(t, tss.SameCurve(ec, curve), "curve ID %d", id)
+		}
+	}
+}
+
+func TestECPointUnmarshalWithCurveIDRejects(t *testing.T) {
+	point := ScalarBaseMult(tss.S256(), big.NewInt(7))
+	bz, err := point.MarshalWithCurveID()
+	assert.NoError(t, err)
+
+	var decoded ECPoint
+	for _, id := range []byte{0, 6, 255} {
+		unknown := append([]byte{id}, bz[1:]...)
+		assert.Error(t, decoded.UnmarshalWithCurveID(unknown), "curve ID %d", id)
+	}
+	assert.Error(t, decoded.UnmarshalWithCurveID(nil))
+	assert.Error(t, decoded.UnmarshalWithCurveID(bz[:len(bz)-1]), "truncated")
+	assert.Error(t, decoded.UnmarshalWithCurveID(append(bz, 0)), "trailing byte")
+	offCurve := append([]byte{}, bz...)
+	offCurve[len(offCurve)-1] ^= 1
+	assert.Error(t, decoded.UnmarshalWithCurveID(offCurve), "not on the curve")
+	// the coordinates of a secp256k1 point are not a point of ed25519
+	edID, _ := tss.GetCurveID(tss.Edwards())
+	assert.Error(t, decoded.UnmarshalWithCurveID(append([]byte{byte(edID)}, bz[1:]...)))
+
+	// a curve without an ID cannot be encoded
+	p384 := ScalarBaseMult(elliptic.P384(), big.NewInt(7))
+	_, err = p384.MarshalWithCurveID()
+	assert.Error(t, err)
+}
+
+func TestScalarMultConst(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256(), tss.Edwards(), tss.BabyJubJub()} {
+		q := ec.Params().N
+		P := ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+		scalars := []*big.Int{
+			big.NewInt(1),
+			big.NewInt(2),
+			new(big.Int).Sub(q, big.NewInt(1)),
+			new(big.Int).Add(q, big.NewInt(5)),
+			common.GetRandomPositiveInt(rand.Reader, q),
+		}
+		for _, k := range scalars {
+			assert.True(t, P.ScalarMult(new(big.Int).Mod(k, q)).Equals(P.ScalarMultConst(k)), "k = %s on %s", k, ec.Params().Name)
+		}
+	}
+}
+
+func TestScalarMultChecked(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256(), tss.Edwards(), tss.BabyJubJub()} {
+		q := ec.Params().N
+		P := ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+		for _, k := range []*big.Int{big.NewInt(1), new(big.Int).Sub(q, big.NewInt(1))} {
+			kP, err := P.ScalarMultChecked(k)
+			if assert.NoError(t, err) {
+				assert.True(t, P.ScalarMult(k).Equals(kP))
+			}
+			kG, err := ScalarBaseMultChecked(ec, k)
+			if assert.NoError(t, err) {
+				assert.True(t, ScalarBaseMult(ec, k).Equals(kG))
+			}
+		}
+		for _, k := range []*big.Int{nil, big.NewInt(-1), new(big.Int).Set(q), new(big.Int).Add(q, big.NewInt(5))} {
+			_, err := P.ScalarMultChecked(k)
+			assert.Equal(t, ErrScalarOutOfRange, err, "k = %v on %s", k, ec.Params().Name)
+			_, err = ScalarBaseMultChecked(ec, k)
+			assert.Equal(t, ErrScalarOutOfRange, err, "k = %v on %s", k, ec.Params().Name)
+		}
+		zero, err := P.ScalarMultChecked(big.NewInt(0))
+		if tss.SameCurve(ec, tss.S256()) || tss.SameCurve(ec, tss.P256()) {
+			assert.Equal(t, ErrPointAtInfinity, err, "0*P has no ECPoint on %s", ec.Params().Name)
+		} else if assert.NoError(t, err) {
+			assert.True(t, zero.IsInfinity())
+		}
+	}
+}
+
+// A coarse dudect-style check: the mean running time for sparse and random scalars must be close, whereas the
+// variable-time double-and-add of BabyJubJub runs noticeably faster on sparse scalars.
+func TestScalarMultConstTiming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timing test in short mode")
+	}
+	ec := tss.BabyJubJub()
+	q := ec.Params().N
+	P := ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+	sparse := big.NewInt(3)
+	const samples = 48
+	var sparseTotal, randomTotal time.Duration
+	for i := 0; i < samples; i++ {
+		random := common.GetRandomPositiveInt(rand.Reader, q)
+		start := time.Now()
+		P.ScalarMultConst(sparse)
+		sparseTotal += time.Since(start)
+
+		start = time.Now()
+		P.ScalarMultConst(random)
+		randomTotal += time.Since(start)
+	}
+	ratio := float64(sparseTotal) / float64(randomTotal)
+	t.Logf("sparse/random timing ratio: %.3f", ratio)
+	assert.True(t, 0.7 < ratio && ratio < 1.4, "gross timing dependence on the scalar (ratio %.3f)", ratio)
+}
+
+func TestEightInvEight(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.Edwards(), tss.BabyJubJub()} {
+		q := ec.Params().N
+		P := ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+		identity, err := NewECPoint(ec, big.NewInt(0), big.NewInt(1))
+		assert.NoError(t, err)
+		// (0, -1) has order 2 on both twisted Edwards curves
+		T, err := NewECPoint(ec, big.NewInt(0), new(big.Int).Sub(ec.Params().P, big.NewInt(1)))
+		assert.NoError(t, err)
+
+		assert.True(t, identity.Equals(T.EightInvEight()), "a small-order point must map to the identity")
+		PT, err := P.Add(T)
+		assert.NoError(t, err)
+		assert.False(t, P.Equals(PT))
+		assert.True(t, P.Equals(PT.EightInvEight()), "the small-order component must be cleared")
+		assert.True(t, P.Equals(P.EightInvEight()), "a prime-order point must be unchanged")
+	}
+
+	// cofactor 1: a copy of the point
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256()} {
+		P := ScalarBaseMult(ec, big.NewInt(42))
+		P2 := P.EightInvEight()
+		assert.True(t, P.Equals(P2))
+		assert.False(t, P == P2)
+	}
+
+	off := NewECPointNoCurveCheck(tss.Edwards(), big.NewInt(1), big.NewInt(2))
+	assert.Nil(t, off.EightInvEight(), "an off-curve point must be rejected")
+	var nilP *ECPoint
+	assert.Nil(t, nilP.EightInvEight())
+}
+
+func TestOnCurve(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256(), tss.Edwards(), tss.BabyJubJub()} {
+		p := ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, ec.Params().N))
+		assert.True(t, p.OnCurve(), "%s", ec.Params().Name)
+		assert.True(t, p.ValidateBasic())
+
+		off := NewECPointNoCurveCheck(ec, p.X(), new(big.Int).Add(p.Y(), big.NewInt(1)))
+		assert.False(t, off.OnCurve(), "%s", ec.Params().Name)
+		assert.False(t, off.ValidateBasic())
+		assert.False(t, NewECPointNoCurveCheck(ec, nil, p.Y()).OnCurve())
+	}
+	// the coordinates of a point are checked against the curve of the point
+	edP := ScalarBaseMult(tss.Edwards(), big.NewInt(42))
+	assert.False(t, NewECPointNoCurveCheck(tss.BabyJubJub(), edP.X(), edP.Y()).OnCurve())
+	assert.False(t, NewECPointNoCurveCheck(tss.S256(), edP.X(), edP.Y()).OnCurve())
+	bjjP := ScalarBaseMult(tss.BabyJubJub(), big.NewInt(42))
+	assert.False(t, NewECPointNoCurveCheck(tss.Edwards(), bjjP.X(), bjjP.Y()).OnCurve())
+
+	var nilP *ECPoint
+	assert.False(t, nilP.OnCurve())
+}
+
+func TestNewECPointChecked(t *testing.T) {
+	// the canonical little-endian encodings of the ed25519 points of order 1, 2, 4 and 8
+	smallOrder := []string{
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000080",
+		"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05",
+		"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85",
+		"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a",
+		"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac03fa",
+	}
+	ec := tss.Edwards()
+	P := ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, ec.Params().N))
+	checked, err := NewECPointChecked(ec, P.X(), P.Y())
+	assert.NoError(t, err, "a prime-order point must be accepted")
+	assert.True(t, P.Equals(checked))
+	identity := NewECPointNoCurveCheck(ec, big.NewInt(0), big.NewInt(1))
+
+	for _, encoded := range smallOrder {
+		bz, err := hex.DecodeString(encoded)
+		assert.NoError(t, err)
+		pk, err := edwards.ParsePubKey(bz)
+		if !assert.NoError(t, err, encoded) {
+			continue
+		}
+		T, err := NewECPoint(ec, pk.X, pk.Y)
+		assert.NoError(t, err, "%s is on the curve", encoded)
+		_, err = NewECPointChecked(ec, pk.X, pk.Y)
+		assert.Error(t, err, "%s has small order", encoded)
+
+		if T.Equals(identity) {
+			continue
+		}
+		// a point with a small-order component is rejected, while EightInvEight clears it
+		PT, err := P.Add(T)
+		assert.NoError(t, err)
+		_, err = NewECPointChecked(ec, PT.X(), PT.Y())
+		assert.Error(t, err, "%s is a component of the point", encoded)
+		assert.True(t, P.Equals(PT.EightInvEight()))
+	}
+
+	// (0, -1) has order 2 on BabyJubJub as well
+	bjj := tss.BabyJubJub()
+	_, err = NewECPointChecked(bjj, big.NewInt(0), new(big.Int).Sub(bjj.Params().P, big.NewInt(1)))
+	assert.Error(t, err)
+	bjjP := ScalarBaseMult(bjj, big.NewInt(42))
+	_, err = NewECPointChecked(bjj, bjjP.X(), bjjP.Y())
+	assert.NoError(t, err)
+
+	// every point has prime order on the curves with cofactor 1
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256()} {
+		P := ScalarBaseMult(ec, big.NewInt(42))
+		_, err := NewECPointChecked(ec, P.X(), P.Y())
+		assert.NoError(t, err)
+	}
+	_, err = NewECPointChecked(ec, big.NewInt(1), big.NewInt(2))
+	assert.Error(t, err, "an off-curve point must be rejected")
+}
+
+func TestECPointEqual(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256(), tss.Edwards()} {
+		k := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+		p := ScalarBaseMult(ec, k)
+		same := NewECPointNoCurveCheck(ec, new(big.Int).Set(p.X()), new(big.Int).Set(p.Y()))
+		assert.True(t, p.Equal(same))
+		assert.True(t, same.Equal(p))
+		assert.True(t, p.Equal(p))
+
+		flippedX := NewECPointNoCurveCheck(ec, new(big.Int).Xor(p.X(), big.NewInt(1)), p.Y())
+		flippedY := NewECPointNoCurveCheck(ec, p.X(), new(big.Int).SetBit(new(big.Int).Set(p.Y()), 200, p.Y().Bit(200)^1))
+		assert.False(t, p.Equal(flippedX), "x differs in its lowest bit")
+		assert.False(t, p.Equal(flippedY), "y differs in one high bit")
+		assert.False(t, p.Equal(ScalarBaseMult(ec, new(big.Int).Add(k, big.NewInt(1)))))
+
+		tooLong := NewECPointNoCurveCheck(ec, new(big.Int).Add(p.X(), new(big.Int).Lsh(big.NewInt(1), 300)), p.Y())
+		assert.False(t, p.Equal(tooLong))
+		assert.False(t, tooLong.Equal(p))
+		assert.False(t, p.Equal(nil))
+		assert.False(t, (*ECPoint)(nil).Equal(p))
+	}
+
+	// the same coordinates on another curve
+	p := ScalarBaseMult(tss.S256(), big.NewInt(42))
+	assert.False(t, p.Equal(NewECPointNoCurveCheck(tss.P256(), p.X(), p.Y())))
+	assert.True(t, p.Equals(NewECPointNoCurveCheck(tss.P256(), p.X(), p.Y())), "Equals does not look at the curve")
+
+	// short coordinates are encoded with leading zeros
+	identity := NewECPointNoCurveCheck(tss.Edwards(), big.NewInt(0), big.NewInt(1))
+	assert.True(t, identity.Equal(NewECPointNoCurveCheck(tss.Edwards(), big.NewInt(0), big.NewInt(1))))
+	assert.False(t, identity.Equal(NewECPointNoCurveCheck(tss.Edwards(), big.NewInt(1), big.NewInt(0))))
+}
+
+func TestGrumpkin(t *testing.T) {
+	ec := tss.Grumpkin()
+	q := ec.Params().N
+	name, ok := tss.GetCurveName(ec)
+	assert.True(t, ok)
+	assert.Equal(t, tss.GrumpkinBN254, name)
+	// the base field is the scalar field of BN254, whose order is the one of BabyJubJub's base field
+	assert.Equal(t, 0, ec.Params().P.Cmp(tss.BabyJubJub().Params().P))
+
+	G := NewECPointNoCurveCheck(ec, ec.Params().Gx, ec.Params().Gy)
+	assert.True(t, G.ValidateBasic())
+	assert.True(t, G.HasPrimeOrder())
+	x, y := ec.ScalarMult(G.X(), G.Y(), q.Bytes())
+	assert.True(t, x.Sign() == 0 && y.Sign() == 0, "q*G is the point at infinity")
+
+	for _, k := range []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(5), new(big.Int).Sub(q, big.NewInt(1)),
+		common.GetRandomPositiveInt(rand.Reader, q)} {
+		kG := ScalarBaseMultGrumpkin(k)
+		assert.True(t, kG.ValidateBasic(), "k = %s", k)
+		assert.True(t, kG.Equals(ScalarBaseMult(ec, k)))
+		assert.True(t, kG.Equals(G.ScalarMult(k)), "the table and the double-and-add must agree")
+		assert.True(t, kG.Equals(G.ScalarMultConst(k)))
+		// (k+1)*G = k*G + G
+		sum, err := kG.Add(G)
+		if k.Cmp(new(big.Int).Sub(q, big.NewInt(1))) == 0 {
+			assert.Equal(t, ErrPointAtInfinity, err)
+		} else if assert.NoError(t, err) {
+			assert.True(t, sum.Equals(ScalarBaseMult(ec, new(big.Int).Add(k, big.NewInt(1)))))
+		}
+	}
+	// 5*G from the generator of the curve, checked independently
+	fiveG := ScalarBaseMult(ec, big.NewInt(5))
+	assert.Equal(t, "12229279139087521908560794489267966517139449915173592433539394009359081620359", fiveG.X().String())
+	assert.Equal(t, "12096995292699515952722386974733884667125946823386040531322131902193094989869", fiveG.Y().String())
+
+	// a point of BabyJubJub is not one of Grumpkin, nor are coordinates that are not reduced
+	bjj := ScalarBaseMult(tss.BabyJubJub(), big.NewInt(5))
+	assert.False(t, ec.IsOnCurve(bjj.X(), bjj.Y()))
+	assert.False(t, ec.IsOnCurve(new(big.Int).Add(G.X(), ec.Params().P), G.Y()))
+	_, err := NewECPoint(ec, big.NewInt(0), big.NewInt(0))
+	assert.Error(t, err, "the point at infinity is not on the curve")
+}
+
+func TestIsInfinity(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256(), tss.Edwards(), tss.BabyJubJub()} {
+		name := ec.Params().Name
+		P := ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, ec.Params().N))
+		assert.False(t, P.IsInfinity(), name)
+		// -P is (-x, y) on the Edwards curves and (x, -y) on the short Weierstrass curves
+		var negP *ECPoint
+		var err error
+		p := ec.Params().P
+		isEdwards := tss.SameCurve(ec, tss.Edwards()) || tss.SameCurve(ec, tss.BabyJubJub())
+		if isEdwards {
+			negP, err = NewECPoint(ec, new(big.Int).Sub(p, P.X()), P.Y())
+		} else {
+			negP, err = NewECPoint(ec, P.X(), new(big.Int).Sub(p, P.Y()))
+		}
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		sum, err := P.Add(negP)
+		if isEdwards {
+			if assert.NoError(t, err, name) {
+				assert.True(t, sum.IsInfinity(), name)
+				assert.True(t, sum.ValidateBasic(), "%s: the identity is a valid point", name)
+				assert.True(t, P.ScalarMult(ec.Params().N).IsInfinity(), name)
+			}
+		} else {
+			assert.Nil(t, sum, name)
+			assert.ErrorIs(t, err, ErrPointAtInfinity, name)
+			assert.True(t, NewECPointNoCurveCheck(ec, big.NewInt(0), big.NewInt(0)).IsInfinity(), name)
+		}
+	}
+	var nilPoint *ECPoint
+	assert.False(t, nilPoint.IsInfinity())
+	// (0, 1) is the identity of the Edwards curves only
+	assert.False(t, NewECPointNoCurveCheck(tss.S256(), big.NewInt(0), big.NewInt(1)).IsInfinity())
+	assert.False(t, NewECPointNoCurveCheck(tss.Edwards(), big.NewInt(0), big.NewInt(0)).IsInfinity())
+}
@@ -292,7 +292,7 @@ func (pf *ProofBobWC) Verify(Session []byte, ec elliptic.Curve, pk *paillier.Pub
 		s1ModQ := new(big.Int).Mod(pf.S1, ec.Params().N)
 		gS1 := crypto.ScalarBaseMult(ec, s1ModQ)
 		xEU, err := X.ScalarMult(e).Add(pf.U)
-		if err != nil || !gS1.Equals(xEU) {
+		if err != nil || !gS1.Equal(xEU) {
 			return false
 		}
 	}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"sync"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
@@ -30,6 +31,18 @@ type (
 	RangeProofAlice struct {
 		Z, U, W, S, S1, S2 *big.Int
 	}
+
+	// RangeProofAlicePrecomputation holds the randomness of one RangeProofAlice together with the commitments that do
+	// not depend on the message, which take most of the time of the proof. It is computed ahead of time, e.g. while a
+	// party is idle, with PrecomputeRangeProofAlice and is bound to the Paillier key of the prover and to the NTilde,
+	// h1 and h2 of the verifier. It can be used once: its randomness is wiped by the proof that consumes it.
+	RangeProofAlicePrecomputation struct {
+		mtx                     sync.Mutex
+		used                    bool
+		q, N, NTilde, h1, h2    *big.Int
+		alpha, beta, gamma, rho *big.Int
+		u, w, h2Rho             *big.Int
+	}
 )
 
 // ProveRangeAlice implements Alice's range proof used in the MtA and MtAwc protocols from GG18Spec (9) Fig. 9.
@@ -37,6 +50,19 @@ func ProveRangeAlice(ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h
 	if pk == nil || NTilde == nil || h1 == nil || h2 == nil || c == nil || m == nil || r == nil {
 		return nil, errors.New("ProveRangeAlice constructor received nil value(s)")
 	}
+	pre, err := PrecomputeRangeProofAlice(ec, pk, NTilde, h1, h2, rand)
+	if err != nil {
+		return nil, err
+	}
+	return ProveRangeAliceWithPrecomputation(ec, pk, c, NTilde, h1, h2, m, r, pre)
+}
+
+// PrecomputeRangeProofAlice draws the randomness of a RangeProofAlice for the Paillier key `pk` and the verifier's
+// NTilde, h1, h2 and computes the commitments u and w and h2^rho, steps 1-4, 6 and 7 of GG18Spec (9) Fig. 9
+func PrecomputeRangeProofAlice(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2 *big.Int, rand io.Reader) (*RangeProofAlicePrecomputation, error) {
+	if pk == nil || NTilde == nil || h1 == nil || h2 == nil {
+		return nil, errors.New("PrecomputeRangeProofAlice() received nil value(s)")
+	}
 
 	q := ec.Params().N
 	q3 := new(big.Int).Mul(q, q)
@@ -55,10 +81,9 @@ func ProveRangeAlice(ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h
 	// 4.
 	rho := common.GetRandomPositiveInt(rand, qNTilde)
 
-	// 5.
+	// 5. (h2^rho only)
 	modNTilde := common.ModInt(NTilde)
-	z := modNTilde.Exp(h1, m)
-	z = modNTilde.Mul(z, modNTilde.Exp(h2, rho))
+	h2Rho := modNTilde.Exp(h2, rho)
 
 	// 6.
 	modNSquared := common.ModInt(pk.NSquare())
@@ -69,6 +94,41 @@ func ProveRangeAlice(ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h
 	w := modNTilde.Exp(h1, alpha)
 	w = modNTilde.Mul(w, modNTilde.Exp(h2, gamma))
 
+	return &RangeProofAlicePrecomputation{
+		q: q, N: pk.N, NTilde: NTilde, h1: h1, h2: h2,
+		alpha: alpha, beta: beta, gamma: gamma, rho: rho,
+		u: u, w: w, h2Rho: h2Rho,
+	}, nil
+}
+
+// ProveRangeAliceWithPrecomputation is ProveRangeAlice with the randomness and commitments of `pre`, which must have
+// been computed for the same curve, Paillier key, NTilde, h1 and h2 and not used before. The proof is the one that
+// ProveRangeAlice returns for the same randomness.
+func ProveRangeAliceWithPrecomputation(ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h2, m, r *big.Int, pre *RangeProofAlicePrecomputation) (*RangeProofAlice, error) {
+	if pk == nil || NTilde == nil || h1 == nil || h2 == nil || c == nil || m == nil || r == nil || pre == nil {
+		return nil, errors.New("ProveRangeAlice constructor received nil value(s)")
+	}
+	pre.mtx.Lock()
+	defer pre.mtx.Unlock()
+	if pre.used {
+		return nil, errors.New("ProveRangeAliceWithPrecomputation() received a precomputation that was already used")
+	}
+	if pre.q.Cmp(ec.Params().N) != 0 || pre.N.Cmp(pk.N) != 0 || pre.NTilde.Cmp(NTilde) != 0 ||
+		pre.h1.Cmp(h1) != 0 || pre.h2.Cmp(h2) != 0 {
+		return nil, errors.New("ProveRangeAliceWithPrecomputation() received a precomputation for other parameters")
+	}
+	// the randomness must never serve two proofs, even if this one fails
+	pre.used = true
+	defer pre.wipe()
+	alpha, beta, gamma, rho, u, w := pre.alpha, pre.beta, pre.gamma, pre.rho, pre.u, pre.w
+
+	q := ec.Params().N
+
+	// 5.
+	modNTilde := common.ModInt(NTilde)
+	z := modNTilde.Exp(h1, m)
+	z = modNTilde.Mul(z, pre.h2Rho)
+
 	// 8-9. e'
 	var e *big.Int
 	{ // must use RejectionSample
@@ -91,6 +151,21 @@ func ProveRangeAlice(ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h
 	return &RangeProofAlice{Z: z, U: u, W: w, S: s, S1: s1, S2: s2}, nil
 }
 
+// Used reports whether the precomputation was consumed by a proof
+func (pre *RangeProofAlicePrecomputation) Used() bool {
+	pre.mtx.Lock()
+	defer pre.mtx.Unlock()
+	return pre.used
+}
+
+// wipe zeroes the secret randomness once the proof has been computed; u and w go out in the proof
+func (pre *RangeProofAlicePrecomputation) wipe() {
+	for _, v := range []*big.Int{pre.alpha, pre.beta, pre.gamma, pre.rho, pre.h2Rho} {
+		v.SetInt64(0)
+	}
+	pre.alpha, pre.beta, pre.gamma, pre.rho, pre.h2Rho = nil, nil, nil, nil, nil
+}
+
 func RangeProofAliceFromBytes(bzs [][]byte) (*RangeProofAlice, error) {
 	if !common.NonEmptyMultiBytes(bzs, RangeProofAliceBytesParts) {
 		return nil, fmt.Errorf("expected %d byte parts to construct RangeProofAlice", RangeProofAliceBytesParts)
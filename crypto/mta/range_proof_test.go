@@ -11,6 +11,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -122,3 +124,51 @@ func TestProveRangeAliceBypassed(t *testing.T) {
 	fmt.Println("Did verify proof bogus with data from bogus?", ok2)
 	fmt.Println("Did we bypass proof 3?", bypassresult3)
 }
+
+func TestProveRangeAliceWithPrecomputation(t *testing.T) {
+	q := tss.EC().Params().N
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	sk := keys[0].PaillierSK
+	pk := &sk.PublicKey
+	NTilde, h1, h2, err := keygen.LoadNTildeH1H2FromTestFixture(1)
+	assert.NoError(t, err)
+
+	m := common.GetRandomPositiveInt(rand.Reader, q)
+	c, r, err := sk.EncryptAndReturnRandomness(rand.Reader, m)
+	assert.NoError(t, err)
+
+	// with the same randomness, the precomputed proof is the one computed on demand
+	onDemand, err := ProveRangeAlice(tss.EC(), pk, c, NTilde, h1, h2, m, r, mrand.New(mrand.NewSource(1)))
+	assert.NoError(t, err)
+	pre, err := PrecomputeRangeProofAlice(tss.EC(), pk, NTilde, h1, h2, mrand.New(mrand.NewSource(1)))
+	assert.NoError(t, err)
+	assert.False(t, pre.Used())
+
+	// a precomputation for other parameters is rejected and not consumed
+	_, err = ProveRangeAliceWithPrecomputation(tss.EC(), pk, c, NTilde, h2, h1, m, r, pre)
+	assert.Error(t, err)
+	_, err = ProveRangeAliceWithPrecomputation(tss.P256(), pk, c, NTilde, h1, h2, m, r, pre)
+	assert.Error(t, err)
+	assert.False(t, pre.Used())
+
+	precomputed, err := ProveRangeAliceWithPrecomputation(tss.EC(), pk, c, NTilde, h1, h2, m, r, pre)
+	assert.NoError(t, err)
+	assert.Equal(t, onDemand, precomputed)
+	assert.True(t, onDemand.Verify(tss.EC(), pk, NTilde, h1, h2, c))
+	assert.True(t, precomputed.Verify(tss.EC(), pk, NTilde, h1, h2, c))
+
+	// a precomputation is single-use
+	assert.True(t, pre.Used())
+	_, err = ProveRangeAliceWithPrecomputation(tss.EC(), pk, c, NTilde, h1, h2, m, r, pre)
+	assert.Error(t, err)
+
+	// and so is the one of AliceInitWithPrecomputation
+	pre, err = PrecomputeRangeProofAlice(tss.EC(), pk, NTilde, h1, h2, rand.Reader)
+	assert.NoError(t, err)
+	cA, pf, err := AliceInitWithPrecomputation(tss.EC(), pk, m, NTilde, h1, h2, pre, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, pf.Verify(tss.EC(), pk, NTilde, h1, h2, cA))
+	_, _, err = AliceInitWithPrecomputation(tss.EC(), pk, m, NTilde, h1, h2, pre, rand.Reader)
+	assert.Error(t, err)
+}
@@ -31,6 +31,23 @@ func AliceInit(
 	return cA, pf, err
 }
 
+// AliceInitWithPrecomputation is AliceInit with the range proof computed from the single-use precomputation `pre`, see
+// ProveRangeAliceWithPrecomputation; only the encryption of `a` draws from `rand`
+func AliceInitWithPrecomputation(
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	a, NTildeB, h1B, h2B *big.Int,
+	pre *RangeProofAlicePrecomputation,
+	rand io.Reader,
+) (cA *big.Int, pf *RangeProofAlice, err error) {
+	cA, rA, err := pkA.EncryptAndReturnRandomness(rand, a)
+	if err != nil {
+		return nil, nil, err
+	}
+	pf, err = ProveRangeAliceWithPrecomputation(ec, pkA, cA, NTildeB, h1B, h2B, a, rA, pre)
+	return cA, pf, err
+}
+
 func BobMid(
 	Session []byte,
 	ec elliptic.Curve,
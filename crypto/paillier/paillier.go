@@ -96,6 +96,25 @@ func GenerateKeyPair(ctx context.Context, rand io.Reader, modulusBitLen int, opt
 		N = tmp.Mul(P, Q)
 	}
 
+	privateKey, publicKey = newKeyPair(P, Q, N)
+	return
+}
+
+// GenerateKeyPairFromSafePrimes returns the key pair with the modulus P*Q of the given safe primes, e.g. taken from
+// a SafePrimePool. As in GenerateKeyPair, P-Q must be large.
+func GenerateKeyPairFromSafePrimes(P, Q *common.GermainSafePrime) (privateKey *PrivateKey, publicKey *PublicKey, err error) {
+	if P == nil || Q == nil {
+		return nil, nil, errors.New("GenerateKeyPairFromSafePrimes() received a nil prime")
+	}
+	p, q := P.SafePrime(), Q.SafePrime()
+	if bitLen := p.BitLen(); q.BitLen() != bitLen || new(big.Int).Sub(p, q).BitLen() < bitLen-pQBitLenDifference {
+		return nil, nil, errors.New("GenerateKeyPairFromSafePrimes(): the primes are too close to each other")
+	}
+	privateKey, publicKey = newKeyPair(p, q, new(big.Int).Mul(p, q))
+	return
+}
+
+func newKeyPair(P, Q, N *big.Int) (*PrivateKey, *PublicKey) {
 	// phiN = P-1 * Q-1
 	PMinus1, QMinus1 := new(big.Int).Sub(P, one), new(big.Int).Sub(Q, one)
 	phiN := new(big.Int).Mul(PMinus1, QMinus1)
@@ -104,9 +123,9 @@ func GenerateKeyPair(ctx context.Context, rand io.Reader, modulusBitLen int, opt
 	gcd := new(big.Int).GCD(nil, nil, PMinus1, QMinus1)
 	lambdaN := new(big.Int).Div(phiN, gcd)
 
-	publicKey = &PublicKey{N: N}
-	privateKey = &PrivateKey{PublicKey: *publicKey, LambdaN: lambdaN, PhiN: phiN, P: P, Q: Q}
-	return
+	publicKey := &PublicKey{N: N}
+	privateKey := &PrivateKey{PublicKey: *publicKey, LambdaN: lambdaN, PhiN: phiN, P: P, Q: Q}
+	return privateKey, publicKey
 }
 
 // ----- //
@@ -0,0 +1,123 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package paillier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// SafePrimePool holds safe primes of one bit length that were generated ahead of time, so that keygen does not have
+// to search for them (see tss.Parameters.SetSafePrimeSource). It can be persisted with encoding/json and loaded again
+// with LoadSafePrimePool, which validates every prime. The primes are taken out of the pool as they are used: a pool
+// must belong to a single party and must not be reused after its primes have been taken, as two keys built from the
+// same prime break each other. It is safe for concurrent use.
+type SafePrimePool struct {
+	mtx    sync.Mutex
+	bitLen int
+	primes []*common.GermainSafePrime
+}
+
+type safePrimePoolJSON struct {
+	BitLen int
+	Primes []*big.Int // the Sophie Germain primes q of the safe primes 2q + 1
+}
+
+var _ tss.SafePrimeSource = (*SafePrimePool)(nil)
+
+// GenerateSafePrimePool searches for `count` safe primes of `bitLen` bits. Keygen takes four 1024-bit safe primes per
+// party: two for the Paillier modulus and two for NTilde.
+func GenerateSafePrimePool(ctx context.Context, rand io.Reader, bitLen, count int, optionalConcurrency ...int) (*SafePrimePool, error) {
+	concurrency := 1
+	if 0 < len(optionalConcurrency) {
+		concurrency = optionalConcurrency[0]
+	}
+	sgps, err := common.GetRandomSafePrimesConcurrent(ctx, bitLen, count, concurrency, rand)
+	if err != nil {
+		return nil, err
+	}
+	return NewSafePrimePool(bitLen, sgps...)
+}
+
+// NewSafePrimePool returns a pool of the given safe primes after checking that they all have `bitLen` bits and are
+// distinct safe primes
+func NewSafePrimePool(bitLen int, primes ...*common.GermainSafePrime) (*SafePrimePool, error) {
+	seen := make(map[string]struct{}, len(primes))
+	for i, sgp := range primes {
+		if sgp == nil || !sgp.Validate() {
+			return nil, fmt.Errorf("NewSafePrimePool(): the prime at index %d is not a safe prime", i)
+		}
+		if sgp.SafePrime().BitLen() != bitLen {
+			return nil, fmt.Errorf("NewSafePrimePool(): the prime at index %d has %d bits, expected %d",
+				i, sgp.SafePrime().BitLen(), bitLen)
+		}
+		key := sgp.SafePrime().String()
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("NewSafePrimePool(): the prime at index %d is a duplicate", i)
+		}
+		seen[key] = struct{}{}
+	}
+	return &SafePrimePool{bitLen: bitLen, primes: append([]*common.GermainSafePrime{}, primes...)}, nil
+}
+
+// LoadSafePrimePool restores a pool from the output of its MarshalJSON, validating every prime
+func LoadSafePrimePool(bz []byte) (*SafePrimePool, error) {
+	aux := new(safePrimePoolJSON)
+	if err := json.Unmarshal(bz, aux); err != nil {
+		return nil, fmt.Errorf("LoadSafePrimePool(): %v", err)
+	}
+	primes := make([]*common.GermainSafePrime, len(aux.Primes))
+	for i, q := range aux.Primes {
+		sgp, err := common.NewGermainSafePrime(q)
+		if err != nil {
+			return nil, fmt.Errorf("LoadSafePrimePool(): the prime at index %d is not valid: %v", i, err)
+		}
+		primes[i] = sgp
+	}
+	return NewSafePrimePool(aux.BitLen, primes...)
+}
+
+func (pool *SafePrimePool) MarshalJSON() ([]byte, error) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	aux := safePrimePoolJSON{BitLen: pool.bitLen, Primes: make([]*big.Int, len(pool.primes))}
+	for i, sgp := range pool.primes {
+		aux.Primes[i] = sgp.Prime()
+	}
+	return json.Marshal(&aux)
+}
+
+// Len returns the number of safe primes left in the pool
+func (pool *SafePrimePool) Len() int {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	return len(pool.primes)
+}
+
+// TakeSafePrimes removes `count` safe primes of `bitLen` bits from the pool and returns them. It takes none if the
+// pool does not hold enough of them.
+func (pool *SafePrimePool) TakeSafePrimes(count, bitLen int) ([]*common.GermainSafePrime, error) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	if bitLen != pool.bitLen {
+		return nil, fmt.Errorf("TakeSafePrimes(): the pool holds %d-bit primes, not %d-bit ones", pool.bitLen, bitLen)
+	}
+	if count < 1 || count > len(pool.primes) {
+		return nil, errors.New("TakeSafePrimes(): the pool does not hold enough safe primes")
+	}
+	taken := pool.primes[:count:count]
+	pool.primes = pool.primes[count:]
+	return taken, nil
+}
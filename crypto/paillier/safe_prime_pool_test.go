@@ -0,0 +1,81 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package paillier_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+)
+
+func TestSafePrimePool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	pool, err := GenerateSafePrimePool(ctx, rand.Reader, 64, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, pool.Len())
+
+	bz, err := json.Marshal(pool)
+	assert.NoError(t, err)
+	loaded, err := LoadSafePrimePool(bz)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, loaded.Len())
+
+	_, err = loaded.TakeSafePrimes(2, 128)
+	assert.Error(t, err, "the bit length must match")
+	_, err = loaded.TakeSafePrimes(6, 64)
+	assert.Error(t, err, "the pool does not hold 6 primes")
+	assert.Equal(t, 5, loaded.Len(), "a failed take must not consume primes")
+
+	taken, err := loaded.TakeSafePrimes(4, 64)
+	assert.NoError(t, err)
+	assert.Len(t, taken, 4)
+	assert.Equal(t, 1, loaded.Len())
+	for _, sgp := range taken {
+		assert.True(t, sgp.Validate())
+		assert.Equal(t, 64, sgp.SafePrime().BitLen())
+	}
+	rest, err := loaded.TakeSafePrimes(1, 64)
+	assert.NoError(t, err)
+	for _, sgp := range taken {
+		assert.NotEqual(t, 0, sgp.SafePrime().Cmp(rest[0].SafePrime()), "a prime must only be handed out once")
+	}
+	_, err = loaded.TakeSafePrimes(1, 64)
+	assert.Error(t, err)
+}
+
+func TestLoadSafePrimePoolRejectsInvalidPrimes(t *testing.T) {
+	// 1019 and 2039 are prime, so 2039 is a safe prime of 11 bits
+	_, err := LoadSafePrimePool([]byte(`{"BitLen":11,"Primes":[1019]}`))
+	assert.NoError(t, err)
+
+	// 1021 is prime but 2043 = 3^2 * 227 is not
+	_, err = LoadSafePrimePool([]byte(`{"BitLen":11,"Primes":[1019,1021]}`))
+	assert.Error(t, err)
+	// 1023 is not prime
+	_, err = LoadSafePrimePool([]byte(`{"BitLen":11,"Primes":[1023]}`))
+	assert.Error(t, err)
+	_, err = LoadSafePrimePool([]byte(`{"BitLen":12,"Primes":[1019]}`))
+	assert.Error(t, err, "the bit length must match")
+	_, err = LoadSafePrimePool([]byte(`{"BitLen":11,"Primes":[1019,1019]}`))
+	assert.Error(t, err, "duplicates must be rejected")
+	_, err = LoadSafePrimePool([]byte(`{"BitLen":11,"Primes":[null]}`))
+	assert.Error(t, err)
+
+	sgp, err := common.NewGermainSafePrime(big.NewInt(1019))
+	assert.NoError(t, err)
+	_, err = NewSafePrimePool(11, sgp, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"github.com/agl/ed25519/edwards25519"
+	"github.com/btcsuite/btcd/btcec/v2"
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// PointAccumulator sums points of one curve. On ed25519, secp256k1 and BabyJubJub the running sum is kept in
+// extended, Jacobian or projective coordinates respectively, so that adding a point costs no field inversion and
+// Result converts back to affine coordinates once. On the other curves it falls back to ECPoint.Add.
+// The sum does not depend on the order in which the points are added: the group law is commutative and Result
+// returns the unique, fully reduced affine coordinates of the sum, whatever the intermediate representation.
+// It is not safe for concurrent use.
+type PointAccumulator struct {
+	curve elliptic.Curve
+	count int
+
+	ed  *edwards25519.ExtendedGroupElement
+	jac *btcec.JacobianPoint
+	bjj *iden3bjj.PointProjective
+	// the affine sum on the other curves; nil stands for the point at infinity, which a partial sum may reach
+	sumX, sumY *big.Int
+}
+
+// NewPointAccumulator returns an empty accumulator of points of `curve`
+func NewPointAccumulator(curve elliptic.Curve) *PointAccumulator {
+	acc := &PointAccumulator{curve: curve}
+	switch {
+	case tss.SameCurve(curve, tss.Edwards()):
+		acc.ed = new(edwards25519.ExtendedGroupElement)
+		acc.ed.Zero()
+	case tss.SameCurve(curve, tss.S256()):
+		acc.jac = new(btcec.JacobianPoint)
+	case isBabyJubJub(curve):
+		acc.bjj = iden3bjj.NewPointProjective()
+	}
+	return acc
+}
+
+// Add adds p to the sum. p must be a point of the accumulator's curve.
+func (acc *PointAccumulator) Add(p *ECPoint) error {
+	if p == nil || p.curve == nil || !tss.SameCurve(p.curve, acc.curve) {
+		return errors.New("PointAccumulator.Add() received a point that is not on the curve of the accumulator")
+	}
+	switch {
+	case acc.ed != nil:
+		x, err := ScalarToLE32(p.coords[0], acc.curve.Params().P)
+		if err != nil {
+			return err
+		}
+		y, err := ScalarToLE32(p.coords[1], acc.curve.Params().P)
+		if err != nil {
+			return err
+		}
+		var q edwards25519.ExtendedGroupElement
+		edwards25519.FeFromBytes(&q.X, x)
+		edwards25519.FeFromBytes(&q.Y, y)
+		edwards25519.FeOne(&q.Z)
+		edwards25519.FeMul(&q.T, &q.X, &q.Y)
+		var qCached edwards25519.CachedGroupElement
+		var r edwards25519.CompletedGroupElement
+		q.ToCached(&qCached)
+		edwards25519.GeAdd(&r, acc.ed, &qCached)
+		r.ToExtended(acc.ed)
+	case acc.jac != nil:
+		var fx, fy, fz btcec.FieldVal
+		fx.SetByteSlice(p.coords[0].Bytes())
+		fy.SetByteSlice(p.coords[1].Bytes())
+		fz.SetInt(1)
+		q := btcec.MakeJacobianPoint(&fx, &fy, &fz)
+		// the btcec routines do not support aliasing the result with an input
+		var sum btcec.JacobianPoint
+		btcec.AddNonConst(acc.jac, &q, &sum)
+		*acc.jac = sum
+	case acc.bjj != nil:
+		acc.bjj.Add(acc.bjj, (&iden3bjj.Point{X: p.X(), Y: p.Y()}).Projective())
+	case acc.sumX == nil:
+		acc.sumX, acc.sumY = p.X(), p.Y()
+	default:
+		x, y := acc.curve.Add(acc.sumX, acc.sumY, p.X(), p.Y())
+		if cofactor(acc.curve) == 1 && x.Sign() == 0 && y.Sign() == 0 {
+			x, y = nil, nil
+		}
+		acc.sumX, acc.sumY = x, y
+	}
+	acc.count++
+	return nil
+}
+
+// Result returns the sum of the points added so far. It fails if no point was added or if the sum is the point at
+// infinity of a short Weierstrass curve, which an ECPoint cannot hold.
+func (acc *PointAccumulator) Result() (*ECPoint, error) {
+	if acc.count == 0 {
+		return nil, errors.New("PointAccumulator.Result(): no points were added")
+	}
+	var x, y *big.Int
+	switch {
+	case acc.ed != nil:
+		var zInv, fx, fy edwards25519.FieldElement
+		edwards25519.FeInvert(&zInv, &acc.ed.Z)
+		edwards25519.FeMul(&fx, &acc.ed.X, &zInv)
+		edwards25519.FeMul(&fy, &acc.ed.Y, &zInv)
+		var xB, yB [32]byte
+		edwards25519.FeToBytes(&xB, &fx)
+		edwards25519.FeToBytes(&yB, &fy)
+		x, y = ScalarFromLE32(&xB), ScalarFromLE32(&yB)
+	case acc.jac != nil:
+		if acc.jac.Z.IsZero() {
+			return nil, errors.New("PointAccumulator.Result(): the sum is the point at infinity")
+		}
+		affine := *acc.jac
+		affine.ToAffine()
+		x, y = new(big.Int).SetBytes(affine.X.Bytes()[:]), new(big.Int).SetBytes(affine.Y.Bytes()[:])
+	case acc.bjj != nil:
+		p := acc.bjj.Affine()
+		x, y = p.X, p.Y
+	default:
+		if acc.sumX == nil {
+			return nil, errors.New("PointAccumulator.Result(): the sum is the point at infinity")
+		}
+		x, y = acc.sumX, acc.sumY
+	}
+	return NewECPoint(acc.curve, x, y)
+}
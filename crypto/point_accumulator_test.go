@@ -0,0 +1,114 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto_test
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	. "github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestPointAccumulatorMatchesRepeatedAdd(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256(), tss.Edwards(), tss.BabyJubJub()} {
+		name, _ := tss.GetCurveName(ec)
+		for _, count := range []int{1, 2, 7, 33} {
+			points := make([]*ECPoint, count)
+			for i := range points {
+				points[i] = ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, ec.Params().N))
+			}
+			// a repeated point exercises the doubling case of the addition formulas
+			if count > 1 {
+				points[count-1] = points[0]
+			}
+			acc := NewPointAccumulator(ec)
+			expected := points[0]
+			assert.NoError(t, acc.Add(points[0]))
+			for _, p := range points[1:] {
+				assert.NoError(t, acc.Add(p))
+				var err error
+				expected, err = expected.Add(p)
+				assert.NoError(t, err)
+			}
+			sum, err := acc.Result()
+			if assert.NoError(t, err, "%s, %d points", name, count) {
+				assert.True(t, sum.Equals(expected), "%s, %d points", name, count)
+				assert.True(t, sum.ValidateBasic())
+			}
+		}
+	}
+}
+
+func TestPointAccumulatorIdentityAndErrors(t *testing.T) {
+	// the twisted Edwards identity (0, 1) is an affine point
+	ec := tss.Edwards()
+	p := ScalarBaseMult(ec, big.NewInt(5))
+	neg := ScalarBaseMult(ec, new(big.Int).Sub(ec.Params().N, big.NewInt(5)))
+	acc := NewPointAccumulator(ec)
+	assert.NoError(t, acc.Add(p))
+	assert.NoError(t, acc.Add(neg))
+	sum, err := acc.Result()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, sum.X().Sign())
+		assert.Equal(t, 0, sum.Y().Cmp(big.NewInt(1)))
+	}
+
+	// the secp256k1 point at infinity is not
+	ec = tss.S256()
+	p = ScalarBaseMult(ec, big.NewInt(5))
+	neg = ScalarBaseMult(ec, new(big.Int).Sub(ec.Params().N, big.NewInt(5)))
+	acc = NewPointAccumulator(ec)
+	assert.NoError(t, acc.Add(p))
+	assert.NoError(t, acc.Add(neg))
+	_, err = acc.Result()
+	assert.Error(t, err)
+
+	_, err = NewPointAccumulator(ec).Result()
+	assert.Error(t, err, "an empty accumulator has no result")
+	assert.Error(t, NewPointAccumulator(ec).Add(ScalarBaseMult(tss.Edwards(), big.NewInt(1))))
+	assert.Error(t, NewPointAccumulator(ec).Add(nil))
+}
+
+func TestPointAccumulatorOrderIndependent(t *testing.T) {
+	rnd := mrand.New(mrand.NewSource(1))
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256(), tss.Edwards(), tss.BabyJubJub()} {
+		name, _ := tss.GetCurveName(ec)
+		points := make([]*ECPoint, 9)
+		for i := range points {
+			points[i] = ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, ec.Params().N))
+		}
+		// a repeated point and a point with its negation make the partial sums hit doubling and cancellation
+		points[3] = points[0]
+		points[5] = ScalarBaseMult(ec, big.NewInt(7))
+		points[6] = ScalarBaseMult(ec, new(big.Int).Sub(ec.Params().N, big.NewInt(7)))
+
+		var expected *ECPoint
+		for trial := 0; trial < 20; trial++ {
+			acc := NewPointAccumulator(ec)
+			for _, i := range rnd.Perm(len(points)) {
+				assert.NoError(t, acc.Add(points[i]))
+			}
+			sum, err := acc.Result()
+			if !assert.NoError(t, err, name) {
+				break
+			}
+			if expected == nil {
+				expected = sum
+				continue
+			}
+			assert.Equal(t, 0, expected.X().Cmp(sum.X()), "%s, trial %d", name, trial)
+			assert.Equal(t, 0, expected.Y().Cmp(sum.Y()), "%s, trial %d", name, trial)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package poseidon
+
+import (
+	"errors"
+	"math/big"
+
+	iden3poseidon "github.com/iden3/go-iden3-crypto/poseidon"
+)
+
+const (
+	// the sponge parameters of iden3 poseidon.HashBytes
+	spongeChunkSize = 31
+	spongeInputs    = 16
+)
+
+// Hasher computes poseidon.HashBytes incrementally, so that the input does not have to be concatenated in memory
+// first. It is not safe for concurrent use.
+type Hasher struct {
+	inputs  []*big.Int
+	k       int
+	dirty   bool
+	hash    *big.Int
+	pending []byte // the bytes of the current, incomplete chunk
+	err     error
+}
+
+func NewHasher() *Hasher {
+	h := &Hasher{pending: make([]byte, 0, spongeChunkSize)}
+	h.Reset()
+	return h
+}
+
+// Write absorbs p. It never fails; an error of the underlying hash is reported by Sum.
+func (h *Hasher) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(h.pending) > 0 {
+		fill := spongeChunkSize - len(h.pending)
+		if len(p) < fill {
+			h.pending = append(h.pending, p...)
+			return n, nil
+		}
+		h.pending = append(h.pending, p[:fill]...)
+		h.absorb(h.pending)
+		h.pending = h.pending[:0]
+		p = p[fill:]
+	}
+	for ; len(p) >= spongeChunkSize; p = p[spongeChunkSize:] {
+		h.absorb(p[:spongeChunkSize])
+	}
+	h.pending = append(h.pending, p...)
+	return n, nil
+}
+
+// Sum returns the hash of everything written so far, which equals poseidon.HashBytes of the concatenated writes.
+// It does not change the state of the hasher, so more data can be written afterwards.
+func (h *Hasher) Sum() (*big.Int, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	if !h.dirty && len(h.pending) == 0 {
+		if h.hash == nil {
+			return nil, errors.New("poseidon.Hasher: nothing was written")
+		}
+		return new(big.Int).Set(h.hash), nil
+	}
+	inputs := h.inputs
+	if len(h.pending) > 0 {
+		// the last chunk is zero padded, so that 0xdeadbeaf becomes 0xdeadbeaf0000...
+		var buf [spongeChunkSize]byte
+		copy(buf[:], h.pending)
+		inputs = make([]*big.Int, spongeInputs)
+		copy(inputs, h.inputs)
+		inputs[h.k] = new(big.Int).SetBytes(buf[:])
+	}
+	return iden3poseidon.Hash(inputs)
+}
+
+// Reset discards everything written so far
+func (h *Hasher) Reset() {
+	h.inputs = newSpongeFrame(nil)
+	h.k = 0
+	h.dirty = false
+	h.hash = nil
+	h.pending = h.pending[:0]
+	h.err = nil
+}
+
+func (h *Hasher) absorb(chunk []byte) {
+	if h.err != nil {
+		return
+	}
+	h.dirty = true
+	h.inputs[h.k] = new(big.Int).SetBytes(chunk)
+	if h.k < spongeInputs-1 {
+		h.k++
+		return
+	}
+	if h.hash, h.err = iden3poseidon.Hash(h.inputs); h.err != nil {
+		return
+	}
+	h.dirty = false
+	h.inputs = newSpongeFrame(h.hash)
+	h.k = 1
+}
+
+// newSpongeFrame returns a frame of zero inputs that carries `hash` in its first slot unless it is nil
+func newSpongeFrame(hash *big.Int) []*big.Int {
+	inputs := make([]*big.Int, spongeInputs)
+	for j := range inputs {
+		inputs[j] = new(big.Int)
+	}
+	if hash != nil {
+		inputs[0] = hash
+	}
+	return inputs
+}
@@ -0,0 +1,67 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package poseidon_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/crypto/poseidon"
+)
+
+func TestSelfTest(t *testing.T) {
+	assert.NoError(t, SelfTest())
+}
+
+func TestKATVectorsFile(t *testing.T) {
+	bz, err := os.ReadFile("testdata/kat_vectors.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	vectors, err := LoadKATVectors(bz)
+	assert.NoError(t, err)
+	assert.Len(t, vectors, 15)
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			assert.NoError(t, RunKAT([]KATVector{v}))
+		})
+	}
+}
+
+func TestKATFailureDiagnostic(t *testing.T) {
+	vectors := []KATVector{{
+		Name:     "good",
+		Inputs:   []string{"1", "2"},
+		Expected: "7853200120776062878684798364095072458815029376092732009249414926327459813530",
+	}, {
+		Name:     "wrong digest",
+		Inputs:   []string{"1", "2"},
+		Expected: "7853200120776062878684798364095072458815029376092732009249414926327459813531",
+	}, {
+		Name:     "both inputs and bytes",
+		Inputs:   []string{"1"},
+		Bytes:    "01",
+		Expected: "1",
+	}, {
+		Name:     "not hex",
+		Bytes:    "zz",
+		Expected: "1",
+	}}
+	err := RunKAT(vectors)
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), `"good"`)
+		assert.Contains(t, err.Error(), `poseidon KAT 1 "wrong digest": got 7853200120776062878684798364095072458815029376092732009249414926327459813530, expected 7853200120776062878684798364095072458815029376092732009249414926327459813531`)
+		assert.Contains(t, err.Error(), `"both inputs and bytes"`)
+		assert.Contains(t, err.Error(), `"not hex"`)
+	}
+
+	assert.Error(t, RunKAT(nil))
+	_, err = LoadKATVectors([]byte("{"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package poseidon
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+	iden3poseidon "github.com/iden3/go-iden3-crypto/poseidon"
+)
+
+const (
+	// maxRejectionRounds bounds the rejection sampling loop of HashToScalar. Each round is rejected with probability
+	// below 1/2, so reaching the bound is practically impossible for any reasonable q.
+	maxRejectionRounds = 256
+
+	// MaxFieldElements is the largest number of inputs of the Poseidon permutation, as in circomlib
+	MaxFieldElements = 16
+)
+
+// FieldOrder is the order of the BN254 scalar field in which the Poseidon hash outputs its digests
+func FieldOrder() *big.Int {
+	return new(big.Int).Set(constants.Q)
+}
+
+// HashToScalar hashes the inputs with Poseidon and maps the digest uniformly into [0, q) by rejection sampling:
+// digests at or above the largest multiple of q below the field order are rejected and the inputs are hashed again
+// with the next round counter. When q is larger than the field order the digest is returned as is; it is then
+// below q but only covers [0, FieldOrder()).
+func HashToScalar(q *big.Int, inputs ...[]byte) (*big.Int, error) {
+	if q == nil || q.Sign() <= 0 {
+		return nil, errors.New("HashToScalar: q must be positive")
+	}
+	field := constants.Q
+	limit := new(big.Int).Sub(field, new(big.Int).Mod(field, q))
+	msg := encodeInputs(inputs)
+	for round := uint32(0); round < maxRejectionRounds; round++ {
+		binary.BigEndian.PutUint32(msg[:4], round)
+		h, err := iden3poseidon.HashBytes(msg)
+		if err != nil {
+			return nil, err
+		}
+		if q.Cmp(field) >= 0 {
+			return h, nil
+		}
+		if h.Cmp(limit) < 0 {
+			return h.Mod(h, q), nil
+		}
+	}
+	return nil, errors.New("HashToScalar: rejection sampling did not terminate")
+}
+
+// HashFieldElements hashes 1 to MaxFieldElements field elements with the Poseidon instance of that width, as
+// circomlib's Poseidon(n) template does. Unlike HashInputs the elements are not encoded as bytes first, so the digest
+// can be recomputed inside a circuit from the same elements. Every element must be in [0, FieldOrder()).
+func HashFieldElements(elems []*big.Int) (*big.Int, error) {
+	if len(elems) == 0 || len(elems) > MaxFieldElements {
+		return nil, fmt.Errorf("HashFieldElements: got %d elements, expected 1 to %d", len(elems), MaxFieldElements)
+	}
+	for i, e := range elems {
+		if e == nil || e.Sign() < 0 || e.Cmp(constants.Q) >= 0 {
+			return nil, fmt.Errorf("HashFieldElements: the element at index %d is not in the field", i)
+		}
+	}
+	return iden3poseidon.Hash(elems)
+}
+
+// FlattenInputs returns the canonical encoding of the inputs, in which every input is prefixed with its length as a
+// 4-byte big-endian integer. Unlike plain concatenation, inputs whose byte boundaries are shifted (e.g. "ab", "c"
+// and "a", "bc") encode differently.
+func FlattenInputs(inputs ...[]byte) []byte {
+	size := 0
+	for _, in := range inputs {
+		size += 4 + len(in)
+	}
+	out := make([]byte, 0, size)
+	lenBz := make([]byte, 4)
+	for _, in := range inputs {
+		binary.BigEndian.PutUint32(lenBz, uint32(len(in)))
+		out = append(out, lenBz...)
+		out = append(out, in...)
+	}
+	return out
+}
+
+// HashInputs returns the Poseidon sponge hash of FlattenInputs(inputs...)
+func HashInputs(inputs ...[]byte) (*big.Int, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("HashInputs: no inputs")
+	}
+	return iden3poseidon.HashBytes(FlattenInputs(inputs...))
+}
+
+// encodeInputs returns a 4-byte round counter (left zero here) and the 4-byte input count followed by
+// FlattenInputs(inputs...), all big-endian
+func encodeInputs(inputs [][]byte) []byte {
+	msg := make([]byte, 8, 8+len(inputs)*4)
+	binary.BigEndian.PutUint32(msg[4:8], uint32(len(inputs)))
+	return append(msg, FlattenInputs(inputs...)...)
+}
@@ -0,0 +1,205 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package poseidon_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	iden3poseidon "github.com/iden3/go-iden3-crypto/poseidon"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/poseidon"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestHashToScalarVectors(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs [][]byte
+		edQ    string
+		bjjQ   string
+	}{{
+		name:   "no inputs",
+		inputs: [][]byte{},
+		edQ:    "f63cd0c9fbe679a562469831d8e810c9d33cc2409695b8e6a893e627ea952d1",
+		bjjQ:   "34ab96fe771ff8fe81058157d2e2af546b5f0b397277f799ba40eaa0c6704ef",
+	}, {
+		name:   "one empty input",
+		inputs: [][]byte{{}},
+		edQ:    "4a27c421387f2e0fbde36368cab8ec2a62e5cd220a0f7602b3bbbf71ef313d5",
+		bjjQ:   "4a27c421387f2e0fbde36368cab8ec2a62e5cd220a0f7602b3bbbf71ef313d5",
+	}, {
+		name:   "abc",
+		inputs: [][]byte{[]byte("abc")},
+		edQ:    "50c07be13c38e5b343484b49fe8dcf1d6e92fe92bf41c876c23e343c6c426a9",
+		bjjQ:   "2e66a52ff50f24b8f166a902f585bceea0b609f2388ef3e8dde7ec9785685c3",
+	}, {
+		name:   "a, bc",
+		inputs: [][]byte{[]byte("a"), []byte("bc")},
+		edQ:    "b7f1487a428a16e23927246b54dc433b1096a257eb36d02e99f907e22815d95",
+		bjjQ:   "34ced4e338fd159476a4f6b748d180518ecad233d2751afa3e794279af295be",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ed, err := HashToScalar(tss.Edwards().Params().N, tt.inputs...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.edQ, ed.Text(16))
+			bjj, err := HashToScalar(tss.BabyJubJub().Params().N, tt.inputs...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.bjjQ, bjj.Text(16))
+		})
+	}
+}
+
+func TestHashToScalarBelowQ(t *testing.T) {
+	// a q of 2/3 of the field order rejects about a third of the digests
+	twoThirds := new(big.Int).Div(new(big.Int).Mul(FieldOrder(), big.NewInt(2)), big.NewInt(3))
+	for _, q := range []*big.Int{tss.Edwards().Params().N, tss.BabyJubJub().Params().N, tss.S256().Params().N, twoThirds, big.NewInt(7)} {
+		for i := 0; i < 64; i++ {
+			in := common.MustGetRandomInt(rand.Reader, 256).Bytes()
+			e, err := HashToScalar(q, in, []byte("ctx"))
+			assert.NoError(t, err)
+			assert.True(t, e.Sign() >= 0 && e.Cmp(q) < 0, "the scalar must be in [0, q)")
+		}
+	}
+}
+
+func TestHashToScalarDomainSeparation(t *testing.T) {
+	q := tss.Edwards().Params().N
+	a, err := HashToScalar(q, []byte("ab"), []byte("c"))
+	assert.NoError(t, err)
+	b, err := HashToScalar(q, []byte("a"), []byte("bc"))
+	assert.NoError(t, err)
+	c, err := HashToScalar(q, []byte("abc"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, a.Cmp(b))
+	assert.NotEqual(t, 0, a.Cmp(c))
+	assert.NotEqual(t, 0, b.Cmp(c))
+}
+
+func TestHashToScalarInvalidQ(t *testing.T) {
+	_, err := HashToScalar(nil, []byte("abc"))
+	assert.Error(t, err)
+	_, err = HashToScalar(big.NewInt(0), []byte("abc"))
+	assert.Error(t, err)
+}
+
+func TestFlattenInputsVector(t *testing.T) {
+	// an (R, A, M) triple as hashed by a signer
+	R, _ := hex.DecodeString("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20")
+	A, _ := hex.DecodeString("a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0")
+	M := []byte("hello")
+	assert.Equal(t,
+		"00000020"+"0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"+
+			"00000020"+"a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebfc0"+
+			"00000005"+"68656c6c6f",
+		hex.EncodeToString(FlattenInputs(R, A, M)))
+	h, err := HashInputs(R, A, M)
+	assert.NoError(t, err)
+	assert.Equal(t, "28bcfd98d545e089e5077757e682cb5cc93b16ea874c2a1b2dd5e422880d7c8b", h.Text(16))
+}
+
+func TestHashFieldElements(t *testing.T) {
+	// circomlib's Poseidon(3) over (1, 2, 3)
+	h, err := HashFieldElements([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	assert.NoError(t, err)
+	assert.Equal(t, "6542985608222806190361240322586112750744169038454362455181422643027100751666", h.String())
+
+	// every width selects its own instance
+	field := FieldOrder()
+	elems := make([]*big.Int, MaxFieldElements)
+	for i := range elems {
+		elems[i] = common.GetRandomPositiveInt(rand.Reader, field)
+	}
+	for n := 1; n <= MaxFieldElements; n++ {
+		h, err := HashFieldElements(elems[:n])
+		assert.NoError(t, err, "%d elements", n)
+		expected, err := iden3poseidon.Hash(elems[:n])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, h, "%d elements", n)
+	}
+	one, err := HashFieldElements(elems[:1])
+	assert.NoError(t, err)
+	padded, err := HashFieldElements([]*big.Int{elems[0], big.NewInt(0)})
+	assert.NoError(t, err)
+	assert.NotEqual(t, one, padded, "a zero element is not the same as no element")
+
+	_, err = HashFieldElements(nil)
+	assert.Error(t, err)
+	_, err = HashFieldElements(append(elems, big.NewInt(1)))
+	assert.Error(t, err, "more elements than the largest instance")
+	_, err = HashFieldElements([]*big.Int{big.NewInt(1), field})
+	assert.Error(t, err, "elements must be below the field order")
+	_, err = HashFieldElements([]*big.Int{big.NewInt(-1)})
+	assert.Error(t, err)
+	_, err = HashFieldElements([]*big.Int{big.NewInt(1), nil})
+	assert.Error(t, err)
+}
+
+func TestHashInputsShiftedBoundaries(t *testing.T) {
+	// both pairs concatenate to "abc"
+	a, err := HashInputs([]byte("ab"), []byte("c"))
+	assert.NoError(t, err)
+	b, err := HashInputs([]byte("a"), []byte("bc"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, a.Cmp(b))
+
+	// an empty input is encoded as its length prefix and still separates the others
+	c, err := HashInputs([]byte{}, []byte("ab"))
+	assert.NoError(t, err)
+	d, err := HashInputs([]byte("a"), []byte("b"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, c.Cmp(d))
+
+	_, err = HashInputs()
+	assert.Error(t, err)
+}
+
+func TestHasherMatchesHashBytes(t *testing.T) {
+	rnd := mrand.New(mrand.NewSource(1))
+	// lengths around the 31-byte chunks and 16-input frames of the sponge
+	lengths := []int{1, 30, 31, 32, 31 * 15, 31 * 16, 31*16 + 1, 31 * 31, 2000}
+	for i := 0; i < 64; i++ {
+		lengths = append(lengths, 1+rnd.Intn(4096))
+	}
+	for _, length := range lengths {
+		msg := make([]byte, length)
+		rnd.Read(msg)
+		expected, err := iden3poseidon.HashBytes(msg)
+		assert.NoError(t, err)
+
+		h := NewHasher()
+		for rest := msg; len(rest) > 0; {
+			n := rnd.Intn(len(rest) + 1)
+			_, _ = h.Write(rest[:n])
+			rest = rest[n:]
+		}
+		actual, err := h.Sum()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, expected.Cmp(actual), "length %d", length)
+
+		// Sum does not consume the state
+		again, err := h.Sum()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, expected.Cmp(again))
+	}
+}
+
+func TestHasherEmpty(t *testing.T) {
+	h := NewHasher()
+	_, err := h.Sum()
+	assert.Error(t, err)
+	_, _ = h.Write([]byte("abc"))
+	h.Reset()
+	_, err = h.Sum()
+	assert.Error(t, err)
+}
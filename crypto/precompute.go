@@ -0,0 +1,188 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"sync"
+
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+
+	"github.com/bnb-chain/tss-lib/v2/babyjubjub"
+	"github.com/bnb-chain/tss-lib/v2/grumpkin"
+)
+
+const (
+	baseTableWindowBits = 4
+)
+
+type (
+	// baseTable holds j * 2^(w*i) * G for every window i and digit j in [1, 2^w)
+	baseTable struct {
+		curve  elliptic.Curve
+		points [][][2]*big.Int
+	}
+
+	// bjjBaseTable is the BabyJubJub counterpart of baseTable. Its entries are kept in projective coordinates so that
+	// a multiplication only converts back to affine once, where the generic table pays a field inversion per addition.
+	bjjBaseTable struct {
+		n      *big.Int
+		points [][]*iden3bjj.PointProjective
+	}
+)
+
+var (
+	baseTableOnces sync.Map // elliptic.Curve -> *sync.Once
+	baseTables     sync.Map // elliptic.Curve -> *baseTable, stored only once fully built
+
+	bjjTableOnce sync.Once
+	bjjTable     *bjjBaseTable
+)
+
+// PrecomputeBase builds a fixed-window table of multiples of the generator of `curve`. Once it has been built,
+// ScalarBaseMult on that curve sums at most one table entry per window instead of running a full scalar
+// multiplication. This pays off on curves with a slow generic ScalarBaseMult (e.g. ed25519, BabyJubJub), but not
+// on secp256k1 whose implementation is already precomputed. It is safe to call concurrently and more than once.
+// On BabyJubJub it is the same as PrecomputeBaseBJJ.
+func PrecomputeBase(curve elliptic.Curve) {
+	if isBabyJubJub(curve) {
+		PrecomputeBaseBJJ()
+		return
+	}
+	once, _ := baseTableOnces.LoadOrStore(curve, new(sync.Once))
+	once.(*sync.Once).Do(func() {
+		baseTables.Store(curve, newBaseTable(curve))
+	})
+}
+
+func newBaseTable(curve elliptic.Curve) *baseTable {
+	ecParams := curve.Params()
+	windows := (ecParams.N.BitLen() + baseTableWindowBits - 1) / baseTableWindowBits
+	table := &baseTable{curve: curve, points: make([][][2]*big.Int, windows)}
+	bx, by := ecParams.Gx, ecParams.Gy
+	for i := 0; i < windows; i++ {
+		row := make([][2]*big.Int, (1<<baseTableWindowBits)-1)
+		row[0] = [2]*big.Int{bx, by}
+		for j := 1; j < len(row); j++ {
+			x, y := curve.Add(row[j-1][0], row[j-1][1], bx, by)
+			row[j] = [2]*big.Int{x, y}
+		}
+		table.points[i] = row
+		// next base: 2^w * current base
+		for b := 0; b < baseTableWindowBits; b++ {
+			bx, by = curve.Add(bx, by, bx, by)
+		}
+	}
+	return table
+}
+
+func (table *baseTable) scalarBaseMult(k *big.Int) (x, y *big.Int) {
+	// the generator has order N, so k and |k| mod N give the same point; k.Bytes() drops the sign as well
+	kk := new(big.Int).Abs(k)
+	kk.Mod(kk, table.curve.Params().N)
+	if kk.Sign() == 0 {
+		return table.curve.ScalarBaseMult(kk.Bytes())
+	}
+	for i, row := range table.points {
+		digit := 0
+		for b := baseTableWindowBits - 1; b >= 0; b-- {
+			digit = digit<<1 | int(kk.Bit(i*baseTableWindowBits+b))
+		}
+		if digit == 0 {
+			continue
+		}
+		if x == nil {
+			x, y = row[digit-1][0], row[digit-1][1]
+			continue
+		}
+		x, y = table.curve.Add(x, y, row[digit-1][0], row[digit-1][1])
+	}
+	return new(big.Int).Set(x), new(big.Int).Set(y)
+}
+
+// PrecomputeBaseBJJ builds the table of multiples of the BabyJubJub generator used by ScalarBaseMultBJJ. That
+// function builds it on first use anyway, so calling this is only needed to pay the cost up front. It is safe to call
+// concurrently and more than once.
+func PrecomputeBaseBJJ() {
+	bjjTableOnce.Do(func() {
+		bjjTable = newBJJBaseTable()
+	})
+}
+
+// ScalarBaseMultBJJ returns k*G on BabyJubJub using the precomputed table: one projective addition per window of k
+// and a single conversion to affine coordinates at the end.
+func ScalarBaseMultBJJ(k *big.Int) *ECPoint {
+	PrecomputeBaseBJJ()
+	x, y := bjjTable.scalarBaseMult(k)
+	return NewECPointNoCurveCheck(babyjubjub.BabyJubJub(), x, y)
+}
+
+func isBabyJubJub(curve elliptic.Curve) bool {
+	_, ok := curve.(*babyjubjub.BabyJubJubCurve)
+	return ok
+}
+
+// ScalarBaseMultGrumpkin returns k*G on Grumpkin using the table of PrecomputeBase, which it builds on first use.
+// As on the other short Weierstrass curves, it panics if k is a multiple of q.
+func ScalarBaseMultGrumpkin(k *big.Int) *ECPoint {
+	curve := grumpkin.Grumpkin()
+	PrecomputeBase(curve)
+	table, _ := baseTables.Load(curve)
+	x, y := table.(*baseTable).scalarBaseMult(k)
+	p, err := NewECPoint(curve, x, y)
+	if err != nil {
+		panic(fmt.Errorf("scalar mult to an ecpoint %s", err.Error()))
+	}
+	return p
+}
+
+func isGrumpkin(curve elliptic.Curve) bool {
+	_, ok := curve.(*grumpkin.GrumpkinCurve)
+	return ok
+}
+
+func newBJJBaseTable() *bjjBaseTable {
+	ecParams := babyjubjub.BabyJubJub().Params()
+	windows := (ecParams.N.BitLen() + baseTableWindowBits - 1) / baseTableWindowBits
+	table := &bjjBaseTable{n: ecParams.N, points: make([][]*iden3bjj.PointProjective, windows)}
+	base := (&iden3bjj.Point{X: ecParams.Gx, Y: ecParams.Gy}).Projective()
+	for i := 0; i < windows; i++ {
+		row := make([]*iden3bjj.PointProjective, (1<<baseTableWindowBits)-1)
+		row[0] = base
+		for j := 1; j < len(row); j++ {
+			row[j] = iden3bjj.NewPointProjective().Add(row[j-1], base)
+		}
+		table.points[i] = row
+		// next base: 2^w * current base
+		for b := 0; b < baseTableWindowBits; b++ {
+			base = iden3bjj.NewPointProjective().Add(base, base)
+		}
+	}
+	return table
+}
+
+func (table *bjjBaseTable) scalarBaseMult(k *big.Int) (x, y *big.Int) {
+	// the generator has order N, so k and |k| mod N give the same point; k.Bytes() drops the sign as well
+	kk := new(big.Int).Abs(k)
+	kk.Mod(kk, table.n)
+	// Add replaces the coordinates of its receiver rather than writing into them, so the table entries are not changed
+	acc := iden3bjj.NewPointProjective() // the identity
+	for i, row := range table.points {
+		digit := 0
+		for b := baseTableWindowBits - 1; b >= 0; b-- {
+			digit = digit<<1 | int(kk.Bit(i*baseTableWindowBits+b))
+		}
+		if digit == 0 {
+			continue
+		}
+		acc.Add(acc, row[digit-1])
+	}
+	p := acc.Affine()
+	return p.X, p.Y
+}
@@ -0,0 +1,109 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto_test
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	. "github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestPrecomputeBase(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.BabyJubJub()} {
+		q := ec.Params().N
+		scalars := []*big.Int{
+			big.NewInt(1),
+			big.NewInt(2),
+			big.NewInt(15),
+			big.NewInt(16),
+			new(big.Int).Sub(q, big.NewInt(1)),
+			new(big.Int).Add(q, big.NewInt(3)),
+		}
+		if ec != tss.S256() { // k = 0 gives the identity, which has no affine form on secp256k1
+			scalars = append(scalars, big.NewInt(0), new(big.Int).Set(q))
+		}
+		for i := 0; i < 8; i++ {
+			scalars = append(scalars, common.GetRandomPositiveInt(rand.Reader, q))
+		}
+		expected := make([]*ECPoint, len(scalars))
+		for i, k := range scalars {
+			expected[i] = ScalarBaseMult(ec, k)
+		}
+		PrecomputeBase(ec)
+		PrecomputeBase(ec) // idempotent
+		for i, k := range scalars {
+			assert.True(t, expected[i].Equals(ScalarBaseMult(ec, k)), "k = %s on %s", k, ec.Params().Name)
+		}
+	}
+}
+
+func BenchmarkScalarBaseMultEdwards(b *testing.B) {
+	ec := tss.Edwards()
+	k := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+	b.Run("generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ec.ScalarBaseMult(k.Bytes())
+		}
+	})
+	PrecomputeBase(ec)
+	b.Run("precomputed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ScalarBaseMult(ec, k)
+		}
+	})
+}
+
+func TestScalarBaseMultBJJ(t *testing.T) {
+	ec := tss.BabyJubJub()
+	q := ec.Params().N
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(15),
+		big.NewInt(16),
+		big.NewInt(-5),
+		new(big.Int).Lsh(big.NewInt(1), uint(q.BitLen()-1)),
+		new(big.Int).Sub(q, big.NewInt(1)),
+		new(big.Int).Set(q),
+		new(big.Int).Add(q, big.NewInt(3)),
+	}
+	for i := 0; i < 32; i++ {
+		scalars = append(scalars, common.GetRandomPositiveInt(rand.Reader, q))
+	}
+	PrecomputeBaseBJJ()
+	for _, k := range scalars {
+		// the generic path, which does not use the table
+		x, y := ec.ScalarBaseMult(k.Bytes())
+		expected := NewECPointNoCurveCheck(ec, x, y)
+		assert.True(t, expected.Equals(ScalarBaseMultBJJ(k)), "k = %s", k)
+		assert.True(t, expected.Equals(ScalarBaseMult(ec, k)), "k = %s", k)
+	}
+}
+
+func BenchmarkScalarBaseMultBJJ(b *testing.B) {
+	ec := tss.BabyJubJub()
+	k := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+	b.Run("generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ec.ScalarBaseMult(k.Bytes())
+		}
+	})
+	PrecomputeBaseBJJ()
+	b.Run("precomputed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ScalarBaseMultBJJ(k)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The library encodes 32-byte integers in two byte orders. big.Int, ECDSA, BIP-340 and the Schnorr proofs use
+// big-endian bytes, while Ed25519 (RFC 8032) and the edwards25519 scalar routines use little-endian bytes. The helpers
+// below name the order explicitly and fail instead of truncating a value that does not fit.
+
+const scalarEncodingLen = 32
+
+// ScalarToBE32 returns the 32-byte big-endian encoding of s, with leading zero bytes kept. It fails if s is nil or
+// negative, if s is not below q, or if s does not fit in 32 bytes; a nil q only checks the length.
+func ScalarToBE32(s, q *big.Int) (*[32]byte, error) {
+	if err := checkScalar32(s, q); err != nil {
+		return nil, fmt.Errorf("ScalarToBE32(): %v", err)
+	}
+	out := new([32]byte)
+	s.FillBytes(out[:])
+	return out, nil
+}
+
+// ScalarToLE32 returns the 32-byte little-endian encoding of s used by Ed25519. It fails if s is nil or negative, if
+// s is not below q, or if s does not fit in 32 bytes; a nil q only checks the length.
+func ScalarToLE32(s, q *big.Int) (*[32]byte, error) {
+	if err := checkScalar32(s, q); err != nil {
+		return nil, fmt.Errorf("ScalarToLE32(): %v", err)
+	}
+	out := new([32]byte)
+	s.FillBytes(out[:])
+	reverse32(out)
+	return out, nil
+}
+
+// ScalarFromBE32 returns the integer of the 32-byte big-endian encoding b
+func ScalarFromBE32(b *[32]byte) *big.Int {
+	return new(big.Int).SetBytes(b[:])
+}
+
+// ScalarFromLE32 returns the integer of the 32-byte little-endian encoding b. b is not changed.
+func ScalarFromLE32(b *[32]byte) *big.Int {
+	be := *b
+	reverse32(&be)
+	return new(big.Int).SetBytes(be[:])
+}
+
+func checkScalar32(s, q *big.Int) error {
+	switch {
+	case s == nil:
+		return errors.New("the value is nil")
+	case s.Sign() < 0:
+		return errors.New("the value is negative")
+	case q != nil && s.Cmp(q) >= 0:
+		return errors.New("the value is not reduced")
+	case (s.BitLen()+7)/8 > scalarEncodingLen:
+		return fmt.Errorf("the value does not fit in %d bytes", scalarEncodingLen)
+	}
+	return nil
+}
+
+func reverse32(b *[32]byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
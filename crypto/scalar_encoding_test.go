@@ -0,0 +1,82 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestScalarEncodingKeepsZeroHighByte(t *testing.T) {
+	// 31 bytes 0x01..0x1f: the most significant of the 32 bytes is zero and must not be dropped
+	raw := make([]byte, 31)
+	for i := range raw {
+		raw[i] = byte(i + 1)
+	}
+	s := new(big.Int).SetBytes(raw)
+	q := tss.Edwards().Params().N
+
+	be, err := ScalarToBE32(s, q)
+	if assert.NoError(t, err) {
+		assert.Equal(t, byte(0), be[0], "the zero high byte leads the big-endian encoding")
+		assert.Equal(t, raw, be[1:])
+		assert.Equal(t, 0, s.Cmp(ScalarFromBE32(be)))
+	}
+
+	le, err := ScalarToLE32(s, q)
+	if assert.NoError(t, err) {
+		assert.Equal(t, byte(0), le[31], "the zero high byte ends the little-endian encoding")
+		assert.Equal(t, byte(0x1f), le[0], "the least significant byte comes first")
+		assert.Equal(t, 0, s.Cmp(ScalarFromLE32(le)))
+		assert.NotEqual(t, 0, s.Cmp(ScalarFromBE32(le)), "the two orders must not be mixed up")
+	}
+
+	// a zero low byte must survive as well
+	s = new(big.Int).Lsh(big.NewInt(0x2a), 8)
+	le, err = ScalarToLE32(s, nil)
+	if assert.NoError(t, err) {
+		assert.True(t, bytes.Equal([]byte{0x00, 0x2a}, le[:2]))
+		assert.Equal(t, 0, s.Cmp(ScalarFromLE32(le)))
+	}
+	le32 := *le
+	ScalarFromLE32(&le32)
+	assert.Equal(t, *le, le32, "decoding must not reverse the input in place")
+}
+
+func TestScalarEncodingRejectsUnfitValues(t *testing.T) {
+	q := tss.Edwards().Params().N
+	tooLong := new(big.Int).Lsh(big.NewInt(1), 256)
+	for name, s := range map[string]*big.Int{
+		"nil":       nil,
+		"negative":  big.NewInt(-1),
+		"unreduced": q,
+		"too long":  tooLong,
+		"2^256 + 1": new(big.Int).Add(tooLong, big.NewInt(1)),
+		"q + 2^255": new(big.Int).Add(q, new(big.Int).Lsh(big.NewInt(1), 255)),
+		"2^264 - 1": new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 264), big.NewInt(1)),
+	} {
+		_, err := ScalarToBE32(s, q)
+		assert.Error(t, err, name)
+		_, err = ScalarToLE32(s, q)
+		assert.Error(t, err, name)
+	}
+
+	// without a bound only the length is checked
+	maxLen := new(big.Int).Sub(tooLong, big.NewInt(1))
+	_, err := ScalarToLE32(maxLen, nil)
+	assert.NoError(t, err)
+	_, err = ScalarToBE32(maxLen, nil)
+	assert.NoError(t, err)
+	_, err = ScalarToBE32(tooLong, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// AggregateProof is a set of Schnorr proofs from many parties, each with its own session, that is checked with a
+// single combined verification (see BatchVerify). It is used to verify the proofs of all the parties of a DKG round
+// at once.
+type AggregateProof struct {
+	indices []int
+	terms   []proofTerm
+}
+
+// AggregateProofs collects the proofs and public points of the parties, keyed by party index. Every proof must come
+// with its session and public point, and all the points must be on the same curve.
+func AggregateProofs(sessions map[int][]byte, proofs map[int]*ZKProof, Xs map[int]*crypto.ECPoint) (*AggregateProof, error) {
+	if len(proofs) == 0 {
+		return nil, errors.New("AggregateProofs() received no proofs")
+	}
+	if len(proofs) != len(Xs) || len(proofs) != len(sessions) {
+		return nil, errors.New("AggregateProofs() received mismatched proofs, points and sessions")
+	}
+	agg := &AggregateProof{
+		indices: make([]int, 0, len(proofs)),
+		terms:   make([]proofTerm, 0, len(proofs)),
+	}
+	for j := range proofs {
+		agg.indices = append(agg.indices, j)
+	}
+	sort.Ints(agg.indices)
+	for _, j := range agg.indices {
+		session, ok := sessions[j]
+		if !ok {
+			return nil, errors.New("AggregateProofs() received a proof without a session")
+		}
+		X, ok := Xs[j]
+		if !ok || X == nil || !X.ValidateBasic() {
+			return nil, errors.New("AggregateProofs() received a proof without a valid public point")
+		}
+		if len(agg.terms) > 0 && !tss.SameCurve(X.Curve(), agg.terms[0].X.Curve()) {
+			return nil, errors.New("AggregateProofs() received points on different curves")
+		}
+		agg.terms = append(agg.terms, proofTerm{session, proofs[j], X})
+	}
+	return agg, nil
+}
+
+// Verify checks all the proofs with one combined verification. It returns false if any of them is invalid; Culprits
+// then tells which.
+func (agg *AggregateProof) Verify() bool {
+	return agg.verify(agg.terms)
+}
+
+// Culprits returns the sorted indices of the parties whose proofs are invalid, bisecting the set with combined
+// verifications so that a few bad proofs among many are found in a logarithmic number of checks.
+func (agg *AggregateProof) Culprits() []int {
+	var culprits []int
+	var bisect func(lo, hi int)
+	bisect = func(lo, hi int) {
+		if agg.verify(agg.terms[lo:hi]) {
+			return
+		}
+		if hi-lo == 1 {
+			culprits = append(culprits, agg.indices[lo])
+			return
+		}
+		mid := (lo + hi) / 2
+		bisect(lo, mid)
+		bisect(mid, hi)
+	}
+	bisect(0, len(agg.terms))
+	return culprits
+}
+
+// ----- //
+
+func (agg *AggregateProof) verify(terms []proofTerm) bool {
+	if ok, supported := combinedVerify(terms); supported {
+		return ok
+	}
+	for _, term := range terms {
+		if term.pf == nil || !term.pf.Verify(term.session, term.X) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr_test
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// newAggregate returns the proofs of n parties, each with its own session, keyed by party index
+func newAggregate(ec elliptic.Curve, n int) (map[int][]byte, map[int]*ZKProof, map[int]*crypto.ECPoint) {
+	q := ec.Params().N
+	sessions, proofs, Xs := make(map[int][]byte, n), make(map[int]*ZKProof, n), make(map[int]*crypto.ECPoint, n)
+	for j := 0; j < n; j++ {
+		x := common.GetRandomPositiveInt(rand.Reader, q)
+		sessions[j] = []byte(fmt.Sprintf("session-%d", j))
+		Xs[j] = crypto.ScalarBaseMult(ec, x)
+		proofs[j], _ = NewZKProof(sessions[j], x, Xs[j], rand.Reader)
+	}
+	return sessions, proofs, Xs
+}
+
+func TestAggregateProofs(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.BabyJubJub()} {
+		t.Run(ec.Params().Name, func(t *testing.T) {
+			sessions, proofs, Xs := newAggregate(ec, 9)
+			agg, err := AggregateProofs(sessions, proofs, Xs)
+			assert.NoError(t, err)
+			assert.True(t, agg.Verify(), "aggregate must verify")
+			assert.Empty(t, agg.Culprits())
+		})
+	}
+}
+
+func TestAggregateProofsCulprits(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.BabyJubJub()} {
+		for _, bad := range [][]int{{0}, {8}, {3, 4}, {1, 5, 6}} {
+			t.Run(fmt.Sprintf("%s/%v", ec.Params().Name, bad), func(t *testing.T) {
+				sessions, proofs, Xs := newAggregate(ec, 9)
+				for _, j := range bad {
+					// a proof for another session
+					sessions[j] = []byte("other session")
+				}
+				agg, err := AggregateProofs(sessions, proofs, Xs)
+				assert.NoError(t, err)
+				assert.False(t, agg.Verify(), "aggregate must not verify")
+				assert.Equal(t, bad, agg.Culprits())
+			})
+		}
+	}
+}
+
+func TestAggregateProofsCulpritsInvalidProof(t *testing.T) {
+	sessions, proofs, Xs := newAggregate(tss.Edwards(), 5)
+	proofs[1] = nil
+	proofs[3] = &ZKProof{Alpha: proofs[3].Alpha}
+	agg, err := AggregateProofs(sessions, proofs, Xs)
+	assert.NoError(t, err)
+	assert.False(t, agg.Verify())
+	assert.Equal(t, []int{1, 3}, agg.Culprits())
+}
+
+// torsionedProofs returns, for the session, a proof whose Alpha has a component of order 2 and a proof of an X that
+// has one, each with a response that makes t*G == Alpha + c*X hold up to that component only, or exactly in the case
+// of X. Verify must reject both.
+func torsionedProofs(t *testing.T, session []byte) map[string]struct {
+	pf *ZKProof
+	X  *crypto.ECPoint
+} {
+	ec := tss.Edwards()
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	// (0, -1) has order 2
+	torsion, err := crypto.NewECPoint(ec, big.NewInt(0), new(big.Int).Sub(ec.Params().P, big.NewInt(1)))
+	assert.NoError(t, err)
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, x)
+
+	a := common.GetRandomPositiveInt(rand.Reader, q)
+	alpha, err := crypto.ScalarBaseMult(ec, a).Add(torsion)
+	assert.NoError(t, err)
+	pfAlpha := &ZKProof{Alpha: alpha, T: big.NewInt(1)}
+	pfAlpha.T = modQ.Add(a, modQ.Mul(pfAlpha.Challenge(session, X), x))
+
+	// Alpha + c*(X + torsion) == t*G when c is odd, which a prover finds by retrying the nonce
+	torsionedX, err := X.Add(torsion)
+	assert.NoError(t, err)
+	var pfX *ZKProof
+	for pfX == nil {
+		a := common.GetRandomPositiveInt(rand.Reader, q)
+		alpha, err := crypto.ScalarBaseMult(ec, a).Add(torsion)
+		assert.NoError(t, err)
+		pf := &ZKProof{Alpha: alpha, T: big.NewInt(1)}
+		if c := pf.Challenge(session, torsionedX); c.Bit(0) == 1 {
+			pf.T = modQ.Add(a, modQ.Mul(c, x))
+			pfX = pf
+		}
+	}
+	return map[string]struct {
+		pf *ZKProof
+		X  *crypto.ECPoint
+	}{
+		"torsioned Alpha": {pfAlpha, X},
+		"torsioned X":     {pfX, torsionedX},
+	}
+}
+
+func TestAggregateProofsRejectsTorsion(t *testing.T) {
+	for name, bad := range torsionedProofs(t, Session) {
+		t.Run(name, func(t *testing.T) {
+			assert.False(t, bad.pf.Verify(Session, bad.X))
+			sessions, proofs, Xs := newAggregate(tss.Edwards(), 5)
+			sessions[2], proofs[2], Xs[2] = Session, bad.pf, bad.X
+			agg, err := AggregateProofs(sessions, proofs, Xs)
+			assert.NoError(t, err)
+			assert.False(t, agg.Verify(), "aggregate must not verify")
+			assert.Equal(t, []int{2}, agg.Culprits())
+		})
+	}
+}
+
+func TestAggregateProofsRejectsInput(t *testing.T) {
+	_, err := AggregateProofs(nil, nil, nil)
+	assert.Error(t, err)
+
+	sessions, proofs, Xs := newAggregate(tss.Edwards(), 3)
+	delete(sessions, 1)
+	sessions[5] = Session
+	_, err = AggregateProofs(sessions, proofs, Xs)
+	assert.Error(t, err, "a proof without a session must be rejected")
+
+	sessions, proofs, Xs = newAggregate(tss.Edwards(), 3)
+	_, secp, secpXs := newAggregate(tss.S256(), 1)
+	proofs[1], Xs[1] = secp[0], secpXs[0]
+	_, err = AggregateProofs(sessions, proofs, Xs)
+	assert.Error(t, err, "points on different curves must be rejected")
+}
+
+func benchmarkAggregate(b *testing.B, ec elliptic.Curve, verify func(map[int][]byte, map[int]*ZKProof, map[int]*crypto.ECPoint)) {
+	sessions, proofs, Xs := newAggregate(ec, 21)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		verify(sessions, proofs, Xs)
+	}
+}
+
+func aggregateVerify(sessions map[int][]byte, proofs map[int]*ZKProof, Xs map[int]*crypto.ECPoint) {
+	agg, _ := AggregateProofs(sessions, proofs, Xs)
+	agg.Verify()
+}
+
+func loopVerify(sessions map[int][]byte, proofs map[int]*ZKProof, Xs map[int]*crypto.ECPoint) {
+	for j, pf := range proofs {
+		pf.Verify(sessions[j], Xs[j])
+	}
+}
+
+func BenchmarkAggregateProofsVerify21Edwards(b *testing.B) {
+	benchmarkAggregate(b, tss.Edwards(), aggregateVerify)
+}
+
+func BenchmarkVerifyLoop21Edwards(b *testing.B) {
+	benchmarkAggregate(b, tss.Edwards(), loopVerify)
+}
+
+func BenchmarkAggregateProofsVerify21S256(b *testing.B) {
+	benchmarkAggregate(b, tss.S256(), aggregateVerify)
+}
+
+func BenchmarkVerifyLoop21S256(b *testing.B) {
+	benchmarkAggregate(b, tss.S256(), loopVerify)
+}
@@ -0,0 +1,242 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"math/bits"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// batchWeightBits is the bit length of the random weights used to fold the verification equations together.
+// A forged proof passes the combined check with probability at most 2^-batchWeightBits.
+const batchWeightBits = 128
+
+// BatchVerify verifies many Schnorr proofs that share a session with a single random linear combination:
+//
+//	sum r_i*Alpha_i + sum (r_i*c_i)*X_i - (sum r_i*t_i)*G == 0
+//
+// evaluated as one multi-scalar multiplication. The combined check is available for secp256k1 and ed25519; on
+// other curves the proofs are verified one by one. When the combined check fails every proof is verified on its own
+// so that the caller learns which indices are bad. The returned slice lists the indices of the failed proofs and is
+// nil when the whole batch is valid.
+//
+// The result is that of calling Verify on every proof, except with probability 2^-batchWeightBits: the check is not
+// cofactored on ed25519, and a proof whose Alpha or X has a small-order component fails it as it fails Verify.
+func BatchVerify(Session []byte, proofs []*ZKProof, Xs []*crypto.ECPoint) (bool, []int) {
+	if len(proofs) != len(Xs) {
+		return false, nil
+	}
+	if len(proofs) == 0 {
+		return true, nil
+	}
+	terms := make([]proofTerm, len(proofs))
+	for i, pf := range proofs {
+		terms[i] = proofTerm{Session, pf, Xs[i]}
+	}
+	if ok, _ := combinedVerify(terms); ok {
+		return true, nil
+	}
+	failed := make([]int, 0, len(proofs))
+	for i, pf := range proofs {
+		if !pf.Verify(Session, Xs[i]) {
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) == 0 {
+		return true, nil
+	}
+	return false, failed
+}
+
+type proofTerm struct {
+	session []byte
+	pf      *ZKProof
+	X       *crypto.ECPoint
+}
+
+// combinedVerify evaluates the random linear combination of BatchVerify over proofs that may each have their own
+// session. `supported` is false when the curve has no combined check, in which case `ok` is false as well. It applies
+// the checks of VerifyProofWithTranscript to every proof first, and the sum is compared with the identity as it is,
+// without clearing the cofactor of ed25519: with Alpha and X of prime order every equation lives in the subgroup of
+// order q, where the random weights cannot cancel out a proof that Verify rejects.
+func combinedVerify(terms []proofTerm) (ok, supported bool) {
+	if terms[0].X == nil || !terms[0].X.ValidateBasic() {
+		return false, true
+	}
+	ec := terms[0].X.Curve()
+	jacobian := tss.SameCurve(ec, tss.S256())
+	if !jacobian && !tss.SameCurve(ec, tss.Edwards()) {
+		return false, false
+	}
+	ctx := contextFor(ec)
+	g, q := ctx.g, ctx.q
+	modQ := common.ModInt(q)
+	weightBound := new(big.Int).Lsh(big.NewInt(1), batchWeightBits)
+
+	points := make([]*crypto.ECPoint, 0, 2*len(terms)+1)
+	scalars := make([]*big.Int, 0, 2*len(terms)+1)
+	sumT := big.NewInt(0)
+	for _, term := range terms {
+		pf, X := term.pf, term.X
+		if pf == nil || !pf.ValidateBasic() || X == nil || !X.ValidateBasic() || !ctx.onCurve(X, pf.Alpha) ||
+			pf.Alpha.IsInfinity() || !ctx.hasPrimeOrder(X, pf.Alpha) {
+			return false, true
+		}
+		// as in Verify, a response that is not reduced is rejected, and so is a zero response or challenge
+		if pf.T.Sign() <= 0 || pf.T.Cmp(q) >= 0 {
+			return false, true
+		}
+		c := ctx.challenge(NewSHA512Transcript(term.session), X, pf.Alpha)
+		if isZeroMod(c, q) {
+			return false, true
+		}
+		r := common.GetRandomPositiveInt(rand.Reader, weightBound)
+		sumT = modQ.Add(sumT, new(big.Int).Mul(r, pf.T))
+
+		points = append(points, pf.Alpha, X)
+		scalars = append(scalars, r, modQ.Mul(r, c))
+	}
+	points = append(points, g)
+	scalars = append(scalars, modQ.Sub(big.NewInt(0), sumT))
+
+	if jacobian {
+		jPoints := make([]*btcec.JacobianPoint, len(points))
+		for i, p := range points {
+			jPoints[i] = toJacobian(p.X(), p.Y())
+		}
+		sum := multiScalarMult(jPoints, scalars)
+		return isInfinity(&sum), true
+	}
+	x, y := multiScalarMultAffine(ec, points, scalars)
+	return x == nil || (x.Sign() == 0 && y.Cmp(big.NewInt(1)) == 0), true
+}
+
+// ----- //
+// secp256k1 multi-scalar multiplication (Pippenger's bucket method) over btcec's Jacobian points
+
+func toJacobian(x, y *big.Int) *btcec.JacobianPoint {
+	var fx, fy, fz btcec.FieldVal
+	fx.SetByteSlice(x.Bytes())
+	fy.SetByteSlice(y.Bytes())
+	fz.SetInt(1)
+	p := btcec.MakeJacobianPoint(&fx, &fy, &fz)
+	return &p
+}
+
+func isInfinity(p *btcec.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+// the btcec routines do not support aliasing the result with an input
+func jacobianAdd(p1, p2 *btcec.JacobianPoint) (result btcec.JacobianPoint) {
+	btcec.AddNonConst(p1, p2, &result)
+	return
+}
+
+func jacobianDouble(p *btcec.JacobianPoint) (result btcec.JacobianPoint) {
+	btcec.DoubleNonConst(p, &result)
+	return
+}
+
+func multiScalarMult(points []*btcec.JacobianPoint, scalars []*big.Int) btcec.JacobianPoint {
+	window := bits.Len(uint(len(points))) - 2
+	if window < 2 {
+		window = 2
+	}
+	maxBits := 0
+	for _, k := range scalars {
+		if k.BitLen() > maxBits {
+			maxBits = k.BitLen()
+		}
+	}
+	var acc btcec.JacobianPoint
+	buckets := make([]btcec.JacobianPoint, (1<<window)-1)
+	for w := (maxBits + window - 1) / window; w >= 0; w-- {
+		for i := 0; i < window; i++ {
+			acc = jacobianDouble(&acc)
+		}
+		for i := range buckets {
+			buckets[i] = btcec.JacobianPoint{}
+		}
+		for i, k := range scalars {
+			digit := 0
+			for b := window - 1; b >= 0; b-- {
+				digit = digit<<1 | int(k.Bit(w*window+b))
+			}
+			if digit > 0 {
+				buckets[digit-1] = jacobianAdd(&buckets[digit-1], points[i])
+			}
+		}
+		var running, windowSum btcec.JacobianPoint
+		for i := len(buckets) - 1; i >= 0; i-- {
+			running = jacobianAdd(&running, &buckets[i])
+			windowSum = jacobianAdd(&windowSum, &running)
+		}
+		acc = jacobianAdd(&acc, &windowSum)
+	}
+	return acc
+}
+
+// ----- //
+// generic multi-scalar multiplication (Pippenger's bucket method) over affine points, for curves whose additions are
+// much cheaper than their scalar multiplications. A nil x coordinate stands for the point at infinity.
+
+func affineAdd(ec elliptic.Curve, x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x1 == nil {
+		return x2, y2
+	}
+	if x2 == nil {
+		return x1, y1
+	}
+	return ec.Add(x1, y1, x2, y2)
+}
+
+func multiScalarMultAffine(ec elliptic.Curve, points []*crypto.ECPoint, scalars []*big.Int) (x, y *big.Int) {
+	window := bits.Len(uint(len(points))) - 2
+	if window < 2 {
+		window = 2
+	}
+	maxBits := 0
+	for _, k := range scalars {
+		if k.BitLen() > maxBits {
+			maxBits = k.BitLen()
+		}
+	}
+	bucketsX, bucketsY := make([]*big.Int, (1<<window)-1), make([]*big.Int, (1<<window)-1)
+	for w := (maxBits + window - 1) / window; w >= 0; w-- {
+		for i := 0; i < window && x != nil; i++ {
+			x, y = ec.Add(x, y, x, y)
+		}
+		for i := range bucketsX {
+			bucketsX[i], bucketsY[i] = nil, nil
+		}
+		for i, k := range scalars {
+			digit := 0
+			for b := window - 1; b >= 0; b-- {
+				digit = digit<<1 | int(k.Bit(w*window+b))
+			}
+			if digit > 0 {
+				bucketsX[digit-1], bucketsY[digit-1] = affineAdd(ec, bucketsX[digit-1], bucketsY[digit-1], points[i].X(), points[i].Y())
+			}
+		}
+		var runningX, runningY, sumX, sumY *big.Int
+		for i := len(bucketsX) - 1; i >= 0; i-- {
+			runningX, runningY = affineAdd(ec, runningX, runningY, bucketsX[i], bucketsY[i])
+			sumX, sumY = affineAdd(ec, sumX, sumY, runningX, runningY)
+		}
+		x, y = affineAdd(ec, x, y, sumX, sumY)
+	}
+	return x, y
+}
@@ -0,0 +1,60 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func newEdwardsBatch(n int) ([]*ZKProof, []*crypto.ECPoint) {
+	ec := tss.Edwards()
+	proofs, Xs := make([]*ZKProof, n), make([]*crypto.ECPoint, n)
+	for i := 0; i < n; i++ {
+		x := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+		Xs[i] = crypto.ScalarBaseMult(ec, x)
+		proofs[i], _ = NewZKProof(Session, x, Xs[i], rand.Reader)
+	}
+	return proofs, Xs
+}
+
+func TestBatchVerifyEdwards(t *testing.T) {
+	proofs, Xs := newEdwardsBatch(8)
+	ok, failed := BatchVerify(Session, proofs, Xs)
+	assert.True(t, ok, "batch must verify")
+	assert.Empty(t, failed)
+
+	Xs[1], Xs[6] = Xs[6], Xs[1]
+	ok, failed = BatchVerify(Session, proofs, Xs)
+	assert.False(t, ok, "batch must not verify")
+	assert.Equal(t, []int{1, 6}, failed)
+}
+
+func TestBatchVerifyRejectsTorsion(t *testing.T) {
+	for name, bad := range torsionedProofs(t, Session) {
+		t.Run(name, func(t *testing.T) {
+			assert.False(t, bad.pf.Verify(Session, bad.X))
+			ok, failed := BatchVerify(Session, []*ZKProof{bad.pf}, []*crypto.ECPoint{bad.X})
+			assert.False(t, ok)
+			assert.Equal(t, []int{0}, failed)
+
+			// the same among valid proofs, whichever path finds it
+			proofs, Xs := newEdwardsBatch(6)
+			proofs[3], Xs[3] = bad.pf, bad.X
+			ok, failed = BatchVerify(Session, proofs, Xs)
+			assert.False(t, ok, "batch must not verify")
+			assert.Equal(t, []int{3}, failed)
+		})
+	}
+}
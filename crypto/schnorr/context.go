@@ -0,0 +1,290 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Context holds the curve parameters used by the Schnorr proofs so that they are derived once and reused
+// across many proofs. A Context is immutable and safe for concurrent use.
+type Context struct {
+	ec elliptic.Curve
+	g  *crypto.ECPoint
+	q  *big.Int
+	// width is the size in bytes of a coordinate, which the challenges are hashed with
+	width int
+}
+
+// default contexts, lazily created per curve
+var contexts sync.Map // elliptic.Curve -> *Context
+
+func NewContext(ec elliptic.Curve) *Context {
+	ecParams := ec.Params()
+	return &Context{
+		ec:    ec,
+		g:     crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy), // already on the curve.
+		q:     ecParams.N,
+		width: coordinateWidth(ec),
+	}
+}
+
+func contextFor(ec elliptic.Curve) *Context {
+	if ctx, ok := contexts.Load(ec); ok {
+		return ctx.(*Context)
+	}
+	ctx, _ := contexts.LoadOrStore(ec, NewContext(ec))
+	return ctx.(*Context)
+}
+
+// hash returns the hash function of the challenges as currently registered for the curve. It is looked up for every
+// challenge rather than kept in the Context, which is cached per curve, so that a later tss.RegisterCurve applies.
+func (ctx *Context) hash() tss.ChallengeHash {
+	return tss.GetChallengeHash(ctx.ec)
+}
+
+func (ctx *Context) Curve() elliptic.Curve {
+	return ctx.ec
+}
+
+// Prove constructs a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
+func (ctx *Context) Prove(Session []byte, x *big.Int, X *crypto.ECPoint, rand io.Reader) (*ZKProof, error) {
+	if x == nil || X == nil || !X.ValidateBasic() || !ctx.onCurve(X) {
+		return nil, errors.New("ZKProof constructor received nil or invalid value(s)")
+	}
+	a := common.GetRandomPositiveInt(rand, ctx.q)
+	return ctx.ProveWithNonce(Session, x, a, X)
+}
+
+// ProveWithNonce constructs a Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16) using the
+// given nonce `a` in [1, q). The nonce must never be reused with the same witness; this is intended for test vectors.
+func (ctx *Context) ProveWithNonce(Session []byte, x, a *big.Int, X *crypto.ECPoint) (*ZKProof, error) {
+	return ctx.proveWithNonce(NewSHA512Transcript(Session), x, a, X)
+}
+
+// ProveWithTranscript constructs a Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16) whose
+// challenge is derived by the fresh transcript `tr`, which must be bound to the session already
+func (ctx *Context) ProveWithTranscript(tr Transcript, x *big.Int, X *crypto.ECPoint, rand io.Reader) (*ZKProof, error) {
+	if tr == nil || x == nil || X == nil || !X.ValidateBasic() || !ctx.onCurve(X) {
+		return nil, errors.New("ZKProof constructor received nil or invalid value(s)")
+	}
+	a := common.GetRandomPositiveInt(rand, ctx.q)
+	return ctx.proveWithNonce(tr, x, a, X)
+}
+
+func (ctx *Context) proveWithNonce(tr Transcript, x, a *big.Int, X *crypto.ECPoint) (*ZKProof, error) {
+	if x == nil || a == nil || X == nil || !X.ValidateBasic() || !ctx.onCurve(X) {
+		return nil, errors.New("ZKProof constructor received nil or invalid value(s)")
+	}
+	if a.Sign() <= 0 || a.Cmp(ctx.q) >= 0 {
+		return nil, errors.New("ZKProof constructor received a nonce outside of [1, q)")
+	}
+	alpha := crypto.ScalarBaseMult(ctx.ec, a)
+
+	c := ctx.challenge(tr, X, alpha)
+	t := new(big.Int).Mul(c, x)
+	t = common.ModInt(ctx.q).Add(a, t)
+
+	return &ZKProof{Alpha: alpha, T: t}, nil
+}
+
+// VerifyProof verifies a Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
+func (ctx *Context) VerifyProof(Session []byte, pf *ZKProof, X *crypto.ECPoint) bool {
+	return ctx.VerifyProofWithTranscript(NewSHA512Transcript(Session), pf, X)
+}
+
+// VerifyProofWithTranscript verifies a Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
+// that was constructed with a transcript identical to the fresh transcript `tr`
+func (ctx *Context) VerifyProofWithTranscript(tr Transcript, pf *ZKProof, X *crypto.ECPoint) bool {
+	if tr == nil || pf == nil || !pf.ValidateBasic() || X == nil || !ctx.onCurve(X, pf.Alpha) || pf.Alpha.IsInfinity() {
+		return false
+	}
+	// as in the batched checks, which must agree with this one
+	if !ctx.hasPrimeOrder(X, pf.Alpha) {
+		return false
+	}
+	c := ctx.challenge(tr, X, pf.Alpha)
+	if tss.SameCurve(ctx.ec, tss.S256()) {
+		return verifyS256(ctx.q, pf.T, c, X, pf.Alpha)
+	}
+	return ctx.verifyGeneric(c, pf, X)
+}
+
+// verifyGeneric checks t*G == Alpha + c*X with the affine operations of the curve, on the curves without a faster
+// path. Both sides must be points other than the point at infinity, which an honest proof yields with negligible
+// probability; the scalars are checked first as ScalarMult cannot return it on the short Weierstrass curves.
+func (ctx *Context) verifyGeneric(c *big.Int, pf *ZKProof, X *crypto.ECPoint) bool {
+	if isZeroMod(pf.T, ctx.q) || isZeroMod(c, ctx.q) {
+		return false
+	}
+	tG, err := crypto.ScalarBaseMultChecked(ctx.ec, pf.T)
+	if err != nil {
+		return false
+	}
+	Xc := X.ScalarMult(c)
+	aXc, err := pf.Alpha.Add(Xc)
+	if err != nil || aXc.IsInfinity() || tG.IsInfinity() {
+		return false
+	}
+	return aXc.Equal(tG)
+}
+
+// ProveV constructs a new Schnorr ZK proof of knowledge s_i, l_i such that V_i = R^s_i, g^l_i (GG18Spec Fig. 17)
+func (ctx *Context) ProveV(Session []byte, V, R *crypto.ECPoint, s, l *big.Int, rand io.Reader) (*ZKVProof, error) {
+	return ctx.ProveVWithGenerator(Session, V, R, ctx.g, s, l, rand)
+}
+
+// ProveVWithGenerator constructs a Schnorr ZK proof of knowledge s, l such that V = R^s, H^l for the second generator
+// H in place of g. H must be a point of prime order on the curve of the context other than the identity; the proof
+// is bound to it, so it only verifies against the same H.
+func (ctx *Context) ProveVWithGenerator(Session []byte, V, R, H *crypto.ECPoint, s, l *big.Int, rand io.Reader) (*ZKVProof, error) {
+	if V == nil || R == nil || H == nil || s == nil || l == nil || !V.ValidateBasic() || !R.ValidateBasic() || !H.ValidateBasic() {
+		return nil, errors.New("ZKVProof constructor received nil value(s)")
+	}
+	if !ctx.onCurve(V, R, H) {
+		return nil, errors.New("ZKVProof constructor received points on another curve")
+	}
+	if V.IsInfinity() || R.IsInfinity() || H.IsInfinity() {
+		return nil, errors.New("ZKVProof constructor received an identity point")
+	}
+	// a point with a small-order component would leak a mod the cofactor through Alpha, and ScalarMultConst requires
+	// a point of prime order
+	if !V.HasPrimeOrder() || !R.HasPrimeOrder() || !H.HasPrimeOrder() {
+		return nil, errors.New("ZKVProof constructor received a point outside the prime-order subgroup")
+	}
+	a, b := common.GetRandomPositiveInt(rand, ctx.q), common.GetRandomPositiveInt(rand, ctx.q)
+	aR := R.ScalarMultConst(a) // a is secret
+	bH := crypto.ScalarBaseMult(ctx.ec, b)
+	if !H.Equals(ctx.g) {
+		bH = H.ScalarMultConst(b) // b is secret
+	}
+	alpha, err := aR.Add(bH)
+	if err != nil || alpha.IsInfinity() {
+		return nil, errors.New("ZKVProof constructor produced an identity Alpha")
+	}
+
+	c := ctx.challengeV(Session, V, R, H, alpha)
+	modQ := common.ModInt(ctx.q)
+	t := modQ.Add(a, new(big.Int).Mul(c, s))
+	u := modQ.Add(b, new(big.Int).Mul(c, l))
+
+	return &ZKVProof{Alpha: alpha, T: t, U: u}, nil
+}
+
+// VerifyVProof verifies a Schnorr ZK proof of knowledge s_i, l_i such that V_i = R^s_i, g^l_i (GG18Spec Fig. 17)
+func (ctx *Context) VerifyVProof(Session []byte, pf *ZKVProof, V, R *crypto.ECPoint) bool {
+	return ctx.VerifyVProofWithGenerator(Session, pf, V, R, ctx.g)
+}
+
+// VerifyVProofWithGenerator verifies a Schnorr ZK proof of knowledge s, l such that V = R^s, H^l that was constructed
+// by ProveVWithGenerator with the same second generator H
+func (ctx *Context) VerifyVProofWithGenerator(Session []byte, pf *ZKVProof, V, R, H *crypto.ECPoint) bool {
+	if pf == nil || !pf.ValidateBasic() || V == nil || R == nil || H == nil || !ctx.onCurve(V, R, H, pf.Alpha) {
+		return false
+	}
+	if pf.Alpha.IsInfinity() || V.IsInfinity() || R.IsInfinity() || H.IsInfinity() {
+		return false
+	}
+	// on the curves with a cofactor, t*R only depends on t mod q for an R of prime order
+	if !V.HasPrimeOrder() || !R.HasPrimeOrder() || !H.HasPrimeOrder() {
+		return false
+	}
+	c := ctx.challengeV(Session, V, R, H, pf.Alpha)
+	// as in verifyGeneric, neither side may be the point at infinity
+	if isZeroMod(pf.T, ctx.q) || isZeroMod(pf.U, ctx.q) || isZeroMod(c, ctx.q) {
+		return false
+	}
+	tR, err := R.ScalarMultChecked(pf.T)
+	if err != nil {
+		return false
+	}
+	var uH *crypto.ECPoint
+	if H.Equals(ctx.g) {
+		uH, err = crypto.ScalarBaseMultChecked(ctx.ec, pf.U)
+	} else {
+		uH, err = H.ScalarMultChecked(pf.U)
+	}
+	if err != nil {
+		return false
+	}
+	tRuH, err := tR.Add(uH)
+	if err != nil || tRuH.IsInfinity() {
+		return false
+	}
+
+	Vc := V.ScalarMult(c)
+	aVc, err := pf.Alpha.Add(Vc)
+	if err != nil || aVc.IsInfinity() {
+		return false
+	}
+	return tRuH.Equal(aVc)
+}
+
+// generatorOrDefault returns the optional second generator of a ZKVProof, or g if none is given
+func (ctx *Context) generatorOrDefault(H []*crypto.ECPoint) *crypto.ECPoint {
+	if len(H) > 0 && H[0] != nil {
+		return H[0]
+	}
+	return ctx.g
+}
+
+// isZeroMod reports whether k is a multiple of q, for which k*P is the point at infinity for every P of order q
+func isZeroMod(k, q *big.Int) bool {
+	return new(big.Int).Mod(k, q).Sign() == 0
+}
+
+// challenge derives the challenge of a ZKProof: the statement X, the generator and the commitment Alpha, in order
+func (ctx *Context) challenge(tr Transcript, X, alpha *crypto.ECPoint) *big.Int {
+	tr.AppendPoint("X", X)
+	tr.AppendPoint("G", ctx.g)
+	tr.AppendPoint("Alpha", alpha)
+	return tr.Challenge(ctx.q)
+}
+
+// challengeV derives the challenge of a ZKVProof from the statement V, R, the second generator H and the commitment
+// Alpha
+func (ctx *Context) challengeV(Session []byte, V, R, H, alpha *crypto.ECPoint) *big.Int {
+	in := []*big.Int{V.X(), V.Y(), R.X(), R.Y(), H.X(), H.Y(), alpha.X(), alpha.Y()}
+	var cHash *big.Int
+	if encoded, ok := encodeFE(ctx.width, in); ok {
+		cHash = taggedHashFE(ctx.hash(), Session, ctx.width, encoded)
+	} else {
+		cHash = taggedHash(ctx.hash(), Session, in...)
+	}
+	return common.RejectionSample(ctx.q, cHash)
+}
+
+// onCurve reports whether all the points belong to the curve of the context; mixing curves would make the group
+// operations meaningless
+func (ctx *Context) onCurve(points ...*crypto.ECPoint) bool {
+	for _, p := range points {
+		if !tss.SameCurve(p.Curve(), ctx.ec) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPrimeOrder reports whether all the points lie in the subgroup of prime order q. On ed25519 and BabyJubJub a
+// small-order component of X or Alpha could be traded against c mod the cofactor, and an even random weight of the
+// combined check would cancel it.
+func (ctx *Context) hasPrimeOrder(points ...*crypto.ECPoint) bool {
+	for _, p := range points {
+		if !p.HasPrimeOrder() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr
+
+import (
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+)
+
+// verifyS256 reports whether t*G - c*X == Alpha on secp256k1. The two products are computed and summed in Jacobian
+// coordinates and the sum is compared with Alpha without converting it back to affine, so the check costs a single
+// pass over the points instead of the two affine multiplications and the addition of the generic path.
+//
+// This is not a joint (Shamir) double-scalar multiplication: btcec multiplies by G with a precomputed table and by
+// X with the GLV endomorphism, which together are cheaper on this curve than an interleaved loop over both 256-bit
+// scalars.
+//
+// As with the checked ECPoint operations of the generic path, a t that is not below q is rejected, not reduced.
+func verifyS256(q, t, c *big.Int, X, alpha *crypto.ECPoint) bool {
+	if t.Sign() < 0 || t.Cmp(q) >= 0 {
+		return false
+	}
+	jX, ok := toJacobianChecked(X.X(), X.Y())
+	if !ok {
+		return false
+	}
+	var tScalar, negC btcec.ModNScalar
+	tScalar.SetByteSlice(new(big.Int).Mod(t, q).Bytes())
+	negC.SetByteSlice(new(big.Int).Mod(c, q).Bytes())
+	negC.Negate()
+
+	var tG, cX, sum btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&tScalar, &tG)
+	btcec.ScalarMultNonConst(&negC, jX, &cX)
+	btcec.AddNonConst(&tG, &cX, &sum)
+	return jacobianEqualsAffine(&sum, alpha.X(), alpha.Y())
+}
+
+// toJacobianChecked is toJacobian for coordinates that have not been validated; ok is false when one of them does
+// not fit below the field prime
+func toJacobianChecked(x, y *big.Int) (p *btcec.JacobianPoint, ok bool) {
+	if x.Sign() < 0 || y.Sign() < 0 || x.BitLen() > 256 || y.BitLen() > 256 {
+		return nil, false
+	}
+	var fx, fy btcec.FieldVal
+	if fx.SetByteSlice(x.Bytes()) || fy.SetByteSlice(y.Bytes()) {
+		return nil, false
+	}
+	return toJacobian(x, y), true
+}
+
+// jacobianEqualsAffine reports whether the Jacobian point p (X, Y, Z) is the affine point (x, y), i.e. whether
+// X == x*Z^2 and Y == y*Z^3. The point at infinity equals no affine point.
+func jacobianEqualsAffine(p *btcec.JacobianPoint, x, y *big.Int) bool {
+	if x.Sign() < 0 || y.Sign() < 0 || x.BitLen() > 256 || y.BitLen() > 256 {
+		return false
+	}
+	var fx, fy btcec.FieldVal
+	if fx.SetByteSlice(x.Bytes()) || fy.SetByteSlice(y.Bytes()) {
+		return false
+	}
+	var z2, z3, px, py btcec.FieldVal
+	z2.SquareVal(&p.Z).Normalize()
+	if z2.IsZero() {
+		return false
+	}
+	z3.Mul2(&z2, &p.Z).Normalize()
+	fx.Mul(&z2).Normalize()
+	fy.Mul(&z3).Normalize()
+	px.Set(&p.X).Normalize()
+	py.Set(&p.Y).Normalize()
+	return px.Equals(&fx) && py.Equals(&fy)
+}
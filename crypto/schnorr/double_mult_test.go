@@ -0,0 +1,95 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// TestVerifyS256MatchesGeneric checks the secp256k1 path of VerifyProof against the generic affine check on valid
+// proofs and on proofs that are broken in each of their inputs
+func TestVerifyS256MatchesGeneric(t *testing.T) {
+	ec := tss.S256()
+	ctx := contextFor(ec)
+	q := ec.Params().N
+	one := big.NewInt(1)
+	for i := 0; i < 32; i++ {
+		x := common.GetRandomPositiveInt(rand.Reader, q)
+		X := crypto.ScalarBaseMult(ec, x)
+		pf, err := ctx.Prove([]byte("session"), x, X, rand.Reader)
+		assert.NoError(t, err)
+		c := ctx.challenge(NewSHA512Transcript([]byte("session")), X, pf.Alpha)
+		other := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+
+		cases := []struct {
+			name  string
+			c, T  *big.Int
+			X     *crypto.ECPoint
+			alpha *crypto.ECPoint
+			valid bool
+		}{
+			{"valid", c, pf.T, X, pf.Alpha, true},
+			{"T + q", c, new(big.Int).Add(pf.T, q), X, pf.Alpha, false},
+			{"T + 1", c, new(big.Int).Add(pf.T, one), X, pf.Alpha, false},
+			{"c + 1", new(big.Int).Add(c, one), pf.T, X, pf.Alpha, false},
+			{"other X", c, pf.T, other, pf.Alpha, false},
+			{"other Alpha", c, pf.T, X, other, false},
+			{"Alpha = X", c, pf.T, X, X, false},
+		}
+		for _, tc := range cases {
+			expected := ctx.verifyGeneric(tc.c, &ZKProof{Alpha: tc.alpha, T: tc.T}, tc.X)
+			assert.Equal(t, tc.valid, expected, tc.name)
+			assert.Equal(t, expected, verifyS256(q, tc.T, tc.c, tc.X, tc.alpha), tc.name)
+		}
+	}
+}
+
+// TestVerifyS256Doubling covers t*G == -c*X, where the two summed products are the same point, and t*G == c*X, where
+// their sum is the point at infinity
+func TestVerifyS256Doubling(t *testing.T) {
+	ec := tss.S256()
+	q := ec.Params().N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, x)
+	c := common.GetRandomPositiveInt(rand.Reader, q)
+	cx := new(big.Int).Mul(c, x)
+	// t = -c*x: t*G - c*X = -2c*X
+	tNeg := new(big.Int).Mod(new(big.Int).Neg(cx), q)
+	alpha := crypto.ScalarBaseMult(ec, new(big.Int).Mod(new(big.Int).Lsh(tNeg, 1), q))
+	assert.True(t, verifyS256(q, tNeg, c, X, alpha))
+	// t = c*x: t*G - c*X is the point at infinity, which is never a valid Alpha
+	tPos := new(big.Int).Mod(cx, q)
+	assert.False(t, verifyS256(q, tPos, c, X, crypto.ScalarBaseMult(ec, big.NewInt(1))))
+}
+
+func BenchmarkVerifyS256(b *testing.B) {
+	ec := tss.S256()
+	ctx := contextFor(ec)
+	q := ec.Params().N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, x)
+	pf, _ := ctx.Prove([]byte("session"), x, X, rand.Reader)
+	c := ctx.challenge(NewSHA512Transcript([]byte("session")), X, pf.Alpha)
+	b.Run("generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctx.verifyGeneric(c, pf, X)
+		}
+	})
+	b.Run("jacobian", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			verifyS256(q, pf.T, c, X, pf.Alpha)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+)
+
+const (
+	zkProofParts  = 3 // Alpha.X, Alpha.Y, T
+	zkVProofParts = 4 // Alpha.X, Alpha.Y, T, U
+)
+
+// Bytes encodes the proof as a sequence of length-prefixed big-endian integers: Alpha.X, Alpha.Y, T
+func (pf *ZKProof) Bytes() ([]byte, error) {
+	if pf == nil || !pf.ValidateBasic() {
+		return nil, errors.New("ZKProof.Bytes() called on a nil or invalid proof")
+	}
+	return encodeLengthPrefixed(pf.Alpha.X(), pf.Alpha.Y(), pf.T), nil
+}
+
+// ZKProofFromBytes decodes a proof produced by ZKProof.Bytes and checks that Alpha is on the curve `ec`
+func ZKProofFromBytes(ec elliptic.Curve, bz []byte) (*ZKProof, error) {
+	parts, err := decodeLengthPrefixed(bz, zkProofParts)
+	if err != nil {
+		return nil, fmt.Errorf("ZKProofFromBytes: %v", err)
+	}
+	alpha, err := crypto.NewECPoint(ec, parts[0], parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("ZKProofFromBytes: %v", err)
+	}
+	return &ZKProof{Alpha: alpha, T: parts[2]}, nil
+}
+
+// Bytes encodes the proof as a sequence of length-prefixed big-endian integers: Alpha.X, Alpha.Y, T, U
+func (pf *ZKVProof) Bytes() ([]byte, error) {
+	if pf == nil || !pf.ValidateBasic() {
+		return nil, errors.New("ZKVProof.Bytes() called on a nil or invalid proof")
+	}
+	return encodeLengthPrefixed(pf.Alpha.X(), pf.Alpha.Y(), pf.T, pf.U), nil
+}
+
+// ZKVProofFromBytes decodes a proof produced by ZKVProof.Bytes and checks that Alpha is on the curve `ec`
+func ZKVProofFromBytes(ec elliptic.Curve, bz []byte) (*ZKVProof, error) {
+	parts, err := decodeLengthPrefixed(bz, zkVProofParts)
+	if err != nil {
+		return nil, fmt.Errorf("ZKVProofFromBytes: %v", err)
+	}
+	alpha, err := crypto.NewECPoint(ec, parts[0], parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("ZKVProofFromBytes: %v", err)
+	}
+	return &ZKVProof{Alpha: alpha, T: parts[2], U: parts[3]}, nil
+}
+
+// ----- //
+
+func encodeLengthPrefixed(in ...*big.Int) []byte {
+	buf := &bytes.Buffer{}
+	for _, n := range in {
+		bz := n.Bytes()
+		lenBz := make([]byte, 4)
+		binary.LittleEndian.PutUint32(lenBz, uint32(len(bz)))
+		buf.Write(lenBz)
+		buf.Write(bz)
+	}
+	return buf.Bytes()
+}
+
+func decodeLengthPrefixed(bz []byte, expectParts int) ([]*big.Int, error) {
+	out := make([]*big.Int, 0, expectParts)
+	for len(bz) > 0 {
+		if len(bz) < 4 {
+			return nil, errors.New("truncated length prefix")
+		}
+		length := binary.LittleEndian.Uint32(bz[:4])
+		bz = bz[4:]
+		if uint64(len(bz)) < uint64(length) {
+			return nil, fmt.Errorf("truncated input: expected %d bytes, got %d", length, len(bz))
+		}
+		out = append(out, new(big.Int).SetBytes(bz[:length]))
+		bz = bz[length:]
+	}
+	if len(out) != expectParts {
+		return nil, fmt.Errorf("expected %d parts, got %d", expectParts, len(out))
+	}
+	return out, nil
+}
@@ -0,0 +1,99 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// multiTag separates the coefficients of an aggregate statement from the other hashes of the session
+var multiTag = big.NewInt(0x6d756c7469) // "multi"
+
+// NewZKProofAggregate constructs a single Schnorr ZK proof of knowledge of the discrete logarithms xs of all the
+// points Xs, of the size of one proof. The statements are combined as X = sum(mu_i*X_i) with coefficients mu_i that
+// are derived from the session and all the points, so that none of them can be chosen to cancel the others, and X is
+// proven with a challenge that is bound to all the points as well. A single statement is proven with NewZKProof.
+func NewZKProofAggregate(Session []byte, xs []*big.Int, Xs []*crypto.ECPoint, rand io.Reader) (*ZKProof, error) {
+	if len(xs) == 0 || len(xs) != len(Xs) {
+		return nil, errors.New("NewZKProofAggregate() received mismatched or no witnesses and points")
+	}
+	for _, x := range xs {
+		if x == nil {
+			return nil, errors.New("NewZKProofAggregate() received a nil witness")
+		}
+	}
+	if len(Xs) == 1 {
+		return NewZKProof(Session, xs[0], Xs[0], rand)
+	}
+	ctx, mus, X, tr, err := aggregateStatement(Session, Xs)
+	if err != nil {
+		return nil, err
+	}
+	x := new(big.Int)
+	for i, mu := range mus {
+		x.Add(x, new(big.Int).Mul(mu, xs[i]))
+	}
+	return ctx.ProveWithTranscript(tr, x.Mod(x, ctx.q), X, rand)
+}
+
+// VerifyAggregate verifies a proof of NewZKProofAggregate for the points Xs, given in the same order
+func (pf *ZKProof) VerifyAggregate(Session []byte, Xs []*crypto.ECPoint) bool {
+	if pf == nil || !pf.ValidateBasic() || len(Xs) == 0 {
+		return false
+	}
+	if len(Xs) == 1 {
+		return pf.Verify(Session, Xs[0])
+	}
+	ctx, _, X, tr, err := aggregateStatement(Session, Xs)
+	if err != nil {
+		return false
+	}
+	return ctx.VerifyProofWithTranscript(tr, pf, X)
+}
+
+// ----- //
+
+// aggregateStatement returns the coefficients of the points, their combination and the transcript of the proof of
+// it, which holds all the points
+func aggregateStatement(Session []byte, Xs []*crypto.ECPoint) (*Context, []*big.Int, *crypto.ECPoint, Transcript, error) {
+	in := make([]*big.Int, 0, 2*len(Xs)+2)
+	in = append(in, multiTag, big.NewInt(int64(len(Xs))))
+	for _, X := range Xs {
+		if X == nil || !X.ValidateBasic() || !tss.SameCurve(X.Curve(), Xs[0].Curve()) {
+			return nil, nil, nil, nil, errors.New("aggregate statement with nil, invalid or mixed-curve points")
+		}
+		in = append(in, X.X(), X.Y())
+	}
+	ctx := contextFor(Xs[0].Curve())
+	digest := taggedHash(ctx.hash(), Session, in...)
+	mus := make([]*big.Int, len(Xs))
+	for i := range Xs {
+		mus[i] = common.RejectionSample(ctx.q, taggedHash(ctx.hash(), Session, digest, big.NewInt(int64(i))))
+		if mus[i].Sign() == 0 {
+			return nil, nil, nil, nil, errors.New("aggregate statement with a zero coefficient")
+		}
+	}
+	x, y := multiScalarMultAffine(ctx.ec, Xs, mus)
+	if x == nil {
+		return nil, nil, nil, nil, errors.New("aggregate statement that combines to the identity")
+	}
+	X, err := crypto.NewECPoint(ctx.ec, x, y)
+	if err != nil || X.IsInfinity() {
+		return nil, nil, nil, nil, errors.New("aggregate statement that combines to the identity")
+	}
+	tr := NewSHA512Transcript(Session)
+	for _, Xi := range Xs {
+		tr.AppendPoint("X_i", Xi)
+	}
+	return ctx, mus, X, tr, nil
+}
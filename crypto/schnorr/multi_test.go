@@ -0,0 +1,98 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr_test
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestZKProofAggregateOfOneIsZKProof(t *testing.T) {
+	q := tss.EC().Params().N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(tss.EC(), x)
+
+	// with the same randomness, both constructors make the same proof
+	aggregate, err := NewZKProofAggregate(Session, []*big.Int{x}, []*crypto.ECPoint{X}, mrand.New(mrand.NewSource(1)))
+	assert.NoError(t, err)
+	proof, err := NewZKProof(Session, x, X, mrand.New(mrand.NewSource(1)))
+	assert.NoError(t, err)
+	assert.True(t, proof.Alpha.Equals(aggregate.Alpha))
+	assert.Equal(t, proof.T, aggregate.T)
+
+	assert.True(t, aggregate.Verify(Session, X))
+	assert.True(t, proof.VerifyAggregate(Session, []*crypto.ECPoint{X}))
+}
+
+func TestZKProofAggregate(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.BabyJubJub()} {
+		t.Run(ec.Params().Name, func(t *testing.T) {
+			q := ec.Params().N
+			xs, Xs := make([]*big.Int, 4), make([]*crypto.ECPoint, 4)
+			for i := range xs {
+				xs[i] = common.GetRandomPositiveInt(rand.Reader, q)
+				Xs[i] = crypto.ScalarBaseMult(ec, xs[i])
+			}
+			proof, err := NewZKProofAggregate(Session, xs, Xs, rand.Reader)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.True(t, proof.VerifyAggregate(Session, Xs))
+
+			assert.False(t, proof.VerifyAggregate([]byte("another session"), Xs))
+			assert.False(t, proof.VerifyAggregate(Session, Xs[:3]), "a statement left out")
+			assert.False(t, proof.VerifyAggregate(Session, []*crypto.ECPoint{Xs[1], Xs[0], Xs[2], Xs[3]}), "statements reordered")
+			assert.False(t, proof.Verify(Session, Xs[0]))
+
+			// one wrong witness breaks the whole proof
+			wrong := append([]*big.Int{}, xs...)
+			wrong[2] = new(big.Int).Add(wrong[2], big.NewInt(1))
+			proof, err = NewZKProofAggregate(Session, wrong, Xs, rand.Reader)
+			assert.NoError(t, err)
+			assert.False(t, proof.VerifyAggregate(Session, Xs))
+
+			// X_2 = -X_1 must not cancel out of the statement
+			neg := []*big.Int{xs[0], new(big.Int).Sub(q, xs[0])}
+			negXs := []*crypto.ECPoint{Xs[0], crypto.ScalarBaseMult(ec, neg[1])}
+			proof, err = NewZKProofAggregate(Session, neg, negXs, rand.Reader)
+			assert.NoError(t, err)
+			assert.True(t, proof.VerifyAggregate(Session, negXs))
+		})
+	}
+}
+
+func TestZKProofAggregateRejectsInput(t *testing.T) {
+	q := tss.EC().Params().N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(tss.EC(), x)
+	Y := crypto.ScalarBaseMult(tss.Edwards(), x)
+
+	_, err := NewZKProofAggregate(Session, nil, nil, rand.Reader)
+	assert.Error(t, err)
+	_, err = NewZKProofAggregate(Session, []*big.Int{x}, []*crypto.ECPoint{X, X}, rand.Reader)
+	assert.Error(t, err)
+	_, err = NewZKProofAggregate(Session, []*big.Int{x, nil}, []*crypto.ECPoint{X, X}, rand.Reader)
+	assert.Error(t, err)
+	_, err = NewZKProofAggregate(Session, []*big.Int{x, x}, []*crypto.ECPoint{X, Y}, rand.Reader)
+	assert.Error(t, err, "points on different curves")
+
+	proof, err := NewZKProofAggregate(Session, []*big.Int{x, x}, []*crypto.ECPoint{X, X}, rand.Reader)
+	assert.NoError(t, err)
+	assert.False(t, proof.VerifyAggregate(Session, nil))
+	assert.False(t, proof.VerifyAggregate(Session, []*crypto.ECPoint{X, nil}))
+	assert.False(t, (*ZKProof)(nil).VerifyAggregate(Session, []*crypto.ECPoint{X, X}))
+}
@@ -11,7 +11,6 @@ import (
 	"io"
 	"math/big"
 
-	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 )
 
@@ -27,109 +26,87 @@ type (
 	}
 )
 
-// NewZKProof constructs a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
-func NewZKProof(Session []byte, x *big.Int, X *crypto.ECPoint, rand io.Reader) (*ZKProof, error) {
+// NewZKProof constructs a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16). An optional
+// fresh transcript, already bound to the session, replaces the default one that is built from Session.
+func NewZKProof(Session []byte, x *big.Int, X *crypto.ECPoint, rand io.Reader, transcript ...Transcript) (*ZKProof, error) {
 	if x == nil || X == nil || !X.ValidateBasic() {
 		return nil, errors.New("ZKProof constructor received nil or invalid value(s)")
 	}
-	ec := X.Curve()
-	ecParams := ec.Params()
-	q := ecParams.N
-	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy) // already on the curve.
-
-	a := common.GetRandomPositiveInt(rand, q)
-	alpha := crypto.ScalarBaseMult(ec, a)
+	return contextFor(X.Curve()).ProveWithTranscript(transcriptOrDefault(Session, transcript), x, X, rand)
+}
 
-	var c *big.Int
-	{
-		cHash := common.SHA512_256i_TAGGED(Session, X.X(), X.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
-		c = common.RejectionSample(q, cHash)
+// NewZKProofWithNonce constructs a Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16) from the
+// fixed nonce `a` in [1, q), e.g. to reproduce golden test vectors. Never reuse a nonce with the same witness.
+func NewZKProofWithNonce(Session []byte, x, a *big.Int, X *crypto.ECPoint) (*ZKProof, error) {
+	if x == nil || X == nil || !X.ValidateBasic() {
+		return nil, errors.New("ZKProof constructor received nil or invalid value(s)")
 	}
-	t := new(big.Int).Mul(c, x)
-	t = common.ModInt(q).Add(a, t)
-
-	return &ZKProof{Alpha: alpha, T: t}, nil
+	return contextFor(X.Curve()).ProveWithNonce(Session, x, a, X)
 }
 
-// NewZKProof verifies a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
-func (pf *ZKProof) Verify(Session []byte, X *crypto.ECPoint) bool {
-	if pf == nil || !pf.ValidateBasic() {
+// NewZKProof verifies a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16). An optional
+// fresh transcript must match the one the proof was constructed with. A proof whose Alpha is a point of another curve
+// than X never verifies, even with the same coordinates.
+func (pf *ZKProof) Verify(Session []byte, X *crypto.ECPoint, transcript ...Transcript) bool {
+	if pf == nil || !pf.ValidateBasic() || X == nil {
 		return false
 	}
-	ec := X.Curve()
-	ecParams := ec.Params()
-	q := ecParams.N
-	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy)
+	return contextFor(X.Curve()).VerifyProofWithTranscript(transcriptOrDefault(Session, transcript), pf, X)
+}
 
-	var c *big.Int
-	{
-		cHash := common.SHA512_256i_TAGGED(Session, X.X(), X.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
-		c = common.RejectionSample(q, cHash)
+// Challenge returns the Fiat-Shamir challenge c that Verify derives for the proof of X, e.g. for an external verifier
+// that checks t*G == Alpha + c*X itself. An optional fresh transcript must match the one the proof was constructed
+// with. It returns nil if the proof or X is nil or invalid.
+func (pf *ZKProof) Challenge(Session []byte, X *crypto.ECPoint, transcript ...Transcript) *big.Int {
+	if pf == nil || !pf.ValidateBasic() || X == nil || !X.ValidateBasic() {
+		return nil
 	}
-	tG := crypto.ScalarBaseMult(ec, pf.T)
-	Xc := X.ScalarMult(c)
-	aXc, err := pf.Alpha.Add(Xc)
-	if err != nil {
-		return false
+	ctx := contextFor(X.Curve())
+	if !ctx.onCurve(pf.Alpha) {
+		return nil
 	}
-	return aXc.X().Cmp(tG.X()) == 0 && aXc.Y().Cmp(tG.Y()) == 0
+	return ctx.challenge(transcriptOrDefault(Session, transcript), X, pf.Alpha)
 }
 
 func (pf *ZKProof) ValidateBasic() bool {
 	return pf.T != nil && pf.Alpha != nil
 }
 
-// NewZKProof constructs a new Schnorr ZK proof of knowledge s_i, l_i such that V_i = R^s_i, g^l_i (GG18Spec Fig. 17)
-func NewZKVProof(Session []byte, V, R *crypto.ECPoint, s, l *big.Int, rand io.Reader) (*ZKVProof, error) {
+// NewZKProof constructs a new Schnorr ZK proof of knowledge s_i, l_i such that V_i = R^s_i, g^l_i (GG18Spec Fig. 17).
+// An optional second generator H replaces g, for the protocols that prove V = R^s, H^l; the proof then only verifies
+// with the same H.
+func NewZKVProof(Session []byte, V, R *crypto.ECPoint, s, l *big.Int, rand io.Reader, H ...*crypto.ECPoint) (*ZKVProof, error) {
 	if V == nil || R == nil || s == nil || l == nil || !V.ValidateBasic() || !R.ValidateBasic() {
 		return nil, errors.New("ZKVProof constructor received nil value(s)")
 	}
-	ec := V.Curve()
-	ecParams := ec.Params()
-	q := ecParams.N
-	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy)
-
-	a, b := common.GetRandomPositiveInt(rand, q), common.GetRandomPositiveInt(rand, q)
-	aR := R.ScalarMult(a)
-	bG := crypto.ScalarBaseMult(ec, b)
-	alpha, _ := aR.Add(bG) // already on the curve.
-
-	var c *big.Int
-	{
-		cHash := common.SHA512_256i_TAGGED(Session, V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
-		c = common.RejectionSample(q, cHash)
-	}
-	modQ := common.ModInt(q)
-	t := modQ.Add(a, new(big.Int).Mul(c, s))
-	u := modQ.Add(b, new(big.Int).Mul(c, l))
-
-	return &ZKVProof{Alpha: alpha, T: t, U: u}, nil
+	ctx := contextFor(V.Curve())
+	return ctx.ProveVWithGenerator(Session, V, R, ctx.generatorOrDefault(H), s, l, rand)
 }
 
-func (pf *ZKVProof) Verify(Session []byte, V, R *crypto.ECPoint) bool {
-	if pf == nil || !pf.ValidateBasic() {
+// Verify verifies a Schnorr ZK proof of knowledge s_i, l_i such that V_i = R^s_i, g^l_i (GG18Spec Fig. 17). An
+// optional second generator H must match the one the proof was constructed with. All the points, Alpha included, must
+// be on the curve of V.
+func (pf *ZKVProof) Verify(Session []byte, V, R *crypto.ECPoint, H ...*crypto.ECPoint) bool {
+	if pf == nil || !pf.ValidateBasic() || V == nil {
 		return false
 	}
-	ec := V.Curve()
-	ecParams := ec.Params()
-	q := ecParams.N
-	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy)
+	ctx := contextFor(V.Curve())
+	return ctx.VerifyVProofWithGenerator(Session, pf, V, R, ctx.generatorOrDefault(H))
+}
 
-	var c *big.Int
-	{
-		cHash := common.SHA512_256i_TAGGED(Session, V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
-		c = common.RejectionSample(q, cHash)
+// Challenge returns the Fiat-Shamir challenge c that Verify derives for the proof of V and R, e.g. for an external
+// verifier that checks t*R + u*H == Alpha + c*V itself, with H = G unless a second generator is given. It returns nil
+// if the proof, V, R or H is nil or invalid.
+func (pf *ZKVProof) Challenge(Session []byte, V, R *crypto.ECPoint, H ...*crypto.ECPoint) *big.Int {
+	if pf == nil || !pf.ValidateBasic() || V == nil || R == nil || !V.ValidateBasic() || !R.ValidateBasic() {
+		return nil
 	}
-	tR := R.ScalarMult(pf.T)
-	uG := crypto.ScalarBaseMult(ec, pf.U)
-	tRuG, _ := tR.Add(uG) // already on the curve.
-
-	Vc := V.ScalarMult(c)
-	aVc, err := pf.Alpha.Add(Vc)
-	if err != nil {
-		return false
+	ctx := contextFor(V.Curve())
+	gen := ctx.generatorOrDefault(H)
+	if !gen.ValidateBasic() || !ctx.onCurve(R, gen, pf.Alpha) {
+		return nil
 	}
-	return tRuG.X().Cmp(aVc.X()) == 0 && tRuG.Y().Cmp(aVc.Y()) == 0
+	return ctx.challengeV(Session, V, R, gen, pf.Alpha)
 }
 
 func (pf *ZKVProof) ValidateBasic() bool {
@@ -7,7 +7,13 @@
 package schnorr_test
 
 import (
+	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"math/big"
+	mrand "math/rand"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,6 +38,33 @@ func TestSchnorrProof(t *testing.T) {
 	assert.NotZero(t, proof.T)
 }
 
+func TestSchnorrProofsP256(t *testing.T) {
+	ec := tss.P256()
+	q := ec.Params().N
+	u := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, u)
+	proof, err := NewZKProof(Session, u, X, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify(Session, X))
+	assert.False(t, proof.Verify([]byte("other session"), X))
+
+	k := common.GetRandomPositiveInt(rand.Reader, q)
+	s := common.GetRandomPositiveInt(rand.Reader, q)
+	l := common.GetRandomPositiveInt(rand.Reader, q)
+	R := crypto.ScalarBaseMult(ec, k)
+	V, err := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+	assert.NoError(t, err)
+	vProof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, vProof.Verify(Session, V, R))
+
+	// the challenge is hashed from fixed-width coordinates, with the digest of their big.Int encodings
+	G := crypto.ScalarBaseMult(ec, big.NewInt(1))
+	expected := common.RejectionSample(q, common.SHA512_256i_TAGGED(Session,
+		V.X(), V.Y(), R.X(), R.Y(), G.X(), G.Y(), vProof.Alpha.X(), vProof.Alpha.Y()))
+	assert.Equal(t, 0, expected.Cmp(vProof.Challenge(Session, V, R)))
+}
+
 func TestSchnorrProofVerify(t *testing.T) {
 	q := tss.EC().Params().N
 	u := common.GetRandomPositiveInt(rand.Reader, q)
@@ -103,3 +136,634 @@ func TestSchnorrVProofVerifyBadS(t *testing.T) {
 
 	assert.False(t, res, "verify result must be false")
 }
+
+func TestSchnorrProofBytesRoundTrip(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.BabyJubJub()} {
+		q := ec.Params().N
+		u := common.GetRandomPositiveInt(rand.Reader, q)
+		X := crypto.ScalarBaseMult(ec, u)
+
+		proof, err := NewZKProof(Session, u, X, rand.Reader)
+		assert.NoError(t, err)
+		bz, err := proof.Bytes()
+		assert.NoError(t, err)
+
+		proof2, err := ZKProofFromBytes(ec, bz)
+		assert.NoError(t, err)
+		assert.True(t, proof2.Verify(Session, X), "decoded proof must verify")
+
+		_, err = ZKProofFromBytes(ec, bz[:len(bz)-1])
+		assert.Error(t, err, "truncated input must be rejected")
+	}
+}
+
+func TestSchnorrVProofBytesRoundTrip(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards(), tss.BabyJubJub()} {
+		q := ec.Params().N
+		k := common.GetRandomPositiveInt(rand.Reader, q)
+		s := common.GetRandomPositiveInt(rand.Reader, q)
+		l := common.GetRandomPositiveInt(rand.Reader, q)
+		R := crypto.ScalarBaseMult(ec, k)
+		Rs := R.ScalarMult(s)
+		lG := crypto.ScalarBaseMult(ec, l)
+		V, _ := Rs.Add(lG)
+
+		proof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+		assert.NoError(t, err)
+		bz, err := proof.Bytes()
+		assert.NoError(t, err)
+
+		proof2, err := ZKVProofFromBytes(ec, bz)
+		assert.NoError(t, err)
+		assert.True(t, proof2.Verify(Session, V, R), "decoded proof must verify")
+
+		_, err = ZKVProofFromBytes(ec, bz[:3])
+		assert.Error(t, err, "truncated input must be rejected")
+	}
+}
+
+func TestSchnorrProofDoesNotWriteToStdout(t *testing.T) {
+	q := tss.EC().Params().N
+	u := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(tss.EC(), u)
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	os.Stdout = w
+	_, err = NewZKProof(Session, u, X, rand.Reader)
+	os.Stdout = stdout
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Empty(t, out, "NewZKProof must not write to stdout")
+}
+
+func newBatch(n int) ([]*ZKProof, []*crypto.ECPoint) {
+	q := tss.EC().Params().N
+	proofs, Xs := make([]*ZKProof, n), make([]*crypto.ECPoint, n)
+	for i := 0; i < n; i++ {
+		x := common.GetRandomPositiveInt(rand.Reader, q)
+		Xs[i] = crypto.ScalarBaseMult(tss.EC(), x)
+		proofs[i], _ = NewZKProof(Session, x, Xs[i], rand.Reader)
+	}
+	return proofs, Xs
+}
+
+func TestSchnorrProofBatchVerify(t *testing.T) {
+	proofs, Xs := newBatch(8)
+	ok, failed := BatchVerify(Session, proofs, Xs)
+	assert.True(t, ok, "batch must verify")
+	assert.Empty(t, failed)
+
+	// swap in statements that don't match two of the proofs
+	Xs[2], Xs[5] = Xs[5], Xs[2]
+	ok, failed = BatchVerify(Session, proofs, Xs)
+	assert.False(t, ok, "batch must not verify")
+	assert.Equal(t, []int{2, 5}, failed)
+}
+
+func BenchmarkSchnorrProofBatchVerify(b *testing.B) {
+	proofs, Xs := newBatch(16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(Session, proofs, Xs)
+	}
+}
+
+func BenchmarkSchnorrProofVerifyLoop(b *testing.B) {
+	proofs, Xs := newBatch(16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, pf := range proofs {
+			pf.Verify(Session, Xs[j])
+		}
+	}
+}
+
+func TestSchnorrContextMatchesPackageFunctions(t *testing.T) {
+	ec := tss.EC()
+	ctx := NewContext(ec)
+	q := ec.Params().N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, x)
+
+	proof1, err := NewZKProof(Session, x, X, mrand.New(mrand.NewSource(1)))
+	assert.NoError(t, err)
+	proof2, err := ctx.Prove(Session, x, X, mrand.New(mrand.NewSource(1)))
+	assert.NoError(t, err)
+	assert.True(t, proof1.Alpha.Equals(proof2.Alpha))
+	assert.Equal(t, 0, proof1.T.Cmp(proof2.T))
+	assert.True(t, ctx.VerifyProof(Session, proof1, X))
+	assert.True(t, proof2.Verify(Session, X))
+
+	s := common.GetRandomPositiveInt(rand.Reader, q)
+	l := common.GetRandomPositiveInt(rand.Reader, q)
+	R := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+	V, _ := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+
+	vProof1, err := NewZKVProof(Session, V, R, s, l, mrand.New(mrand.NewSource(2)))
+	assert.NoError(t, err)
+	vProof2, err := ctx.ProveV(Session, V, R, s, l, mrand.New(mrand.NewSource(2)))
+	assert.NoError(t, err)
+	assert.True(t, vProof1.Alpha.Equals(vProof2.Alpha))
+	assert.Equal(t, 0, vProof1.T.Cmp(vProof2.T))
+	assert.Equal(t, 0, vProof1.U.Cmp(vProof2.U))
+	assert.True(t, ctx.VerifyVProof(Session, vProof1, V, R))
+	assert.True(t, vProof2.Verify(Session, V, R))
+}
+
+func TestSchnorrProofWithNonceGoldenVector(t *testing.T) {
+	x, a := big.NewInt(0x1234567), big.NewInt(0x89abcdef)
+	X := crypto.ScalarBaseMult(tss.S256(), x)
+
+	proof, err := NewZKProofWithNonce(Session, x, a, X)
+	assert.NoError(t, err)
+	assert.Equal(t, "795eeb6658e2ddb7acb7143461d18aef2d6286f72804ee05216fc6ef35edf118", hex.EncodeToString(proof.Alpha.X().Bytes()))
+	assert.Equal(t, "578a6bb1c09e4af206f2dea7ebd7a01c149e520379b7eaa9470de32e7f2eb1a3", hex.EncodeToString(proof.Alpha.Y().Bytes()))
+	assert.Equal(t, "5a8b515527e5dea0dfbfc53284d9044388ac5608f49667df87b6a84521187e13", hex.EncodeToString(proof.T.Bytes()))
+	assert.True(t, proof.Verify(Session, X))
+
+	q := tss.S256().Params().N
+	for _, bad := range []*big.Int{big.NewInt(0), q, new(big.Int).Add(q, big.NewInt(1)), big.NewInt(-1)} {
+		_, err = NewZKProofWithNonce(Session, x, bad, X)
+		assert.Error(t, err, "nonce %s must be rejected", bad)
+	}
+}
+
+func TestSchnorrProofVerifyRejectsIdentityAlpha(t *testing.T) {
+	ec := tss.Edwards()
+	identity, err := crypto.NewECPoint(ec, big.NewInt(0), big.NewInt(1))
+	assert.NoError(t, err)
+
+	// with X = Alpha = identity and T = 0 the verification equation holds trivially
+	proof := &ZKProof{Alpha: identity, T: big.NewInt(0)}
+	assert.False(t, proof.Verify(Session, identity), "identity Alpha must be rejected")
+}
+
+func TestSchnorrVProofRejectsIdentity(t *testing.T) {
+	ec := tss.Edwards()
+	q := ec.Params().N
+	identity, err := crypto.NewECPoint(ec, big.NewInt(0), big.NewInt(1))
+	assert.NoError(t, err)
+
+	proof := &ZKVProof{Alpha: identity, T: big.NewInt(0), U: big.NewInt(0)}
+	assert.False(t, proof.Verify(Session, identity, identity), "identity Alpha, V and R must be rejected")
+
+	s := common.GetRandomPositiveInt(rand.Reader, q)
+	l := common.GetRandomPositiveInt(rand.Reader, q)
+	R := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+	V, _ := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+	_, err = NewZKVProof(Session, identity, R, s, l, rand.Reader)
+	assert.Error(t, err, "identity V must be rejected")
+	_, err = NewZKVProof(Session, V, identity, s, l, rand.Reader)
+	assert.Error(t, err, "identity R must be rejected")
+}
+
+func TestSchnorrVProofBJJ(t *testing.T) {
+	newVProof := func(ec elliptic.Curve) (*ZKVProof, *crypto.ECPoint, *crypto.ECPoint) {
+		q := ec.Params().N
+		k, s, l := common.GetRandomPositiveInt(rand.Reader, q), common.GetRandomPositiveInt(rand.Reader, q),
+			common.GetRandomPositiveInt(rand.Reader, q)
+		R := crypto.ScalarBaseMult(ec, k)
+		V, _ := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+		proof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+		assert.NoError(t, err)
+		return proof, V, R
+	}
+	bjjProof, bjjV, bjjR := newVProof(tss.BabyJubJub())
+	edProof, edV, edR := newVProof(tss.Edwards())
+	assert.True(t, bjjProof.Verify(Session, bjjV, bjjR))
+	assert.True(t, edProof.Verify(Session, edV, edR))
+
+	// proofs and points of different curves never verify together
+	assert.False(t, edProof.Verify(Session, bjjV, bjjR))
+	assert.False(t, bjjProof.Verify(Session, edV, edR))
+	assert.False(t, bjjProof.Verify(Session, bjjV, edR))
+	_, err := NewZKVProof(Session, bjjV, edR, big.NewInt(1), big.NewInt(1), rand.Reader)
+	assert.Error(t, err)
+
+	// likewise for the proof of knowledge of a discrete logarithm
+	x := common.GetRandomPositiveInt(rand.Reader, tss.BabyJubJub().Params().N)
+	bjjX := crypto.ScalarBaseMult(tss.BabyJubJub(), x)
+	proof, err := NewZKProof(Session, x, bjjX, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify(Session, bjjX))
+	assert.False(t, proof.Verify(Session, crypto.ScalarBaseMult(tss.Edwards(), x)))
+}
+
+func TestSchnorrProofsGrumpkin(t *testing.T) {
+	ec := tss.Grumpkin()
+	q := ec.Params().N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, x)
+	proof, err := NewZKProof(Session, x, X, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify(Session, X))
+	assert.False(t, proof.Verify([]byte("other session"), X))
+	assert.False(t, proof.Verify(Session, crypto.ScalarBaseMult(ec, new(big.Int).Add(x, big.NewInt(1)))))
+	assert.False(t, (&ZKProof{Alpha: proof.Alpha, T: new(big.Int).Add(proof.T, big.NewInt(1))}).Verify(Session, X))
+	ok, failed := BatchVerify(Session, []*ZKProof{proof, proof}, []*crypto.ECPoint{X, X})
+	assert.True(t, ok)
+	assert.Empty(t, failed)
+
+	// the encoding of a proof keeps its curve
+	bz, err := proof.Bytes()
+	if assert.NoError(t, err) {
+		decoded, err := ZKProofFromBytes(ec, bz)
+		if assert.NoError(t, err) {
+			assert.True(t, decoded.Verify(Session, X))
+		}
+	}
+
+	k, s, l := common.GetRandomPositiveInt(rand.Reader, q), common.GetRandomPositiveInt(rand.Reader, q),
+		common.GetRandomPositiveInt(rand.Reader, q)
+	R := crypto.ScalarBaseMult(ec, k)
+	V, err := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+	assert.NoError(t, err)
+	vProof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, vProof.Verify(Session, V, R))
+	assert.False(t, vProof.Verify(Session, V, crypto.ScalarBaseMult(ec, new(big.Int).Add(k, big.NewInt(1)))))
+
+	// the same scalars on BabyJubJub, whose base field is the same, give points that do not verify on Grumpkin
+	bjjX := crypto.ScalarBaseMult(tss.BabyJubJub(), x)
+	assert.False(t, proof.Verify(Session, bjjX))
+}
+
+func TestSchnorrProofsRejectCrossCurveAlpha(t *testing.T) {
+	for _, pair := range [][2]elliptic.Curve{
+		{tss.BabyJubJub(), tss.Edwards()},
+		{tss.S256(), tss.BabyJubJub()},
+		{tss.Edwards(), tss.S256()},
+	} {
+		ec, other := pair[0], pair[1]
+		name := ec.Params().Name + "/" + other.Params().Name
+		q := ec.Params().N
+		x := common.GetRandomPositiveInt(rand.Reader, q)
+		X := crypto.ScalarBaseMult(ec, x)
+		proof, err := NewZKProof(Session, x, X, rand.Reader)
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		otherX := crypto.ScalarBaseMult(other, common.GetRandomPositiveInt(rand.Reader, other.Params().N))
+		otherProof, err := NewZKProof(Session, x, otherX, rand.Reader)
+		assert.NoError(t, err, name)
+
+		// the same coordinates tagged with the other curve, and a commitment of the other curve
+		relabelled := crypto.NewECPointNoCurveCheck(other, proof.Alpha.X(), proof.Alpha.Y())
+		for label, alpha := range map[string]*crypto.ECPoint{"relabelled": relabelled, "foreign": otherProof.Alpha} {
+			bad := &ZKProof{Alpha: alpha, T: proof.T}
+			assert.False(t, bad.Verify(Session, X), "%s: %s Alpha", name, label)
+			assert.False(t, NewContext(ec).VerifyProof(Session, bad, X), "%s: %s Alpha", name, label)
+			assert.Nil(t, bad.Challenge(Session, X), "%s: %s Alpha", name, label)
+			ok, culprits := BatchVerify(Session, []*ZKProof{proof, bad}, []*crypto.ECPoint{X, X})
+			assert.False(t, ok, "%s: %s Alpha", name, label)
+			assert.Equal(t, []int{1}, culprits, "%s: %s Alpha", name, label)
+		}
+		assert.False(t, proof.Verify(Session, otherX), name)
+		assert.False(t, otherProof.Verify(Session, X), name)
+
+		// a context of one curve does not prove statements of another
+		_, err = NewContext(other).Prove(Session, x, X, rand.Reader)
+		assert.Error(t, err, name)
+		_, err = NewContext(other).ProveWithNonce(Session, x, big.NewInt(1), X)
+		assert.Error(t, err, name)
+
+		// likewise for the commitment of a ZKVProof
+		s, l := common.GetRandomPositiveInt(rand.Reader, q), common.GetRandomPositiveInt(rand.Reader, q)
+		R := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+		V, _ := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+		vProof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		bad := &ZKVProof{Alpha: crypto.NewECPointNoCurveCheck(other, vProof.Alpha.X(), vProof.Alpha.Y()), T: vProof.T, U: vProof.U}
+		assert.False(t, bad.Verify(Session, V, R), name)
+		assert.Nil(t, bad.Challenge(Session, V, R), name)
+		_, err = NewContext(other).ProveV(Session, V, R, s, l, rand.Reader)
+		assert.Error(t, err, name)
+		_, err = NewZKVProof(Session, V, otherX, s, l, rand.Reader)
+		assert.Error(t, err, name)
+		assert.False(t, vProof.Verify(Session, V, otherX), name)
+	}
+}
+
+func TestSchnorrVProofRejectsLowOrderR(t *testing.T) {
+	ec := tss.Edwards()
+	q := ec.Params().N
+	// (0, -1) has order 2, and R + (0, -1) has order 2q
+	lowOrder, err := crypto.NewECPoint(ec, big.NewInt(0), new(big.Int).Sub(ec.Params().P, big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.False(t, lowOrder.HasPrimeOrder())
+	R := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+	mixed, err := R.Add(lowOrder)
+	assert.NoError(t, err)
+	assert.False(t, mixed.HasPrimeOrder())
+
+	s := common.GetRandomPositiveInt(rand.Reader, q)
+	l := common.GetRandomPositiveInt(rand.Reader, q)
+	for name, badR := range map[string]*crypto.ECPoint{"low-order R": lowOrder, "mixed-order R": mixed} {
+		V, err := badR.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+		assert.NoError(t, err)
+		_, err = NewZKVProof(Session, V, badR, s, l, rand.Reader)
+		assert.Error(t, err, name)
+
+		// a proof computed as NewZKVProof would without the check
+		a, b := common.GetRandomPositiveInt(rand.Reader, q), common.GetRandomPositiveInt(rand.Reader, q)
+		alpha, err := badR.ScalarMult(a).Add(crypto.ScalarBaseMult(ec, b))
+		assert.NoError(t, err)
+		G := crypto.ScalarBaseMult(ec, big.NewInt(1))
+		c := common.RejectionSample(q, common.SHA512_256i_TAGGED(Session, V.X(), V.Y(), badR.X(), badR.Y(), G.X(), G.Y(), alpha.X(), alpha.Y()))
+		modQ := common.ModInt(q)
+		proof := &ZKVProof{Alpha: alpha, T: modQ.Add(a, new(big.Int).Mul(c, s)), U: modQ.Add(b, new(big.Int).Mul(c, l))}
+		assert.False(t, proof.Verify(Session, V, badR), name)
+	}
+
+	// the same statement over a prime-order R still verifies
+	V, _ := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+	proof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify(Session, V, R))
+	assert.True(t, R.HasPrimeOrder())
+}
+
+func TestSchnorrVProofWithGenerator(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards()} {
+		name := ec.Params().Name
+		q := ec.Params().N
+		s, l := common.GetRandomPositiveInt(rand.Reader, q), common.GetRandomPositiveInt(rand.Reader, q)
+		R := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+		H := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+		H2 := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+		V, err := R.ScalarMult(s).Add(H.ScalarMult(l))
+		assert.NoError(t, err, name)
+
+		proof, err := NewZKVProof(Session, V, R, s, l, rand.Reader, H)
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		assert.True(t, proof.Verify(Session, V, R, H), name)
+		ctx := NewContext(ec)
+		assert.True(t, ctx.VerifyVProofWithGenerator(Session, proof, V, R, H), name)
+		c := proof.Challenge(Session, V, R, H)
+		if assert.NotNil(t, c, name) {
+			lhs, _ := R.ScalarMult(proof.T).Add(H.ScalarMult(proof.U))
+			rhs, _ := proof.Alpha.Add(V.ScalarMult(c))
+			assert.True(t, lhs.Equals(rhs), name)
+		}
+
+		// the proof is bound to H: neither another generator nor the default G verifies it
+		assert.False(t, proof.Verify(Session, V, R, H2), name)
+		assert.False(t, proof.Verify(Session, V, R), name)
+		assert.False(t, proof.Verify(Session, V, H, R), name)
+
+		// G given explicitly is the default
+		G := crypto.ScalarBaseMult(ec, big.NewInt(1))
+		VG, _ := R.ScalarMult(s).Add(G.ScalarMult(l))
+		proofG, err := NewZKVProof(Session, VG, R, s, l, rand.Reader, G)
+		assert.NoError(t, err, name)
+		assert.True(t, proofG.Verify(Session, VG, R), name)
+		assert.Equal(t, proofG.Challenge(Session, VG, R), proofG.Challenge(Session, VG, R, G), name)
+
+		// H must be a valid point of the curve other than the identity
+		offCurve := crypto.NewECPointNoCurveCheck(ec, H.X(), new(big.Int).Add(H.Y(), big.NewInt(1)))
+		_, err = NewZKVProof(Session, V, R, s, l, rand.Reader, offCurve)
+		assert.Error(t, err, name)
+		assert.False(t, proof.Verify(Session, V, R, offCurve), name)
+		_, err = NewZKVProof(Session, V, R, s, l, rand.Reader, crypto.ScalarBaseMult(tss.P256(), big.NewInt(2)))
+		assert.Error(t, err, name)
+	}
+
+	ec := tss.Edwards()
+	q := ec.Params().N
+	s, l := common.GetRandomPositiveInt(rand.Reader, q), common.GetRandomPositiveInt(rand.Reader, q)
+	R := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+	V, _ := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+	identity, err := crypto.NewECPoint(ec, big.NewInt(0), big.NewInt(1))
+	assert.NoError(t, err)
+	_, err = NewZKVProof(Session, V, R, s, l, rand.Reader, identity)
+	assert.Error(t, err, "identity H must be rejected")
+	lowOrder, err := crypto.NewECPoint(ec, big.NewInt(0), new(big.Int).Sub(ec.Params().P, big.NewInt(1)))
+	assert.NoError(t, err)
+	_, err = NewZKVProof(Session, V, R, s, l, rand.Reader, lowOrder)
+	assert.Error(t, err, "low-order H must be rejected")
+}
+
+func TestSchnorrProofChallengeMatchesVerify(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.Edwards()} {
+		q := ec.Params().N
+		x := common.GetRandomPositiveInt(rand.Reader, q)
+		X := crypto.ScalarBaseMult(ec, x)
+		proof, err := NewZKProof(Session, x, X, rand.Reader)
+		assert.NoError(t, err)
+
+		// the equation that Verify checks holds with the exposed challenge
+		c := proof.Challenge(Session, X)
+		if assert.NotNil(t, c) {
+			aXc, err := proof.Alpha.Add(X.ScalarMult(c))
+			assert.NoError(t, err)
+			assert.True(t, crypto.ScalarBaseMult(ec, proof.T).Equals(aXc))
+		}
+		assert.NotEqual(t, 0, c.Cmp(proof.Challenge([]byte("other session"), X)))
+		assert.Equal(t, 0, c.Cmp(proof.Challenge(nil, X, NewSHA512Transcript(Session))))
+		assert.Nil(t, proof.Challenge(Session, nil))
+		assert.Nil(t, (*ZKProof)(nil).Challenge(Session, X))
+
+		k := common.GetRandomPositiveInt(rand.Reader, q)
+		s := common.GetRandomPositiveInt(rand.Reader, q)
+		l := common.GetRandomPositiveInt(rand.Reader, q)
+		R := crypto.ScalarBaseMult(ec, k)
+		V, err := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+		assert.NoError(t, err)
+		vProof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+		assert.NoError(t, err)
+		assert.True(t, vProof.Verify(Session, V, R))
+
+		cV := vProof.Challenge(Session, V, R)
+		if assert.NotNil(t, cV) {
+			tRuG, err := R.ScalarMult(vProof.T).Add(crypto.ScalarBaseMult(ec, vProof.U))
+			assert.NoError(t, err)
+			aVc, err := vProof.Alpha.Add(V.ScalarMult(cV))
+			assert.NoError(t, err)
+			assert.True(t, tRuG.Equals(aVc))
+		}
+		assert.NotEqual(t, 0, cV.Cmp(vProof.Challenge([]byte("other session"), V, R)))
+		assert.Nil(t, vProof.Challenge(Session, V, nil))
+	}
+}
+
+func TestSchnorrProofsRejectUnreducedScalars(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), tss.P256(), tss.Edwards()} {
+		q := ec.Params().N
+		u := common.GetRandomPositiveInt(rand.Reader, q)
+		X := crypto.ScalarBaseMult(ec, u)
+		proof, err := NewZKProof(Session, u, X, rand.Reader)
+		assert.NoError(t, err)
+		assert.True(t, proof.Verify(Session, X))
+		for _, T := range []*big.Int{
+			new(big.Int).Set(q),
+			new(big.Int).Add(q, big.NewInt(5)),
+			// the same response mod q
+			new(big.Int).Add(proof.T, q),
+		} {
+			bad := &ZKProof{Alpha: proof.Alpha, T: T}
+			assert.False(t, bad.Verify(Session, X), "T = %s on %s", T, ec.Params().Name)
+			ok, failed := BatchVerify(Session, []*ZKProof{proof, bad}, []*crypto.ECPoint{X, X})
+			assert.False(t, ok)
+			assert.Equal(t, []int{1}, failed)
+		}
+
+		k := common.GetRandomPositiveInt(rand.Reader, q)
+		s := common.GetRandomPositiveInt(rand.Reader, q)
+		l := common.GetRandomPositiveInt(rand.Reader, q)
+		R := crypto.ScalarBaseMult(ec, k)
+		V, err := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+		assert.NoError(t, err)
+		vProof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+		assert.NoError(t, err)
+		assert.True(t, vProof.Verify(Session, V, R))
+		for _, bad := range []*ZKVProof{
+			{Alpha: vProof.Alpha, T: new(big.Int).Add(vProof.T, q), U: vProof.U},
+			{Alpha: vProof.Alpha, T: vProof.T, U: new(big.Int).Add(vProof.U, q)},
+		} {
+			assert.False(t, bad.Verify(Session, V, R), "on %s", ec.Params().Name)
+		}
+	}
+}
+
+func TestSchnorrProofsRejectInfinity(t *testing.T) {
+	// on P-256 the proofs are checked with ECPoint arithmetic, whose ScalarMult cannot return the point at infinity:
+	// the scalars that lead to it must fail the verification instead of panicking
+	ec := tss.P256()
+	q := ec.Params().N
+	u := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, u)
+	proof, err := NewZKProof(Session, u, X, rand.Reader)
+	assert.NoError(t, err)
+	for _, T := range []*big.Int{big.NewInt(0), new(big.Int).Set(q), new(big.Int).Lsh(q, 1)} {
+		bad := &ZKProof{Alpha: proof.Alpha, T: T}
+		assert.NotPanics(t, func() {
+			assert.False(t, bad.Verify(Session, X), "t*G at infinity must not verify")
+		})
+	}
+
+	k := common.GetRandomPositiveInt(rand.Reader, q)
+	s := common.GetRandomPositiveInt(rand.Reader, q)
+	l := common.GetRandomPositiveInt(rand.Reader, q)
+	R := crypto.ScalarBaseMult(ec, k)
+	V, err := R.ScalarMult(s).Add(crypto.ScalarBaseMult(ec, l))
+	assert.NoError(t, err)
+	vProof, err := NewZKVProof(Session, V, R, s, l, rand.Reader)
+	assert.NoError(t, err)
+	for _, bad := range []*ZKVProof{
+		{Alpha: vProof.Alpha, T: big.NewInt(0), U: vProof.U},
+		{Alpha: vProof.Alpha, T: vProof.T, U: new(big.Int).Set(q)},
+		// t*R + u*G = P + (-P)
+		{Alpha: vProof.Alpha, T: big.NewInt(1), U: new(big.Int).Sub(q, k)},
+	} {
+		assert.NotPanics(t, func() {
+			assert.False(t, bad.Verify(Session, V, R))
+		})
+	}
+
+	// on ed25519 P + (-P) is the identity point (0, 1), which must not pass either
+	edwardsX := crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(5))
+	negX, err := crypto.NewECPoint(tss.Edwards(), new(big.Int).Sub(tss.Edwards().Params().P, edwardsX.X()), edwardsX.Y())
+	assert.NoError(t, err)
+	identity, err := edwardsX.Add(negX)
+	assert.NoError(t, err)
+	assert.True(t, identity.IsInfinity())
+	assert.False(t, (&ZKProof{Alpha: identity, T: big.NewInt(0)}).Verify(Session, edwardsX))
+}
+
+func TestSchnorrProofChallengeHashPerCurve(t *testing.T) {
+	// two copies of P-256 under other names, with other challenge hashes
+	curves := make([]elliptic.Curve, 0, 3)
+	curves = append(curves, tss.P256())
+	for name, hash := range map[tss.CurveName]tss.ChallengeHash{
+		"p256-sha256":    tss.SHA256Challenge,
+		"p256-keccak256": tss.Keccak256Challenge,
+	} {
+		params := *elliptic.P256().Params()
+		params.Name = string(name)
+		tss.RegisterCurve(name, &params, hash)
+		assert.Equal(t, hash, tss.GetChallengeHash(&params))
+		curves = append(curves, &params)
+	}
+	assert.Equal(t, tss.SHA512_256Challenge, tss.GetChallengeHash(tss.P256()))
+
+	q := tss.P256().Params().N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	a := common.GetRandomPositiveInt(rand.Reader, q)
+	var proofs []*ZKProof
+	var Xs []*crypto.ECPoint
+	challenges := make(map[string]struct{})
+	for _, ec := range curves {
+		X := crypto.ScalarBaseMult(ec, x)
+		proof, err := NewZKProofWithNonce(Session, x, a, X)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, proof.Verify(Session, X), ec.Params().Name)
+		c := proof.Challenge(Session, X)
+		tv := new(big.Int).Add(a, new(big.Int).Mul(c, x))
+		assert.Equal(t, 0, tv.Mod(tv, q).Cmp(proof.T), "%s: t = a + c*x", ec.Params().Name)
+		challenges[c.String()] = struct{}{}
+
+		random, err := NewZKProof(Session, x, X, rand.Reader)
+		assert.NoError(t, err)
+		assert.True(t, random.Verify(Session, X))
+		V, err := X.ScalarMult(x).Add(X)
+		assert.NoError(t, err)
+		vProof, err := NewZKVProof(Session, V, X, x, x, rand.Reader)
+		assert.NoError(t, err)
+		assert.True(t, vProof.Verify(Session, V, X))
+		proofs, Xs = append(proofs, proof), append(Xs, X)
+	}
+	assert.Len(t, challenges, len(curves), "every hash gives another challenge")
+
+	// a proof only verifies with the hash of its curve
+	for i := range proofs {
+		for j, ec := range curves {
+			if i == j {
+				continue
+			}
+			Xj, err := crypto.NewECPoint(ec, Xs[i].X(), Xs[i].Y())
+			assert.NoError(t, err)
+			alpha, err := crypto.NewECPoint(ec, proofs[i].Alpha.X(), proofs[i].Alpha.Y())
+			assert.NoError(t, err)
+			assert.False(t, (&ZKProof{Alpha: alpha, T: proofs[i].T}).Verify(Session, Xj))
+		}
+	}
+}
+
+func TestSchnorrProofChallengeHashChangedAfterUse(t *testing.T) {
+	params := *elliptic.P256().Params()
+	params.Name = "p256-late"
+	tss.RegisterCurve("p256-late", &params)
+	defer tss.RegisterCurve("p256-late", &params, tss.SHA512_256Challenge)
+
+	q := params.N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(&params, x)
+	before, err := NewZKProof(Session, x, X, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, before.Verify(Session, X))
+	c := before.Challenge(Session, X)
+
+	// the proofs made and verified since do not keep using the first hash
+	tss.RegisterCurve("p256-late", &params, tss.Keccak256Challenge)
+	assert.NotEqual(t, 0, c.Cmp(before.Challenge(Session, X)))
+	assert.False(t, before.Verify(Session, X))
+	after, err := NewZKProof(Session, x, X, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, after.Verify(Session, X))
+	tr := NewSHA512Transcript(Session)
+	withTranscript, err := NewContext(&params).ProveWithTranscript(tr, x, X, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, after.Verify(Session, X, NewSHA512Transcript(Session)))
+	assert.True(t, withTranscript.Verify(Session, X))
+}
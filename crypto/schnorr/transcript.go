@@ -0,0 +1,145 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Transcript derives the Fiat–Shamir challenge of a proof from the elements appended to it, in order. It lets the
+// challenge derivation be swapped, e.g. for a Merlin/STROBE transcript to interoperate with other proof systems. A
+// transcript is used for one proof only; the prover and the verifier must each start from an identical one, bound
+// to the same session.
+type Transcript interface {
+	AppendPoint(label string, p *crypto.ECPoint)
+	AppendScalar(label string, s *big.Int)
+	// Challenge returns the challenge in [0, q) for the elements appended so far
+	Challenge(q *big.Int) *big.Int
+}
+
+// sha512Transcript is the default transcript: SHA512_256i_TAGGED over the coordinates and scalars, tagged with the
+// session, followed by RejectionSample. The labels are not hashed. The elements are encoded as field elements of the
+// size of the coordinates of the first point as they are appended, and hashed with SHA512_256i_TAGGED_FE for the same
+// digest; an element that does not fit that size sends the transcript back to hashing big.Ints. The curve of the
+// first point may be registered with another hash than SHA-512/256, see tss.RegisterCurve, which is then used instead.
+type sha512Transcript struct {
+	session []byte
+	ins     []*big.Int
+	width   int
+	encoded []byte
+	hash    tss.ChallengeHash
+	curve   bool // whether hash was taken from the curve of a point
+}
+
+// NewSHA512Transcript returns the transcript that the proofs use unless they are given another one
+func NewSHA512Transcript(session []byte) Transcript {
+	return &sha512Transcript{session: session}
+}
+
+func (tr *sha512Transcript) AppendPoint(_ string, p *crypto.ECPoint) {
+	if !tr.curve {
+		tr.hash, tr.curve = tss.GetChallengeHash(p.Curve()), true
+	}
+	if len(tr.ins) == 0 && tr.width == 0 {
+		tr.width = coordinateWidth(p.Curve())
+	}
+	tr.append(p.X(), p.Y())
+}
+
+func (tr *sha512Transcript) AppendScalar(_ string, s *big.Int) {
+	tr.append(s)
+}
+
+func (tr *sha512Transcript) append(in ...*big.Int) {
+	if len(tr.ins) == 0 && tr.width > 0 {
+		start := len(tr.encoded)
+		tr.encoded = append(tr.encoded, make([]byte, tr.width*len(in))...)
+		fits := true
+		for i, n := range in {
+			fits = fits && fillFE(tr.encoded[start+i*tr.width:start+(i+1)*tr.width], n)
+		}
+		if fits {
+			return
+		}
+		tr.decode(start)
+	}
+	tr.ins = append(tr.ins, in...)
+}
+
+// decode moves the elements encoded before the offset `end` to tr.ins and stops encoding
+func (tr *sha512Transcript) decode(end int) {
+	tr.ins = make([]*big.Int, 0, end/tr.width+2)
+	for start := 0; start < end; start += tr.width {
+		tr.ins = append(tr.ins, new(big.Int).SetBytes(tr.encoded[start:start+tr.width]))
+	}
+	tr.width, tr.encoded = 0, nil
+}
+
+func (tr *sha512Transcript) Challenge(q *big.Int) *big.Int {
+	if len(tr.ins) == 0 && len(tr.encoded) > 0 {
+		return common.RejectionSample(q, taggedHashFE(tr.hash, tr.session, tr.width, tr.encoded))
+	}
+	return common.RejectionSample(q, taggedHash(tr.hash, tr.session, tr.ins...))
+}
+
+// taggedHash is SHA512_256i_TAGGED, or common.TaggedHashi with another challenge hash
+func taggedHash(h tss.ChallengeHash, tag []byte, in ...*big.Int) *big.Int {
+	if h == tss.SHA512_256Challenge {
+		return common.SHA512_256i_TAGGED(tag, in...)
+	}
+	return common.TaggedHashi(h.New, tag, in...)
+}
+
+// taggedHashFE is SHA512_256i_TAGGED_FE, or common.TaggedHashiFE with another challenge hash
+func taggedHashFE(h tss.ChallengeHash, tag []byte, width int, in []byte) *big.Int {
+	if h == tss.SHA512_256Challenge {
+		return common.SHA512_256i_TAGGED_FE(tag, width, in)
+	}
+	return common.TaggedHashiFE(h.New, tag, width, in)
+}
+
+// coordinateWidth returns the size in bytes of the coordinates of the points of the curve
+func coordinateWidth(ec elliptic.Curve) int {
+	return (ec.Params().P.BitLen() + 7) / 8
+}
+
+// fillFE encodes n into dst as a big-endian field element, treating nil as zero as SHA512_256i_TAGGED does. It
+// returns false if n is negative or does not fit.
+func fillFE(dst []byte, n *big.Int) bool {
+	if n == nil {
+		return true
+	}
+	if n.Sign() < 0 || n.BitLen() > 8*len(dst) {
+		return false
+	}
+	n.FillBytes(dst)
+	return true
+}
+
+// encodeFE returns the inputs encoded as consecutive field elements of `width` bytes, or false if one does not fit
+func encodeFE(width int, in []*big.Int) ([]byte, bool) {
+	encoded := make([]byte, width*len(in))
+	for i, n := range in {
+		if !fillFE(encoded[i*width:(i+1)*width], n) {
+			return nil, false
+		}
+	}
+	return encoded, true
+}
+
+// transcriptOrDefault returns the single optional transcript or the default one for the session
+func transcriptOrDefault(session []byte, transcript []Transcript) Transcript {
+	if len(transcript) > 0 && transcript[0] != nil {
+		return transcript[0]
+	}
+	return NewSHA512Transcript(session)
+}
@@ -0,0 +1,126 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package schnorr_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// mockTranscript records what is appended to it and returns a fixed challenge
+type mockTranscript struct {
+	entries   []string
+	challenge int64
+}
+
+func (tr *mockTranscript) AppendPoint(label string, p *crypto.ECPoint) {
+	tr.entries = append(tr.entries, fmt.Sprintf("point %s (%s, %s)", label, p.X(), p.Y()))
+}
+
+func (tr *mockTranscript) AppendScalar(label string, s *big.Int) {
+	tr.entries = append(tr.entries, fmt.Sprintf("scalar %s %s", label, s))
+}
+
+func (tr *mockTranscript) Challenge(q *big.Int) *big.Int {
+	tr.entries = append(tr.entries, "challenge")
+	return new(big.Int).Mod(big.NewInt(tr.challenge), q)
+}
+
+func TestSchnorrProofMockTranscript(t *testing.T) {
+	ec := tss.EC()
+	q := ec.Params().N
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, x)
+	G := crypto.ScalarBaseMult(ec, big.NewInt(1))
+
+	prover := &mockTranscript{challenge: 12345}
+	proof, err := NewZKProof(Session, x, X, rand.Reader, prover)
+	assert.NoError(t, err)
+	expected := []string{
+		fmt.Sprintf("point X (%s, %s)", X.X(), X.Y()),
+		fmt.Sprintf("point G (%s, %s)", G.X(), G.Y()),
+		fmt.Sprintf("point Alpha (%s, %s)", proof.Alpha.X(), proof.Alpha.Y()),
+		"challenge",
+	}
+	assert.Equal(t, expected, prover.entries)
+
+	verifier := &mockTranscript{challenge: 12345}
+	assert.True(t, proof.Verify(Session, X, verifier), "the proof must verify with a matching transcript")
+	assert.Equal(t, expected, verifier.entries, "the verifier must append the same elements in the same order")
+
+	assert.False(t, proof.Verify(Session, X, &mockTranscript{challenge: 54321}), "a different challenge must fail")
+	assert.False(t, proof.Verify(Session, X), "the default transcript derives another challenge")
+}
+
+func TestSchnorrProofDefaultTranscript(t *testing.T) {
+	ec := tss.EC()
+	x := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+	X := crypto.ScalarBaseMult(ec, x)
+
+	// the default transcript is the one built from Session
+	proof, err := NewZKProof(Session, x, X, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify(nil, X, NewSHA512Transcript(Session)))
+	proof, err = NewZKProof(nil, x, X, rand.Reader, NewSHA512Transcript(Session))
+	assert.NoError(t, err)
+	assert.True(t, proof.Verify(Session, X))
+
+	ctx := NewContext(ec)
+	_, err = ctx.ProveWithTranscript(nil, x, X, rand.Reader)
+	assert.Error(t, err)
+	assert.False(t, ctx.VerifyProofWithTranscript(nil, proof, X))
+}
+
+func TestSHA512TranscriptMatchesTaggedHash(t *testing.T) {
+	ec := tss.EC()
+	q := ec.Params().N
+	P := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q))
+	// a point with a short x coordinate, whose encoding has leading zeros
+	G1 := crypto.ScalarBaseMult(ec, big.NewInt(1))
+	large := new(big.Int).Lsh(big.NewInt(1), 300)
+	for name, tc := range map[string]struct {
+		points  []*crypto.ECPoint
+		scalars []*big.Int
+		first   bool // the scalars are appended before the points
+	}{
+		"points":               {points: []*crypto.ECPoint{P, G1}},
+		"points and scalars":   {points: []*crypto.ECPoint{P}, scalars: []*big.Int{big.NewInt(0), nil, big.NewInt(7)}},
+		"scalar too large":     {points: []*crypto.ECPoint{P, G1}, scalars: []*big.Int{big.NewInt(1), large, big.NewInt(2)}},
+		"negative scalar":      {points: []*crypto.ECPoint{P}, scalars: []*big.Int{big.NewInt(-5)}},
+		"scalars before point": {points: []*crypto.ECPoint{P}, scalars: []*big.Int{big.NewInt(3)}, first: true},
+	} {
+		tr := NewSHA512Transcript(Session)
+		var in []*big.Int
+		appendScalars := func() {
+			for _, s := range tc.scalars {
+				tr.AppendScalar("s", s)
+				in = append(in, s)
+			}
+		}
+		if tc.first {
+			appendScalars()
+		}
+		for _, p := range tc.points {
+			tr.AppendPoint("p", p)
+			in = append(in, p.X(), p.Y())
+		}
+		if !tc.first {
+			appendScalars()
+		}
+		expected := common.RejectionSample(q, common.SHA512_256i_TAGGED(Session, in...))
+		assert.Equal(t, 0, expected.Cmp(tr.Challenge(q)), name)
+	}
+}
@@ -110,7 +110,7 @@ func (share *Share) Verify(ec elliptic.Curve, threshold int, vs Vs) bool {
 		}
 	}
 	sigmaGi := crypto.ScalarBaseMult(ec, share.Share)
-	return sigmaGi.Equals(v)
+	return sigmaGi.Equal(v)
 }
 
 func (shares Shares) ReConstruct(ec elliptic.Curve) (secret *big.Int, err error) {
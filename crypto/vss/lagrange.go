@@ -0,0 +1,123 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package vss
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// maxCachedCommittees bounds the memory of the cache of LagrangeCoefficients; it is emptied once it is full
+const maxCachedCommittees = 1024
+
+// lagrangeCache holds the coefficients of the committees that LagrangeCoefficients has seen, by lagrangeKey, each as a
+// map from the index of a party in base 16 to its coefficient
+var lagrangeCache = struct {
+	sync.Mutex
+	committees map[string]map[string]*big.Int
+}{committees: make(map[string]map[string]*big.Int)}
+
+// LagrangeCoefficients returns the Lagrange coefficient at 0 of every party index ks[i] of a signing committee: the
+// product over j != i of ks[j] / (ks[j] - ks[i]) mod q, which turns the Shamir share of ks[i] into its additive share
+// of the secret. The coefficients are computed once per committee and modulus and cached, so that a committee that
+// signs repeatedly skips the modular inversions; the committee is the set of its indexes, in whatever order they are
+// given. It fails if two of the indexes are equal mod q. The caller may modify the returned values.
+func LagrangeCoefficients(q *big.Int, ks []*big.Int) ([]*big.Int, error) {
+	if q == nil || q.Sign() <= 0 {
+		return nil, errors.New("LagrangeCoefficients() received an invalid modulus")
+	}
+	for _, k := range ks {
+		if k == nil {
+			return nil, errors.New("LagrangeCoefficients() received a nil index")
+		}
+	}
+	key := lagrangeKey(q, ks)
+	lagrangeCache.Lock()
+	byIndex, ok := lagrangeCache.committees[key]
+	lagrangeCache.Unlock()
+	if !ok {
+		coefs, err := computeLagrangeCoefficients(q, ks, big.NewInt(0))
+		if err != nil {
+			return nil, err
+		}
+		byIndex = make(map[string]*big.Int, len(ks))
+		for i, k := range ks {
+			byIndex[k.Text(16)] = coefs[i]
+		}
+		lagrangeCache.Lock()
+		if len(lagrangeCache.committees) >= maxCachedCommittees {
+			lagrangeCache.committees = make(map[string]map[string]*big.Int)
+		}
+		lagrangeCache.committees[key] = byIndex
+		lagrangeCache.Unlock()
+	}
+	out := make([]*big.Int, len(ks))
+	for i, k := range ks {
+		out[i] = new(big.Int).Set(byIndex[k.Text(16)])
+	}
+	return out, nil
+}
+
+// LagrangeCoefficientsAt returns the Lagrange basis polynomial of every party index ks[i] evaluated at x: the product
+// over j != i of (x - ks[j]) / (ks[i] - ks[j]) mod q, e.g. to interpolate the public share of another index from the
+// public shares of ks. At x = 0 these are the coefficients of LagrangeCoefficients, which caches them. It fails if two
+// of the indexes are equal mod q.
+func LagrangeCoefficientsAt(q *big.Int, ks []*big.Int, x *big.Int) ([]*big.Int, error) {
+	if q == nil || q.Sign() <= 0 {
+		return nil, errors.New("LagrangeCoefficientsAt() received an invalid modulus")
+	}
+	if x == nil {
+		return nil, errors.New("LagrangeCoefficientsAt() received a nil point")
+	}
+	for _, k := range ks {
+		if k == nil {
+			return nil, errors.New("LagrangeCoefficientsAt() received a nil index")
+		}
+	}
+	return computeLagrangeCoefficients(q, ks, x)
+}
+
+// computeLagrangeCoefficients is LagrangeCoefficientsAt without the checks of its arguments
+func computeLagrangeCoefficients(q *big.Int, ks []*big.Int, x *big.Int) ([]*big.Int, error) {
+	modQ := common.ModInt(q)
+	coefs := make([]*big.Int, len(ks))
+	for i, ki := range ks {
+		coef := big.NewInt(1)
+		for j, kj := range ks {
+			if j == i {
+				continue
+			}
+			sub := modQ.Sub(ki, kj)
+			if sub.Sign() == 0 {
+				return nil, errors.New("index of two parties are equal")
+			}
+			// big.Int Div is calculated as: a/b = a * modInv(b,q)
+			coef = modQ.Mul(coef, modQ.Mul(modQ.Sub(x, kj), modQ.ModInverse(sub)))
+		}
+		coefs[i] = coef
+	}
+	return coefs, nil
+}
+
+// lagrangeKey identifies a committee by the modulus and its sorted indexes
+func lagrangeKey(q *big.Int, ks []*big.Int) string {
+	sorted := make([]*big.Int, len(ks))
+	copy(sorted, ks)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].Cmp(sorted[b]) < 0 })
+	var key strings.Builder
+	key.WriteString(q.Text(16))
+	for _, k := range sorted {
+		key.WriteByte(',')
+		key.WriteString(k.Text(16))
+	}
+	return key.String()
+}
@@ -0,0 +1,157 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package vss_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// freshLagrangeCoefficients computes the coefficients as PrepareForSigning used to, without a cache
+func freshLagrangeCoefficients(q *big.Int, ks []*big.Int) []*big.Int {
+	modQ := common.ModInt(q)
+	coefs := make([]*big.Int, len(ks))
+	for i := range ks {
+		coefs[i] = big.NewInt(1)
+		for j := range ks {
+			if j == i {
+				continue
+			}
+			coefs[i] = modQ.Mul(coefs[i], modQ.Mul(ks[j], modQ.ModInverse(new(big.Int).Sub(ks[j], ks[i]))))
+		}
+	}
+	return coefs
+}
+
+func randomCommittee(q *big.Int, n int) []*big.Int {
+	ks := make([]*big.Int, n)
+	for i := range ks {
+		ks[i] = common.GetRandomPositiveInt(rand.Reader, q)
+	}
+	return ks
+}
+
+func TestLagrangeCoefficients(t *testing.T) {
+	for _, q := range []*big.Int{tss.S256().Params().N, tss.Edwards().Params().N} {
+		ks := randomCommittee(q, 7)
+		expected := freshLagrangeCoefficients(q, ks)
+		for n := 0; n < 3; n++ {
+			coefs, err := LagrangeCoefficients(q, ks)
+			if assert.NoError(t, err) {
+				assert.Equal(t, expected, coefs, "attempt %d", n)
+			}
+			// the values handed out are copies of the cached ones
+			coefs[0].SetInt64(0)
+		}
+
+		// the same committee in another order gets the same coefficient for each index
+		reversed := make([]*big.Int, len(ks))
+		for i, k := range ks {
+			reversed[len(ks)-1-i] = k
+		}
+		coefs, err := LagrangeCoefficients(q, reversed)
+		if assert.NoError(t, err) {
+			assert.Equal(t, freshLagrangeCoefficients(q, reversed), coefs)
+			assert.Equal(t, expected[0], coefs[len(ks)-1])
+		}
+
+		// a subset is another committee
+		coefs, err = LagrangeCoefficients(q, ks[1:])
+		if assert.NoError(t, err) {
+			assert.Equal(t, freshLagrangeCoefficients(q, ks[1:]), coefs)
+		}
+	}
+
+	// the coefficients turn the shares into additive shares of the secret
+	ec := tss.S256()
+	q := ec.Params().N
+	secret := common.GetRandomPositiveInt(rand.Reader, q)
+	ids := randomCommittee(q, 5)
+	_, shares, err := Create(ec, 2, secret, ids, rand.Reader)
+	assert.NoError(t, err)
+	ks := []*big.Int{shares[4].ID, shares[0].ID, shares[2].ID}
+	coefs, err := LagrangeCoefficients(q, ks)
+	if assert.NoError(t, err) {
+		modQ := common.ModInt(q)
+		sum := big.NewInt(0)
+		for i, share := range []*Share{shares[4], shares[0], shares[2]} {
+			sum = modQ.Add(sum, modQ.Mul(share.Share, coefs[i]))
+		}
+		assert.Equal(t, secret, sum)
+	}
+
+	_, err = LagrangeCoefficients(q, []*big.Int{ks[0], ks[1], ks[0]})
+	assert.Error(t, err, "duplicate indexes")
+	_, err = LagrangeCoefficients(q, []*big.Int{ks[0], new(big.Int).Add(ks[0], q)})
+	assert.Error(t, err, "indexes equal mod q")
+	_, err = LagrangeCoefficients(q, []*big.Int{ks[0], nil})
+	assert.Error(t, err)
+}
+
+func TestLagrangeCoefficientsAt(t *testing.T) {
+	ec := tss.Edwards()
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	ks := randomCommittee(q, 5)
+	coefs, err := LagrangeCoefficientsAt(q, ks, big.NewInt(0))
+	if assert.NoError(t, err) {
+		expected, err := LagrangeCoefficients(q, ks)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, coefs, "at 0 they are the coefficients of LagrangeCoefficients")
+	}
+
+	// threshold+1 shares interpolate the share of another index
+	secret := common.GetRandomPositiveInt(rand.Reader, q)
+	_, shares, err := Create(ec, 2, secret, ks, rand.Reader)
+	assert.NoError(t, err)
+	basis := []*Share{shares[3], shares[0], shares[1]}
+	coefs, err = LagrangeCoefficientsAt(q, []*big.Int{basis[0].ID, basis[1].ID, basis[2].ID}, shares[4].ID)
+	if assert.NoError(t, err) {
+		sum := big.NewInt(0)
+		for i, share := range basis {
+			sum = modQ.Add(sum, modQ.Mul(share.Share, coefs[i]))
+		}
+		assert.Equal(t, shares[4].Share, sum)
+	}
+
+	_, err = LagrangeCoefficientsAt(q, []*big.Int{ks[0], new(big.Int).Add(ks[0], q)}, ks[1])
+	assert.Error(t, err, "indexes equal mod q")
+	_, err = LagrangeCoefficientsAt(q, ks, nil)
+	assert.Error(t, err)
+}
+
+func BenchmarkLagrangeCoefficients(b *testing.B) {
+	q := tss.S256().Params().N
+	b.Run("same committee", func(b *testing.B) {
+		ks := randomCommittee(q, 20)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if _, err := LagrangeCoefficients(q, ks); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("new committee", func(b *testing.B) {
+		committees := make([][]*big.Int, b.N)
+		for n := range committees {
+			committees[n] = randomCommittee(q, 20)
+		}
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if _, err := LagrangeCoefficients(q, committees[n]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/ckd"
+)
+
+// DeriveChildKey derives the non-hardened BIP-32 child at `path` of the threshold master key in `key`, without
+// re-running keygen. Every party calls it on its own save data with the same 32-byte chain code and path.
+//
+// The derivation adds the same tweak delta to every share: x + delta has the Shamir shares x_j + delta, so the
+// returned save data holds Xi + delta, BigXj + delta*G for each party and the child public key. It can be passed to
+// signing.NewLocalParty as is and the signatures verify under the child public key, which is also returned with its
+// chain code for further derivation. The master save data is not changed.
+func DeriveChildKey(key LocalPartySaveData, chainCode []byte, path []uint32) (LocalPartySaveData, *ckd.ExtendedKey, error) {
+	if key.ECDSAPub == nil || !key.ECDSAPub.ValidateBasic() {
+		return LocalPartySaveData{}, nil, errors.New("DeriveChildKey() received save data without a valid public key")
+	}
+	if len(chainCode) != 32 {
+		return LocalPartySaveData{}, nil, fmt.Errorf("DeriveChildKey() received a chain code of %d bytes", len(chainCode))
+	}
+	if key.Xi == nil {
+		return LocalPartySaveData{}, nil, errors.New("DeriveChildKey() received save data without a secret share")
+	}
+	ec := key.ECDSAPub.Curve()
+	masterPk := &ckd.ExtendedKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: ec,
+			X:     key.ECDSAPub.X(),
+			Y:     key.ECDSAPub.Y(),
+		},
+		Depth:      0,
+		ChildIndex: 0,
+		ChainCode:  chainCode,
+		ParentFP:   []byte{0x00, 0x00, 0x00, 0x00},
+		Version:    chaincfg.MainNetParams.HDPrivateKeyID[:],
+	}
+	delta, childPk, err := ckd.DeriveChildKeyFromHierarchy(path, masterPk, ec.Params().N, ec)
+	if err != nil {
+		return LocalPartySaveData{}, nil, err
+	}
+
+	child := key
+	child.Xi = common.ModInt(ec.Params().N).Add(key.Xi, delta)
+	if child.ECDSAPub, err = crypto.NewECPoint(ec, childPk.X, childPk.Y); err != nil {
+		return LocalPartySaveData{}, nil, err
+	}
+	deltaG := crypto.ScalarBaseMult(ec, delta)
+	child.BigXj = make([]*crypto.ECPoint, len(key.BigXj))
+	for j, Xj := range key.BigXj {
+		if Xj == nil {
+			return LocalPartySaveData{}, nil, fmt.Errorf("DeriveChildKey() received save data without BigXj[%d]", j)
+		}
+		if child.BigXj[j], err = Xj.Add(deltaG); err != nil {
+			return LocalPartySaveData{}, nil, err
+		}
+	}
+	return child, childPk, nil
+}
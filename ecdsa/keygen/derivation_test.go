@@ -0,0 +1,53 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+)
+
+func TestDeriveChildKey(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	chainCode := make([]byte, 32)
+	chainCode[0] = 1
+	masterXi := new(big.Int).Set(keys[0].Xi)
+	masterPub := keys[0].ECDSAPub
+
+	children := make([]LocalPartySaveData, len(keys))
+	for i, key := range keys {
+		children[i], _, err = DeriveChildKey(key, chainCode, []uint32{1, 2})
+		assert.NoError(t, err)
+	}
+	for i, child := range children {
+		assert.True(t, child.ECDSAPub.Equals(children[0].ECDSAPub), "the parties must agree on the child key")
+		for j, Xj := range child.BigXj {
+			assert.True(t, Xj.Equals(children[0].BigXj[j]), "the parties must agree on the child BigXj")
+		}
+		// the tweaked secret share still matches the tweaked public share of the party
+		own := -1
+		for j, kj := range child.Ks {
+			if kj.Cmp(child.ShareID) == 0 {
+				own = j
+			}
+		}
+		assert.True(t, crypto.ScalarBaseMult(child.ECDSAPub.Curve(), child.Xi).Equals(child.BigXj[own]), "party %d", i)
+	}
+	assert.False(t, children[0].ECDSAPub.Equals(masterPub))
+	assert.Equal(t, 0, masterXi.Cmp(keys[0].Xi), "the master save data must not change")
+	assert.True(t, masterPub.Equals(keys[0].ECDSAPub), "the master save data must not change")
+
+	_, _, err = DeriveChildKey(keys[0], chainCode[:16], []uint32{1})
+	assert.Error(t, err, "a short chain code must be rejected")
+	_, _, err = DeriveChildKey(keys[0], chainCode, []uint32{0x80000000})
+	assert.Error(t, err, "a hardened index must be rejected")
+}
@@ -338,6 +338,107 @@ keygen:
 	}
 }
 
+func TestE2EWithSafePrimePool(t *testing.T) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
+	if err != nil {
+		t.Skip("the safe primes are taken from the test fixtures, which were not found")
+	}
+
+	// each party gets its own pool, persisted and loaded again, holding the safe primes of its fixture
+	one := big.NewInt(1)
+	pools := make([]*paillier.SafePrimePool, len(pIDs))
+	for i, fixture := range fixtures {
+		sgps := make([]*common.GermainSafePrime, 0, 4)
+		for _, q := range []*big.Int{
+			new(big.Int).Rsh(new(big.Int).Sub(fixture.PaillierSK.P, one), 1),
+			new(big.Int).Rsh(new(big.Int).Sub(fixture.PaillierSK.Q, one), 1),
+			fixture.LocalPreParams.P,
+			fixture.LocalPreParams.Q,
+		} {
+			sgp, err := common.NewGermainSafePrime(q)
+			if !assert.NoError(t, err) {
+				return
+			}
+			sgps = append(sgps, sgp)
+		}
+		pool, err := paillier.NewSafePrimePool(1024, sgps...)
+		assert.NoError(t, err)
+		bz, err := json.Marshal(pool)
+		assert.NoError(t, err)
+		if pools[i], err = paillier.LoadSafePrimePool(bz); !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		// do not use in untrusted setting
+		params.SetNoProofMod()
+		// do not use in untrusted setting
+		params.SetNoProofFac()
+		params.SetSafePrimeSource(pools[i])
+		P := NewLocalParty(params, outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			break keygen
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			index, err := save.OriginalIndex()
+			assert.NoError(t, err)
+			assert.Equal(t, fixtures[index].PaillierSK.N, save.PaillierSK.N, "the Paillier modulus must be built from the pool")
+			assert.Equal(t, fixtures[index].NTildei, save.NTildei, "NTilde must be built from the pool")
+			assert.Equal(t, 0, pools[index].Len(), "the pool must be drained")
+			if ended++; ended == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	// a drained pool makes keygen fail rather than reuse a prime
+	params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[0], len(pIDs), testThreshold)
+	params.SetSafePrimeSource(pools[0])
+	tssErr := NewLocalParty(params, outCh, endCh).Start()
+	if assert.NotNil(t, tssErr) {
+		assert.Contains(t, tssErr.Error(), "safe prime source")
+	}
+}
+
 func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 	fixtureFileName := makeTestFixtureFilePath(index)
 
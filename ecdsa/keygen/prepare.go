@@ -17,6 +17,7 @@ import (
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 const (
@@ -129,7 +130,34 @@ consumer:
 		}
 	}
 	logProgressTicker.Stop()
+	return newPreParams(rand, paiSK, sgps), nil
+}
+
+// GeneratePreParamsFromSafePrimes computes the pre-parameters like GeneratePreParams, but takes the four safe primes
+// it needs from `source` (e.g. a paillier.SafePrimePool) instead of searching for them: two for the Paillier modulus
+// and two for NTilde.
+func GeneratePreParamsFromSafePrimes(source tss.SafePrimeSource, rand io.Reader) (*LocalPreParams, error) {
+	if source == nil {
+		return nil, errors.New("GeneratePreParamsFromSafePrimes() received a nil source")
+	}
+	sgps, err := source.TakeSafePrimes(4, safePrimeBitLen)
+	if err != nil {
+		return nil, err
+	}
+	for _, sgp := range sgps {
+		if sgp == nil || !sgp.Validate() || sgp.SafePrime().BitLen() != safePrimeBitLen {
+			return nil, errors.New("GeneratePreParamsFromSafePrimes(): the source returned an invalid safe prime")
+		}
+	}
+	paiSK, _, err := paillier.GenerateKeyPairFromSafePrimes(sgps[0], sgps[1])
+	if err != nil {
+		return nil, err
+	}
+	return newPreParams(rand, paiSK, sgps[2:]), nil
+}
 
+// newPreParams derives NTilde, h1, h2 and the discrete logs between them from two safe primes
+func newPreParams(rand io.Reader, paiSK *paillier.PrivateKey, sgps []*common.GermainSafePrime) *LocalPreParams {
 	P, Q := sgps[0].SafePrime(), sgps[1].SafePrime()
 	NTildei := new(big.Int).Mul(P, Q)
 	modNTildeI := common.ModInt(NTildei)
@@ -152,5 +180,5 @@ consumer:
 		P:          p,
 		Q:          q,
 	}
-	return preParams, nil
+	return preParams
 }
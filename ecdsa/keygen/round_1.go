@@ -73,6 +73,10 @@ func (round *round1) Start() *tss.Error {
 			errors.New("`optionalPreParams` failed to validate; it might have been generated with an older version of tss-lib"))
 	} else if round.save.LocalPreParams.ValidateWithProof() {
 		preParams = &round.save.LocalPreParams
+	} else if source := round.SafePrimeSource(); source != nil {
+		if preParams, err = GeneratePreParamsFromSafePrimes(source, round.Rand()); err != nil {
+			return round.WrapError(errors.New("pre-params generation from the safe prime source failed"), Pi)
+		}
 	} else {
 		{
 			ctx, cancel := context.WithTimeout(context.Background(), round.SafePrimeGenTimeout())
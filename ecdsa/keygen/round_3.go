@@ -75,7 +75,7 @@ func (round *round3) Start() *tss.Error {
 			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj}
 			ok, flatPolyGs := cmtDeCmt.DeCommit()
 			if !ok || flatPolyGs == nil {
-				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
+				ch <- vssOut{commitments.ErrDeCommitVerify, nil}
 				return
 			}
 			PjVs, err := crypto.UnFlattenECPoints(round.Params().EC(), flatPolyGs)
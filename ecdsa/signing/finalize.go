@@ -36,28 +36,18 @@ func (round *finalization) Start() *tss.Error {
 		sumS = modN.Add(sumS, r9msg.UnmarshalS())
 	}
 
-	recid := 0
-	// byte v = if(R.X > curve.N) then 2 else 0) | (if R.Y.IsEven then 0 else 1);
-	if round.temp.rx.Cmp(round.Params().EC().Params().N) > 0 {
-		recid = 2
-	}
-	if round.temp.ry.Bit(0) != 0 {
-		recid |= 1
-	}
+	r, recid := recoveryID(round.Params().EC().Params().N, round.temp.rx, round.temp.ry)
 
 	// This is copied from:
 	// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L442-L444
 	// This is needed because of tendermint checks here:
 	// https://github.com/tendermint/tendermint/blob/d9481e3648450cb99e15c6a070c1fb69aa0c255b/crypto/secp256k1/secp256k1_nocgo.go#L43-L47
-	secp256k1halfN := new(big.Int).Rsh(round.Params().EC().Params().N, 1)
-	if sumS.Cmp(secp256k1halfN) > 0 {
-		sumS.Sub(round.Params().EC().Params().N, sumS)
-		recid ^= 1
-	}
+	// It is applied on every curve; a verifier that does not require a low S (e.g. crypto/ecdsa on P-256) accepts both.
+	sumS, recid = lowS(round.Params().EC().Params().N, sumS, recid)
 
 	// save the signature for final output
 	bitSizeInBytes := round.Params().EC().Params().BitSize / 8
-	round.data.R = padToLengthBytesInPlace(round.temp.rx.Bytes(), bitSizeInBytes)
+	round.data.R = padToLengthBytesInPlace(r.Bytes(), bitSizeInBytes)
 	round.data.S = padToLengthBytesInPlace(sumS.Bytes(), bitSizeInBytes)
 	round.data.Signature = append(round.data.R, round.data.S...)
 	round.data.SignatureRecovery = []byte{byte(recid)}
@@ -75,7 +65,7 @@ func (round *finalization) Start() *tss.Error {
 		Y:     round.key.ECDSAPub.Y(),
 	}
 
-	ok := ecdsa.Verify(&pk, round.data.M, round.temp.rx, sumS)
+	ok := ecdsa.Verify(&pk, round.data.M, r, sumS)
 	if !ok {
 		return round.WrapError(fmt.Errorf("signature verification failed"))
 	}
@@ -99,6 +89,33 @@ func (round *finalization) NextRound() tss.Round {
 	return nil // finished!
 }
 
+// recoveryID returns the r of a signature with the nonce point R = (rx, ry), i.e. rx mod N, and its recovery id, from
+// which a verifier such as Ethereum's ecrecover finds R again: bit 0 is the parity of ry and bit 1 is set if rx is not
+// below N, in which case r must have N added back to give rx.
+func recoveryID(N, rx, ry *big.Int) (*big.Int, int) {
+	recid := 0
+	r := rx
+	if rx.Cmp(N) >= 0 {
+		r = new(big.Int).Sub(rx, N)
+		recid = 2
+	}
+	if ry.Bit(0) != 0 {
+		recid |= 1
+	}
+	return r, recid
+}
+
+// lowS returns s and the recovery id recid of a signature normalized to the lower half of [1, N), as Bitcoin and
+// Ethereum require: a high s is replaced by N-s, which is the s of the nonce point -R. -R has the same x coordinate
+// and a y of the other parity, so the parity bit of the recovery id is flipped and the signature still recovers the
+// same public key.
+func lowS(N, s *big.Int, recid int) (*big.Int, int) {
+	if s.Cmp(new(big.Int).Rsh(N, 1)) > 0 {
+		return new(big.Int).Sub(N, s), recid ^ 1
+	}
+	return s, recid
+}
+
 func padToLengthBytesInPlace(src []byte, length int) []byte {
 	oriLen := len(src)
 	if oriLen < length {
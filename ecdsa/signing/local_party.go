@@ -36,8 +36,9 @@ type (
 		data *common.SignatureData
 
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- *common.SignatureData
+		out       chan<- tss.Message
+		end       chan<- *common.SignatureData
+		presigEnd chan<- *Presignature
 	}
 
 	localMessageStore struct {
@@ -70,6 +71,8 @@ type (
 		bigWs        []*crypto.ECPoint
 		pointGamma   *crypto.ECPoint
 		deCommit     cmt.HashDeCommitment
+		// set by PrecomputeRangeProofs, by the index of the verifier
+		rangeProofs []*mta.RangeProofAlicePrecomputation
 
 		// round 2
 		betas, // return value of Bob_mid
@@ -161,22 +164,57 @@ func NewLocalPartyWithKDD(
 }
 
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end)
+	round := newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end, p.presigEnd)
+	if p.temp.bigR != nil {
+		// signing from a presignature resumes at round 5
+		return &round5{&round4{&round3{&round2{round.(*round1)}}}}
+	}
+	return round
 }
 
 func (p *LocalParty) Start() *tss.Error {
 	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
-		round1, ok := round.(*round1)
-		if !ok {
+		switch rnd := round.(type) {
+		case *round1:
+			if err := rnd.prepare(); err != nil {
+				return round.WrapError(err)
+			}
+		case *round5:
+			// the key share was already folded into sigma by the presignature
+		default:
 			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
 		}
-		if err := round1.prepare(); err != nil {
-			return round.WrapError(err)
-		}
 		return nil
 	})
 }
 
+// PrecomputeRangeProofs computes the randomness and the message-independent commitments of the MtA range proofs that
+// round 1 sends to the other signers, which take most of its time, so that Start only completes them. It is meant
+// for the idle phase before a session and must be called before Start. Each precomputation is used by round 1 once;
+// round 1 computes the proofs in full for the signers without one.
+func (p *LocalParty) PrecomputeRangeProofs() error {
+	i := p.PartyID().Index
+	if i < 0 || i >= len(p.keys.PaillierPKs) {
+		return errors.New("PrecomputeRangeProofs(): the key has no Paillier key of the party")
+	}
+	pres := make([]*mta.RangeProofAlicePrecomputation, len(p.params.Parties().IDs()))
+	for j := range pres {
+		if j == i {
+			continue
+		}
+		if j >= len(p.keys.NTildej) || j >= len(p.keys.H1j) || j >= len(p.keys.H2j) {
+			return fmt.Errorf("PrecomputeRangeProofs(): the key has no NTilde, h1 or h2 of the party at index %d", j)
+		}
+		pre, err := mta.PrecomputeRangeProofAlice(p.params.EC(), p.keys.PaillierPKs[i], p.keys.NTildej[j], p.keys.H1j[j], p.keys.H2j[j], p.params.Rand())
+		if err != nil {
+			return err
+		}
+		pres[j] = pre
+	}
+	p.temp.rangeProofs = pres
+	return nil
+}
+
 func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 	return tss.BaseUpdate(p, msg, TaskName)
 }
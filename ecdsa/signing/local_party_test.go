@@ -8,6 +8,7 @@ package signing
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
 
@@ -334,6 +336,280 @@ signing:
 	}
 }
 
+func TestE2EWithDerivedChildKeys(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	chainCode := make([]byte, 32)
+	_, err = rand.Read(chainCode)
+	assert.NoError(t, err)
+
+	for _, path := range [][]uint32{{0}, {44, 0, 0, 7}, {12, 209, 3}} {
+		// every party derives its child save data on its own
+		childKeys := make([]keygen.LocalPartySaveData, len(keys))
+		var childPk *ecdsa.PublicKey
+		for i := range keys {
+			child, extendedChildPk, err := keygen.DeriveChildKey(keys[i], chainCode, path)
+			assert.NoError(t, err)
+			childKeys[i] = child
+			childPk = &extendedChildPk.PublicKey
+		}
+		// the child public key is the one derived from the master public key alone
+		_, expectedPk, err := derivingPubkeyFromPath(keys[0].ECDSAPub, chainCode, path, btcec.S256())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, expectedPk.X.Cmp(childPk.X), "path %v", path)
+		assert.Equal(t, 0, expectedPk.Y.Cmp(childPk.Y), "path %v", path)
+		for _, child := range childKeys {
+			assert.True(t, child.ECDSAPub.Equals(childKeys[0].ECDSAPub), "the parties must agree on the child key")
+		}
+
+		msg := big.NewInt(int64(len(path)))
+		data := signWithKeys(t, tss.S256(), childKeys, signPIDs, msg)
+		r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+		assert.True(t, ecdsa.Verify(childPk, msg.Bytes(), r, s), "ecdsa verify under the child key must pass for path %v", path)
+		masterPk := keys[0].ECDSAPub.ToECDSAPubKey()
+		assert.False(t, ecdsa.Verify(masterPk, msg.Bytes(), r, s), "the master key must not verify a child signature")
+	}
+}
+
+// signWithKeys runs a signing session of the keys; the optional `setup` is called with every party before its Start
+func signWithKeys(t *testing.T, ec elliptic.Curve, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, msg *big.Int,
+	setup ...func(*LocalParty)) *common.SignatureData {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(ec, p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		for _, fn := range setup {
+			fn(P)
+		}
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		case data := <-endCh:
+			if ended++; ended == len(signPIDs) {
+				return data
+			}
+		}
+	}
+}
+
+func TestE2EKeygenAndSignP256(t *testing.T) {
+	setUp("info")
+
+	ec := tss.P256()
+	// the Paillier and safe prime parameters of the fixtures do not depend on the curve
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pIDs := tss.GenerateTestPartyIDs(testThreshold + 1)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*keygen.LocalParty, 0, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(ec, p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		// do not use in untrusted setting
+		params.SetNoProofMod()
+		// do not use in untrusted setting
+		params.SetNoProofFac()
+		parties = append(parties, keygen.NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*keygen.LocalParty))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		case save := <-endCh:
+			index, err := save.OriginalIndex()
+			assert.NoError(t, err)
+			keys[index] = *save
+			ended++
+		}
+	}
+	for _, key := range keys {
+		assert.True(t, key.ECDSAPub.Equals(keys[0].ECDSAPub), "the parties must agree on the public key")
+		assert.True(t, tss.SameCurve(ec, key.ECDSAPub.Curve()))
+	}
+
+	msg := big.NewInt(256)
+	data := signWithKeys(t, ec, keys, pIDs, msg)
+	pk := keys[0].ECDSAPub.ToECDSAPubKey()
+	assert.Equal(t, elliptic.P256(), pk.Curve)
+	r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+	assert.True(t, ecdsa.Verify(pk, msg.Bytes(), r, s), "ecdsa verify must pass on P-256")
+}
+
+func TestLowS(t *testing.T) {
+	N := tss.S256().Params().N
+	half := new(big.Int).Rsh(N, 1)
+	for _, tc := range []struct {
+		s, expected *big.Int
+		flipped     bool
+	}{
+		{s: big.NewInt(1), expected: big.NewInt(1)},
+		{s: half, expected: half},
+		{s: new(big.Int).Add(half, big.NewInt(1)), expected: half, flipped: true},
+		{s: new(big.Int).Sub(N, big.NewInt(1)), expected: big.NewInt(1), flipped: true},
+	} {
+		for recid := 0; recid < 4; recid++ {
+			s, id := lowS(N, new(big.Int).Set(tc.s), recid)
+			assert.Equal(t, 0, tc.expected.Cmp(s), "s = %s", tc.s)
+			if tc.flipped {
+				assert.Equal(t, recid^1, id)
+			} else {
+				assert.Equal(t, recid, id)
+			}
+		}
+	}
+}
+
+func TestRecoveryID(t *testing.T) {
+	ec := tss.S256()
+	P, N := ec.Params().P, ec.Params().N
+	modN := common.ModInt(N)
+	// nonce points with an x below N and, rarely enough that no signing session will meet one, above N
+	var xs []*big.Int
+	for _, from := range []*big.Int{big.NewInt(1), new(big.Int).Add(N, big.NewInt(1))} {
+		for x, found := new(big.Int).Set(from), 0; found < 2; x = new(big.Int).Add(x, big.NewInt(1)) {
+			y2 := new(big.Int).Add(new(big.Int).Exp(x, big.NewInt(3), P), ec.Params().B)
+			if new(big.Int).ModSqrt(y2.Mod(y2, P), P) != nil {
+				xs = append(xs, x)
+				found++
+			}
+		}
+	}
+	for _, rx := range xs {
+		y2 := new(big.Int).Add(new(big.Int).Exp(rx, big.NewInt(3), P), ec.Params().B)
+		y := new(big.Int).ModSqrt(y2.Mod(y2, P), P)
+		for _, ry := range []*big.Int{y, new(big.Int).Sub(P, y)} {
+			r, recid := recoveryID(N, rx, ry)
+			assert.Equal(t, 0, new(big.Int).Mod(rx, N).Cmp(r))
+			assert.Equal(t, rx.Cmp(N) >= 0, recid&2 != 0, "the overflow bit")
+			assert.Equal(t, ry.Bit(0) != 0, recid&1 != 0, "the parity bit")
+
+			// the public key for which (r, s) signs the hash with the nonce point R is r^-1 * (s*R - e*G)
+			hash := make([]byte, 32)
+			_, err := rand.Read(hash)
+			assert.NoError(t, err)
+			e := new(big.Int).Mod(new(big.Int).SetBytes(hash), N)
+			s := common.GetRandomPositiveInt(rand.Reader, N)
+			sRx, sRy := ec.ScalarMult(rx, ry, s.Bytes())
+			eGx, eGy := ec.ScalarBaseMult(modN.Sub(big.NewInt(0), e).Bytes())
+			Qx, Qy := ec.Add(sRx, sRy, eGx, eGy)
+			Qx, Qy = ec.ScalarMult(Qx, Qy, modN.ModInverse(r).Bytes())
+
+			s, recid = lowS(N, s, recid)
+			compact := append([]byte{27 + byte(recid)}, padToLengthBytesInPlace(r.Bytes(), 32)...)
+			compact = append(compact, padToLengthBytesInPlace(s.Bytes(), 32)...)
+			recovered, _, err := btcecdsa.RecoverCompact(compact, hash)
+			if assert.NoError(t, err, "rx = %s", rx) {
+				assert.Equal(t, 0, recovered.X().Cmp(Qx))
+				assert.Equal(t, 0, recovered.Y().Cmp(Qy))
+			}
+		}
+	}
+}
+
+func TestE2ESignaturesAreLowS(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pk := keys[0].ECDSAPub.ToECDSAPubKey()
+	half := new(big.Int).Rsh(tss.S256().Params().N, 1)
+	for n := 0; n < 8; n++ {
+		hash := make([]byte, 32)
+		_, err := rand.Read(hash)
+		assert.NoError(t, err)
+		data := signWithKeys(t, tss.S256(), keys, signPIDs, new(big.Int).SetBytes(hash))
+		r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+		assert.True(t, s.Cmp(half) <= 0, "S must be in the lower half of the order")
+		assert.True(t, ecdsa.Verify(pk, hash, r, s), "ecdsa verify must pass")
+
+		// the recovery id still recovers the key: a compact signature is 27 + recid, R and S
+		compact := append([]byte{27 + data.SignatureRecovery[0]}, data.Signature...)
+		recovered, _, err := btcecdsa.RecoverCompact(compact, hash)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, recovered.X().Cmp(pk.X))
+			assert.Equal(t, 0, recovered.Y().Cmp(pk.Y))
+		}
+	}
+}
+
+func TestE2EPrecomputedRangeProofs(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	var parties []*LocalParty
+	precompute := func(P *LocalParty) {
+		assert.NoError(t, P.PrecomputeRangeProofs())
+		for j, pre := range P.temp.rangeProofs {
+			assert.Equal(t, j != P.PartyID().Index, pre != nil)
+		}
+		parties = append(parties, P)
+	}
+	hash := common.SHA512_256([]byte("precomputed range proofs"))
+	data := signWithKeys(t, tss.S256(), keys, signPIDs, new(big.Int).SetBytes(hash), precompute)
+	r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+	assert.True(t, ecdsa.Verify(keys[0].ECDSAPub.ToECDSAPubKey(), hash, r, s), "ecdsa verify must pass")
+
+	// round 1 consumed every precomputation
+	assert.Len(t, parties, len(signPIDs))
+	for _, P := range parties {
+		for _, pre := range P.temp.rangeProofs {
+			assert.Nil(t, pre)
+		}
+	}
+}
+
 func TestFillTo32BytesInPlace(t *testing.T) {
 	s := big.NewInt(123456789)
 	normalizedS := padToLengthBytesInPlace(s.Bytes(), 32)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 )
 
 // PrepareForSigning(), GG18Spec (11) Fig. 14
@@ -28,40 +29,24 @@ func PrepareForSigning(ec elliptic.Curve, i, pax int, xi *big.Int, ks []*big.Int
 		panic(fmt.Errorf("PrepareForSigning: len(ks) <= i (%d <= %d)", len(ks), i))
 	}
 
+	coefs, err := vss.LagrangeCoefficients(ec.Params().N, ks)
+	if err != nil {
+		panic(err)
+	}
+
 	// 2-4.
 	wi = xi
-	for j := 0; j < pax; j++ {
-		if j == i {
-			continue
-		}
-		ksj := ks[j]
-		ksi := ks[i]
-		if ksj.Cmp(ksi) == 0 {
-			panic(fmt.Errorf("index of two parties are equal"))
-		}
-		// big.Int Div is calculated as: a/b = a * modInv(b,q)
-		coef := modQ.Mul(ks[j], modQ.ModInverse(new(big.Int).Sub(ksj, ksi)))
-		wi = modQ.Mul(wi, coef)
+	if pax > 1 {
+		wi = modQ.Mul(xi, coefs[i])
 	}
 
 	// 5-10.
 	bigWs = make([]*crypto.ECPoint, len(ks))
 	for j := 0; j < pax; j++ {
-		bigWj := bigXs[j]
-		for c := 0; c < pax; c++ {
-			if j == c {
-				continue
-			}
-			ksc := ks[c]
-			ksj := ks[j]
-			if ksj.Cmp(ksc) == 0 {
-				panic(fmt.Errorf("index of two parties are equal"))
-			}
-			// big.Int Div is calculated as: a/b = a * modInv(b,q)
-			iota := modQ.Mul(ksc, modQ.ModInverse(new(big.Int).Sub(ksc, ksj)))
-			bigWj = bigWj.ScalarMult(iota)
+		bigWs[j] = bigXs[j]
+		if pax > 1 {
+			bigWs[j] = bigXs[j].ScalarMult(coefs[j])
 		}
-		bigWs[j] = bigWj
 	}
 	return
 }
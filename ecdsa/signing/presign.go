@@ -0,0 +1,140 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Presignature is the output of the message-independent rounds 1 to 4 of signing, which hold all the Paillier and
+// MtA work: this party's nonce share ki, its share sigma_i of k*x and the nonce point R of all the signers. It can be
+// serialized (e.g. with encoding/json) and is consumed by SignPresig once the message is known.
+//
+// A presignature must only ever be used once: signing two messages with the same nonce reveals the key.
+// SignPresig clears K and Sigma and refuses presignatures that have already been used, but copies that were
+// persisted before that must be deleted by the caller.
+type Presignature struct {
+	mtx sync.Mutex
+
+	SSID  []byte
+	K     *big.Int        // secret
+	Sigma *big.Int        // secret
+	R     *crypto.ECPoint // nonce point of the signature
+	Ks    []*big.Int      // keys of the signing parties, in order
+}
+
+// NewPresignParty returns a party that runs signing rounds 1 to 4 without a message and sends the resulting
+// presignature to `end`. All the signers of a future message must take part.
+func NewPresignParty(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *Presignature,
+) tss.Party {
+	p := NewLocalParty(nil, params, key, out, nil).(*LocalParty)
+	p.presigEnd = end
+	return p
+}
+
+// SignPresig returns a party that signs `msg` from a presignature produced by NewPresignParty, starting directly at
+// round 5. Every signer must use its own presignature from the same presigning session. The presignature is consumed
+// and cannot be used again. The party broadcasts its round 5 message as soon as it is started, so all the signers
+// should be started before their messages are delivered.
+//
+// The remaining rounds 5 to 9 only exchange commitments and Schnorr proofs. They check the signature shares against
+// R and the public key before s is released, which GG18 needs because the presigning rounds carry no such check.
+func SignPresig(
+	presig *Presignature,
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+	fullBytesLen ...int,
+) (tss.Party, error) {
+	if presig == nil || msg == nil {
+		return nil, errors.New("SignPresig() received a nil presignature or message")
+	}
+	// see round 1: the hashed message must belong to Zq
+	if msg.Sign() < 0 || msg.Cmp(params.EC().Params().N) >= 0 {
+		return nil, errors.New("SignPresig(): hashed message is not valid")
+	}
+	presig.mtx.Lock()
+	defer presig.mtx.Unlock()
+	if presig.K == nil || presig.Sigma == nil {
+		return nil, errors.New("SignPresig() received a presignature that has already been used")
+	}
+	if presig.R == nil || !presig.R.ValidateBasic() || !tss.SameCurve(presig.R.Curve(), params.EC()) {
+		return nil, errors.New("SignPresig() received a presignature with an invalid R")
+	}
+	ks := params.Parties().IDs().Keys()
+	if len(ks) != len(presig.Ks) {
+		return nil, errors.New("SignPresig() received a presignature for a different set of parties")
+	}
+	for j, k := range ks {
+		if k.Cmp(presig.Ks[j]) != 0 {
+			return nil, errors.New("SignPresig() received a presignature for a different set of parties")
+		}
+	}
+	p := NewLocalParty(msg, params, key, out, end, fullBytesLen...).(*LocalParty)
+	p.temp.ssid = presig.SSID
+	p.temp.k = presig.K
+	p.temp.sigma = presig.Sigma
+	p.temp.bigR = presig.R
+	presig.K, presig.Sigma = nil, nil
+	return p, nil
+}
+
+// ----- //
+
+func (round *presignFinalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 5
+	round.started = true
+	round.resetOK()
+
+	R, err := round.computeR()
+	if err != nil {
+		return err
+	}
+	// clear temp.w from memory, lint ignore
+	round.temp.w = zero
+	for j := range round.ok {
+		round.ok[j] = true
+	}
+	round.presigEnd <- &Presignature{
+		SSID:  round.temp.ssid,
+		K:     round.temp.k,
+		Sigma: round.temp.sigma,
+		R:     R,
+		Ks:    round.Parties().IDs().Keys(),
+	}
+	return nil
+}
+
+func (round *presignFinalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *presignFinalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *presignFinalization) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -0,0 +1,162 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// routeMessages delivers the messages of `parties` until `done` is closed or a party fails, returning the failure
+func routeMessages(parties []tss.Party, outCh <-chan tss.Message, errCh chan *tss.Error, done <-chan struct{}) *tss.Error {
+	for {
+		select {
+		case <-done:
+			return nil
+		case err := <-errCh:
+			return err
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		}
+	}
+}
+
+func runPresign(t *testing.T, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs) []*Presignature {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *Presignature, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		P := NewPresignParty(params, keys[i], outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	presigs := make([]*Presignature, 0, len(signPIDs))
+	done := make(chan struct{})
+	go func() {
+		for range signPIDs {
+			presigs = append(presigs, <-endCh)
+		}
+		close(done)
+	}()
+	if err := routeMessages(parties, outCh, errCh, done); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	// the presignatures arrive in any order
+	ordered := make([]*Presignature, len(signPIDs))
+	for _, presig := range presigs {
+		for i, P := range parties {
+			if presig.K.Cmp(P.(*LocalParty).temp.k) == 0 {
+				ordered[i] = presig
+			}
+		}
+	}
+	return ordered
+}
+
+func signWithPresigs(t *testing.T, presigs []*Presignature, msg *big.Int, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs) *common.SignatureData {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		P, err := SignPresig(presigs[i], msg, params, keys[i], outCh, endCh, 32)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		parties = append(parties, P)
+	}
+	// round 5 broadcasts as soon as a party starts, so start them all before routing
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	var sig *common.SignatureData
+	done := make(chan struct{})
+	go func() {
+		for range signPIDs {
+			sig = <-endCh
+		}
+		close(done)
+	}()
+	if err := routeMessages(parties, outCh, errCh, done); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	return sig
+}
+
+func TestE2EPresign(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pk := ecdsa.PublicKey{
+		Curve: tss.S256(),
+		X:     keys[0].ECDSAPub.X(),
+		Y:     keys[0].ECDSAPub.Y(),
+	}
+
+	// both presignatures are produced before either message is known
+	presigSets := [][]*Presignature{runPresign(t, keys, signPIDs), runPresign(t, keys, signPIDs)}
+	assert.False(t, presigSets[0][0].R.Equals(presigSets[1][0].R), "each presigning session must use a fresh nonce")
+
+	for n, msg := range []string{"first message", "second message"} {
+		presigs := presigSets[n]
+		for _, presig := range presigs[1:] {
+			assert.True(t, presigs[0].R.Equals(presig.R), "all parties must agree on R")
+		}
+
+		// a presignature survives serialization
+		bz, err := json.Marshal(presigs[0])
+		assert.NoError(t, err)
+		restored := new(Presignature)
+		assert.NoError(t, json.Unmarshal(bz, restored))
+		presigs[0] = restored
+
+		digest := sha256.Sum256([]byte(msg))
+		sig := signWithPresigs(t, presigs, new(big.Int).SetBytes(digest[:]), keys, signPIDs)
+		assert.Equal(t, digest[:], sig.M)
+		r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+		assert.True(t, ecdsa.Verify(&pk, digest[:], r, s), "ecdsa verify must pass")
+
+		// a presignature is single use
+		params := tss.NewParameters(tss.S256(), tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+		_, err = SignPresig(presigs[0], big.NewInt(1), params, keys[0], nil, nil)
+		assert.Error(t, err)
+	}
+}
@@ -79,5 +79,8 @@ func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
 
 func (round *round4) NextRound() tss.Round {
 	round.started = false
+	if round.presigEnd != nil {
+		return &presignFinalization{round}
+	}
 	return &round5{round}
 }
@@ -26,39 +26,13 @@ func (round *round5) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
-	R := round.temp.pointGamma
-	for j, Pj := range round.Parties().IDs() {
-		if j == round.PartyID().Index {
-			continue
-		}
-		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
-		r1msg2 := round.temp.signRound1Message2s[j].Content().(*SignRound1Message2)
-		r4msg := round.temp.signRound4Messages[j].Content().(*SignRound4Message)
-		SCj, SDj := r1msg2.UnmarshalCommitment(), r4msg.UnmarshalDeCommitment()
-		cmtDeCmt := commitments.HashCommitDecommit{C: SCj, D: SDj}
-		ok, bigGammaJ := cmtDeCmt.DeCommit()
-		if !ok || len(bigGammaJ) != 2 {
-			return round.WrapError(errors.New("commitment verify failed"), Pj)
-		}
-		bigGammaJPoint, err := crypto.NewECPoint(round.Params().EC(), bigGammaJ[0], bigGammaJ[1])
-		if err != nil {
-			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigGammaJ)"), Pj)
-		}
-		proof, err := r4msg.UnmarshalZKProof(round.Params().EC())
-		if err != nil {
-			return round.WrapError(errors.New("failed to unmarshal bigGamma proof"), Pj)
-		}
-		ok = proof.Verify(ContextJ, bigGammaJPoint)
-		if !ok {
-			return round.WrapError(errors.New("failed to prove bigGamma"), Pj)
-		}
-		R, err = R.Add(bigGammaJPoint)
-		if err != nil {
-			return round.WrapError(errors2.Wrapf(err, "R.Add(bigGammaJ)"), Pj)
+	R := round.temp.bigR
+	if R == nil {
+		var err *tss.Error
+		if R, err = round.computeR(); err != nil {
+			return err
 		}
 	}
-
-	R = R.ScalarMult(round.temp.thetaInverse)
 	N := round.Params().EC().Params().N
 	modN := common.ModInt(N)
 	rx := R.X()
@@ -97,6 +71,44 @@ func (round *round5) Start() *tss.Error {
 	return nil
 }
 
+// computeR verifies the de-commitments and proofs of the other parties' Gamma_j and returns R = (sum Gamma_j)^(theta^-1).
+// It does not depend on the message, which lets it also run when producing a presignature.
+func (round *round4) computeR() (*crypto.ECPoint, *tss.Error) {
+	R := round.temp.pointGamma
+	for j, Pj := range round.Parties().IDs() {
+		if j == round.PartyID().Index {
+			continue
+		}
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		r1msg2 := round.temp.signRound1Message2s[j].Content().(*SignRound1Message2)
+		r4msg := round.temp.signRound4Messages[j].Content().(*SignRound4Message)
+		SCj, SDj := r1msg2.UnmarshalCommitment(), r4msg.UnmarshalDeCommitment()
+		cmtDeCmt := commitments.HashCommitDecommit{C: SCj, D: SDj}
+		ok, bigGammaJ := cmtDeCmt.DeCommit()
+		if !ok || len(bigGammaJ) != 2 {
+			return nil, round.WrapError(errors.New("commitment verify failed"), Pj)
+		}
+		bigGammaJPoint, err := crypto.NewECPoint(round.Params().EC(), bigGammaJ[0], bigGammaJ[1])
+		if err != nil {
+			return nil, round.WrapError(errors2.Wrapf(err, "NewECPoint(bigGammaJ)"), Pj)
+		}
+		proof, err := r4msg.UnmarshalZKProof(round.Params().EC())
+		if err != nil {
+			return nil, round.WrapError(errors.New("failed to unmarshal bigGamma proof"), Pj)
+		}
+		ok = proof.Verify(ContextJ, bigGammaJPoint)
+		if !ok {
+			return nil, round.WrapError(errors.New("failed to prove bigGamma"), Pj)
+		}
+		R, err = R.Add(bigGammaJPoint)
+		if err != nil {
+			return nil, round.WrapError(errors2.Wrapf(err, "R.Add(bigGammaJ)"), Pj)
+		}
+	}
+
+	return R.ScalarMult(round.temp.thetaInverse), nil
+}
+
 func (round *round5) Update() (bool, *tss.Error) {
 	ret := true
 	for j, msg := range round.temp.signRound5Messages {
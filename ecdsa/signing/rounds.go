@@ -23,14 +23,15 @@ const (
 type (
 	base struct {
 		*tss.Parameters
-		key     *keygen.LocalPartySaveData
-		data    *common.SignatureData
-		temp    *localTempData
-		out     chan<- tss.Message
-		end     chan<- *common.SignatureData
-		ok      []bool // `ok` tracks parties which have been verified by Update()
-		started bool
-		number  int
+		key       *keygen.LocalPartySaveData
+		data      *common.SignatureData
+		temp      *localTempData
+		out       chan<- tss.Message
+		end       chan<- *common.SignatureData
+		presigEnd chan<- *Presignature
+		ok        []bool // `ok` tracks parties which have been verified by Update()
+		started   bool
+		number    int
 	}
 	round1 struct {
 		*base
@@ -62,6 +63,9 @@ type (
 	finalization struct {
 		*round9
 	}
+	presignFinalization struct {
+		*round4
+	}
 )
 
 var (
@@ -75,6 +79,7 @@ var (
 	_ tss.Round = (*round8)(nil)
 	_ tss.Round = (*round9)(nil)
 	_ tss.Round = (*finalization)(nil)
+	_ tss.Round = (*presignFinalization)(nil)
 )
 
 // ----- //
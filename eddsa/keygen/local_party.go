@@ -21,6 +21,7 @@ import (
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.MessageCounter = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -155,6 +156,19 @@ func (save LocalPartySaveData) OriginalIndex() (int, error) {
 	return index, nil
 }
 
+// ExpectedMessages implements tss.MessageCounter: round 1 waits for the commitment broadcast by every other party
+// and round 2 for its de-commitment broadcast and the share that it sends to this party
+func (p *LocalParty) ExpectedMessages(round int) (broadcast, p2p int) {
+	others := len(p.params.Parties().IDs()) - 1
+	switch round {
+	case 1:
+		return others, 0
+	case 2:
+		return others, others
+	}
+	return 0, 0
+}
+
 func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
@@ -14,6 +14,7 @@ import (
 	"runtime"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
@@ -235,3 +236,112 @@ func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 		t.Logf("Fixture file already exists for party %d; not re-creating: %s", index, fixtureFileName)
 	}
 }
+
+// keygenErrors runs a keygen whose messages pass through `tamper` and returns the errors of the parties in `victims`
+func keygenErrors(t *testing.T, tamper func(tss.ParsedMessage) tss.ParsedMessage, victims ...int) map[int]*tss.Error {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		parties = append(parties, NewLocalParty(params, outCh, endCh).(*LocalParty))
+	}
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	errs := make(map[int]*tss.Error)
+	timeout := time.After(time.Minute)
+	for {
+		select {
+		case err := <-errCh:
+			errs[err.Victim().Index] = err
+			complete := true
+			for _, v := range victims {
+				if errs[v] == nil {
+					complete = false
+				}
+			}
+			if complete {
+				return errs
+			}
+		case msg := <-outCh:
+			msg = tamper(msg.(tss.ParsedMessage))
+			if dest := msg.GetTo(); dest != nil {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				continue
+			}
+			for _, P := range parties {
+				if P.PartyID().Index != msg.GetFrom().Index {
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			}
+		case <-endCh:
+			// a cheater does not check its own messages, so it may finish
+		case <-timeout:
+			assert.FailNow(t, "timed out waiting for the errors of the honest parties")
+		}
+	}
+}
+
+// badProof replaces the schnorr proof of a KGRound2Message2 with one that does not verify
+func badProof(msg tss.ParsedMessage) tss.ParsedMessage {
+	content := msg.Content().(*KGRound2Message2)
+	proof, _ := content.UnmarshalZKProof(tss.Edwards())
+	proof.T = new(big.Int).Add(proof.T, big.NewInt(1))
+	deCommit, _ := content.UnmarshalDeCommitment(2*(testThreshold+1), tss.Edwards().Params().P)
+	return NewKGRound2Message2(msg.GetFrom(), deCommit, proof)
+}
+
+func TestE2ESchnorrProofCulprits(t *testing.T) {
+	setUp("info")
+
+	// parties 1 and 3 both send schnorr proofs that do not verify
+	errs := keygenErrors(t, func(msg tss.ParsedMessage) tss.ParsedMessage {
+		if _, ok := msg.Content().(*KGRound2Message2); ok && (msg.GetFrom().Index == 1 || msg.GetFrom().Index == 3) {
+			return badProof(msg)
+		}
+		return msg
+	}, 0, 2, 4)
+	for _, v := range []int{0, 2, 4} {
+		err := errs[v]
+		assert.Equal(t, 3, err.Round())
+		assert.Contains(t, err.Error(), "failed to prove schnorr proof")
+		if assert.Len(t, err.Culprits(), 2, "party %d", v) {
+			assert.Equal(t, 1, err.Culprits()[0].Index)
+			assert.Equal(t, 3, err.Culprits()[1].Index)
+		}
+	}
+
+	// party 1 sends a bad proof while party 3 sends a bad share to party 0; party 0 names both in one pass
+	errs = keygenErrors(t, func(msg tss.ParsedMessage) tss.ParsedMessage {
+		switch content := msg.Content().(type) {
+		case *KGRound2Message2:
+			if msg.GetFrom().Index == 1 {
+				return badProof(msg)
+			}
+		case *KGRound2Message1:
+			if msg.GetFrom().Index == 3 && msg.GetTo()[0].Index == 0 {
+				share := &vss.Share{Share: new(big.Int).Add(content.UnmarshalShare(), big.NewInt(1))}
+				return NewKGRound2Message1(msg.GetTo()[0], msg.GetFrom(), share)
+			}
+		}
+		return msg
+	}, 0, 2)
+	err := errs[0]
+	assert.Contains(t, err.Error(), "vss verify failed")
+	assert.Contains(t, err.Error(), "failed to prove schnorr proof")
+	if assert.Len(t, err.Culprits(), 2) {
+		assert.Equal(t, 1, err.Culprits()[0].Index)
+		assert.Equal(t, 3, err.Culprits()[1].Index)
+	}
+	if assert.Len(t, errs[2].Culprits(), 1, "the share of party 2 is valid") {
+		assert.Equal(t, 1, errs[2].Culprits()[0].Index)
+	}
+}
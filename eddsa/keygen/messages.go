@@ -106,9 +106,15 @@ func (m *KGRound2Message2) ValidateBasic() bool {
 		common.NonEmptyMultiBytes(m.GetDeCommitment())
 }
 
-func (m *KGRound2Message2) UnmarshalDeCommitment() []*big.Int {
+// UnmarshalDeCommitment returns the de-commitment of the polynomial. It fails unless it holds exactly `arity` values,
+// each in [0, bound), see cmt.ValidateDeCommitment.
+func (m *KGRound2Message2) UnmarshalDeCommitment(arity int, bound *big.Int) (cmt.HashDeCommitment, error) {
 	deComBzs := m.GetDeCommitment()
-	return cmt.NewHashDeCommitmentFromBytes(deComBzs)
+	D := cmt.NewHashDeCommitmentFromBytes(deComBzs)
+	if err := cmt.ValidateDeCommitment(D, arity, bound); err != nil {
+		return nil, err
+	}
+	return D, nil
 }
 
 func (m *KGRound2Message2) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
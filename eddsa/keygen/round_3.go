@@ -16,6 +16,7 @@ import (
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
 	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
@@ -53,6 +54,7 @@ func (round *round3) Start() *tss.Error {
 	type vssOut struct {
 		unWrappedErr error
 		pjVs         vss.Vs
+		proof        *schnorr.ZKProof
 	}
 	chs := make([]chan vssOut, len(Ps))
 	for i := range chs {
@@ -65,38 +67,36 @@ func (round *round3) Start() *tss.Error {
 		if j == PIdx {
 			continue
 		}
-		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
 
 		// 6-9.
 		go func(j int, ch chan<- vssOut) {
 			// 4-10.
 			KGCj := round.temp.KGCs[j]
 			r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
-			KGDj := r2msg2.UnmarshalDeCommitment()
+			// the coordinates of the t+1 points of the polynomial
+			KGDj, err := r2msg2.UnmarshalDeCommitment(2*(round.Threshold()+1), round.Params().EC().Params().P)
+			if err != nil {
+				ch <- vssOut{err, nil, nil}
+				return
+			}
 			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj}
 			ok, flatPolyGs := cmtDeCmt.DeCommit()
 			if !ok || flatPolyGs == nil {
-				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
+				ch <- vssOut{commitments.ErrDeCommitVerify, nil, nil}
 				return
 			}
 
 			PjVs, err := crypto.UnFlattenECPoints(round.Params().EC(), flatPolyGs)
-			for i, PjV := range PjVs {
-				PjVs[i] = PjV.EightInvEight()
-			}
-
 			if err != nil {
-				ch <- vssOut{err, nil}
+				ch <- vssOut{err, nil, nil}
 				return
 			}
+			for i, PjV := range PjVs {
+				PjVs[i] = PjV.EightInvEight()
+			}
 			proof, err := r2msg2.UnmarshalZKProof(round.Params().EC())
 			if err != nil {
-				ch <- vssOut{errors.New("failed to unmarshal schnorr proof"), nil}
-				return
-			}
-			ok = proof.Verify(ContextJ, PjVs[0])
-			if !ok {
-				ch <- vssOut{errors.New("failed to prove schnorr proof"), nil}
+				ch <- vssOut{errors.New("failed to unmarshal schnorr proof"), nil, nil}
 				return
 			}
 			r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
@@ -106,35 +106,61 @@ func (round *round3) Start() *tss.Error {
 				Share:     r2msg1.UnmarshalShare(),
 			}
 			if ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs); !ok {
-				ch <- vssOut{errors.New("vss verify failed"), nil}
+				ch <- vssOut{errors.New("vss verify failed"), nil, nil}
 				return
 			}
-			// (9) handled above
-			ch <- vssOut{nil, PjVs}
+			// (9) handled below, for all the parties at once
+			ch <- vssOut{nil, PjVs, proof}
 		}(j, chs[j])
 	}
 
 	// consume unbuffered channels (end the goroutines)
+	// the schnorr proofs of the parties that passed these checks are verified before erroring out, so that all the
+	// culprits of this round are reported together
 	vssResults := make([]vssOut, len(Ps))
+	failed := make([]error, len(Ps))
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		vssResults[j] = <-chs[j]
+		failed[j] = vssResults[j].unWrappedErr
+	}
+	// 9. verify the schnorr proofs of all the other parties with one combined check
 	{
-		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
-		for j, Pj := range Ps {
-			if j == PIdx {
+		sessions := make(map[int][]byte, len(Ps)-1)
+		proofs := make(map[int]*schnorr.ZKProof, len(Ps)-1)
+		Xs := make(map[int]*crypto.ECPoint, len(Ps)-1)
+		for j := range Ps {
+			if j == PIdx || failed[j] != nil {
 				continue
 			}
-			vssResults[j] = <-chs[j]
-			// collect culprits to error out with
-			if err := vssResults[j].unWrappedErr; err != nil {
-				culprits = append(culprits, Pj)
+			sessions[j] = common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+			proofs[j] = vssResults[j].proof
+			Xs[j] = vssResults[j].pjVs[0]
+		}
+		if len(proofs) > 0 {
+			agg, err := schnorr.AggregateProofs(sessions, proofs, Xs)
+			if err != nil {
+				return round.WrapError(err)
+			}
+			if !agg.Verify() {
+				for _, j := range agg.Culprits() {
+					failed[j] = errors.New("failed to prove schnorr proof")
+				}
 			}
 		}
+	}
+	{
 		var multiErr error
-		if len(culprits) > 0 {
-			for _, vssResult := range vssResults {
-				if vssResult.unWrappedErr != nil {
-					multiErr = multierror.Append(multiErr, vssResult.unWrappedErr)
-				}
+		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s), in index order
+		for j, Pj := range Ps {
+			if failed[j] != nil {
+				multiErr = multierror.Append(multiErr, failed[j])
+				culprits = append(culprits, Pj)
 			}
+		}
+		if len(culprits) > 0 {
 			return round.WrapError(multiErr, culprits...)
 		}
 	}
@@ -190,6 +216,9 @@ func (round *round3) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "public key is not on the curve"))
 	}
+	if eddsaPubKey.IsInfinity() {
+		return round.WrapError(errors.New("public key is the identity"))
+	}
 	round.save.EDDSAPub = eddsaPubKey
 
 	// PRINT public key & private share
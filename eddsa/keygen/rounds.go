@@ -88,11 +88,15 @@ func (round *base) resetOK() {
 
 // get ssid from local params
 func (round *base) getSSID() ([]byte, error) {
-	ssidList := []*big.Int{round.EC().Params().P, round.EC().Params().N, round.EC().Params().Gx, round.EC().Params().Gy} // ec curve
-	ssidList = append(ssidList, round.Parties().IDs().Keys()...)
-	ssidList = append(ssidList, big.NewInt(int64(round.number))) // round number
-	ssidList = append(ssidList, round.temp.ssidNonce)
-	ssid := common.SHA512_256i(ssidList...).Bytes()
+	return sessionID(round.Params(), round.number, round.temp.ssidNonce), nil
+}
 
-	return ssid, nil
+// sessionID returns the ssid of a keygen session with the given parameters, as computed in the round `number`
+func sessionID(params *tss.Parameters, number int, ssidNonce *big.Int) []byte {
+	ec := params.EC()
+	ssidList := []*big.Int{ec.Params().P, ec.Params().N, ec.Params().Gx, ec.Params().Gy} // ec curve
+	ssidList = append(ssidList, params.Parties().IDs().Keys()...)
+	ssidList = append(ssidList, big.NewInt(int64(number))) // round number
+	ssidList = append(ssidList, ssidNonce)
+	return common.SHA512_256i(ssidList...).Bytes()
 }
@@ -8,9 +8,12 @@ package keygen
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
@@ -21,6 +24,9 @@ type (
 	}
 
 	// Everything in LocalPartySaveData is saved locally to user's HD when done
+	//
+	// Signing only reads the save data, so one LocalPartySaveData can back any number of concurrent signing sessions.
+	// Resharing is different: an old committee member wipes its Xi when the resharing completes.
 	LocalPartySaveData struct {
 		LocalSecrets
 
@@ -42,6 +48,7 @@ func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
 }
 
 // BuildLocalSaveDataSubset re-creates the LocalPartySaveData to contain data for only the list of signing parties.
+// The Ks and BigXj slices are new, but the integers and points in them, Xi and EDDSAPub are shared with sourceData.
 func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.SortedPartyIDs) LocalPartySaveData {
 	keysToIndices := make(map[string]int, len(sourceData.Ks))
 	for j, kj := range sourceData.Ks {
@@ -60,3 +67,71 @@ func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.Sorte
 	}
 	return newData
 }
+
+// DerivePublicKey recomputes the group public key of a keygen ceremony from the saved data of some or all of its
+// parties, so that the ceremony can be audited afterwards. The key is interpolated at 0 from the public shares BigXj
+// of all the parties. It returns an error if the saves disagree on the parties or their public shares, if a party's
+// secret share Xi does not match its public share, or if the derived key differs from a saved EDDSAPub.
+func DerivePublicKey(saves []LocalPartySaveData) (*crypto.ECPoint, error) {
+	if len(saves) == 0 {
+		return nil, errors.New("DerivePublicKey() received no save data")
+	}
+	ks, bigXj := saves[0].Ks, saves[0].BigXj
+	if len(ks) == 0 || len(ks) != len(bigXj) {
+		return nil, errors.New("DerivePublicKey() received save data with mismatched Ks and BigXj")
+	}
+	for j, Xj := range bigXj {
+		if ks[j] == nil || Xj == nil || !Xj.ValidateBasic() {
+			return nil, fmt.Errorf("DerivePublicKey() received an invalid Ks or BigXj at index %d", j)
+		}
+	}
+	ec := bigXj[0].Curve()
+	if _, err := vss.CheckIndexes(ec, ks); err != nil {
+		return nil, fmt.Errorf("DerivePublicKey(): %v", err)
+	}
+
+	// y = sum over j of lambda_j * Xj, with the Lagrange coefficients lambda_j at 0
+	coefs, err := vss.LagrangeCoefficients(ec.Params().N, ks)
+	if err != nil {
+		return nil, fmt.Errorf("DerivePublicKey(): %v", err)
+	}
+	var y *crypto.ECPoint
+	for j, Xj := range bigXj {
+		term := Xj.ScalarMult(coefs[j])
+		if y == nil {
+			y = term
+			continue
+		}
+		if y, err = y.Add(term); err != nil {
+			return nil, fmt.Errorf("DerivePublicKey(): %v", err)
+		}
+	}
+
+	for i, save := range saves {
+		if len(save.Ks) != len(ks) || len(save.BigXj) != len(bigXj) {
+			return nil, fmt.Errorf("DerivePublicKey(): save %d has a different set of parties", i)
+		}
+		own := -1
+		for j := range ks {
+			if save.Ks[j] == nil || save.Ks[j].Cmp(ks[j]) != 0 {
+				return nil, fmt.Errorf("DerivePublicKey(): save %d has a different set of parties", i)
+			}
+			if save.BigXj[j] == nil || !save.BigXj[j].Equals(bigXj[j]) {
+				return nil, fmt.Errorf("DerivePublicKey(): save %d has a different public share for party %d", i, j)
+			}
+			if save.ShareID != nil && save.ShareID.Cmp(ks[j]) == 0 {
+				own = j
+			}
+		}
+		if own < 0 || save.Xi == nil {
+			return nil, fmt.Errorf("DerivePublicKey(): save %d has no secret share of a known party", i)
+		}
+		if !crypto.ScalarBaseMult(ec, save.Xi).Equals(bigXj[own]) {
+			return nil, fmt.Errorf("DerivePublicKey(): the secret share of save %d does not match its public share", i)
+		}
+		if save.EDDSAPub != nil && !save.EDDSAPub.Equals(y) {
+			return nil, fmt.Errorf("DerivePublicKey(): the public key of save %d differs from the derived one", i)
+		}
+	}
+	return y, nil
+}
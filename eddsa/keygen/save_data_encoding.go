@@ -0,0 +1,213 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// The binary save data format is
+//
+//	magic "TSSE" | version uint16 | body | SHA-256 of everything before it
+//
+// where the body of version 1 holds the curve name, the threshold and the party count followed by ShareID, Xi, the
+// Ks, the X and Y of the BigXj and of EDDSAPub. The counts are big-endian uint32s, the curve name a uint16-prefixed
+// string and every integer a uint32-prefixed big-endian byte string.
+const (
+	SaveDataVersion = 1
+
+	saveDataMagic       = "TSSE"
+	saveDataHeaderLen   = len(saveDataMagic) + 2
+	saveDataChecksumLen = sha256.Size
+)
+
+var (
+	// ErrSaveDataVersion is returned for save data written in a version of the format that this code does not know
+	ErrSaveDataVersion = errors.New("unsupported save data version")
+
+	// ErrSaveDataChecksum is returned for save data whose checksum does not match, e.g. a corrupted file
+	ErrSaveDataChecksum = errors.New("save data checksum mismatch")
+)
+
+// EncodeSaveData encodes the save data of a party, together with the threshold of its keygen, in the versioned binary
+// format read by DecodeSaveData
+func EncodeSaveData(save LocalPartySaveData, threshold int) ([]byte, error) {
+	n := len(save.Ks)
+	if n == 0 || len(save.BigXj) != n {
+		return nil, errors.New("EncodeSaveData() received save data with mismatched Ks and BigXj")
+	}
+	if threshold < 1 || threshold >= n {
+		return nil, fmt.Errorf("EncodeSaveData() received a threshold of %d for %d parties", threshold, n)
+	}
+	if save.Xi == nil || save.ShareID == nil || save.EDDSAPub == nil {
+		return nil, errors.New("EncodeSaveData() received save data without Xi, ShareID or EDDSAPub")
+	}
+	curveName, ok := tss.GetCurveName(save.EDDSAPub.Curve())
+	if !ok {
+		return nil, errors.New("EncodeSaveData() received a public key on an unregistered curve")
+	}
+	ints := make([]*big.Int, 0, 2+3*n+2)
+	ints = append(ints, save.ShareID, save.Xi)
+	ints = append(ints, save.Ks...)
+	for j, Xj := range save.BigXj {
+		if save.Ks[j] == nil || Xj == nil {
+			return nil, fmt.Errorf("EncodeSaveData() received a nil Ks or BigXj at index %d", j)
+		}
+		ints = append(ints, Xj.X(), Xj.Y())
+	}
+	ints = append(ints, save.EDDSAPub.X(), save.EDDSAPub.Y())
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(saveDataMagic)
+	_ = binary.Write(buf, binary.BigEndian, uint16(SaveDataVersion))
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(curveName)))
+	buf.WriteString(string(curveName))
+	_ = binary.Write(buf, binary.BigEndian, uint32(threshold))
+	_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	for _, x := range ints {
+		if x.Sign() < 0 {
+			return nil, errors.New("EncodeSaveData() received a negative integer")
+		}
+		bz := x.Bytes()
+		_ = binary.Write(buf, binary.BigEndian, uint32(len(bz)))
+		buf.Write(bz)
+	}
+	checksum := sha256.Sum256(buf.Bytes())
+	buf.Write(checksum[:])
+	return buf.Bytes(), nil
+}
+
+// DecodeSaveData decodes and validates save data written by EncodeSaveData and returns it with the threshold of its
+// keygen. Besides the checksum it checks that 1 <= threshold < party count, that the party indexes are distinct and
+// nonzero, that the public shares lie on a single polynomial of degree threshold whose value at 0 is EDDSAPub, and
+// that Xi is the secret of this party's public share. Data of an unknown version fails with ErrSaveDataVersion.
+func DecodeSaveData(bz []byte) (LocalPartySaveData, int, error) {
+	var save LocalPartySaveData
+	if len(bz) < saveDataHeaderLen+saveDataChecksumLen || string(bz[:len(saveDataMagic)]) != saveDataMagic {
+		return save, 0, errors.New("DecodeSaveData(): the data is not in the save data format")
+	}
+	if version := binary.BigEndian.Uint16(bz[len(saveDataMagic):saveDataHeaderLen]); version != SaveDataVersion {
+		return save, 0, fmt.Errorf("DecodeSaveData(): %w %d", ErrSaveDataVersion, version)
+	}
+	body, checksum := bz[:len(bz)-saveDataChecksumLen], bz[len(bz)-saveDataChecksumLen:]
+	if expected := sha256.Sum256(body); !bytes.Equal(expected[:], checksum) {
+		return save, 0, fmt.Errorf("DecodeSaveData(): %w", ErrSaveDataChecksum)
+	}
+
+	r := bytes.NewReader(body[saveDataHeaderLen:])
+	var nameLen uint16
+	if err := binary.Read(r, binary.BigEndian, &nameLen); err != nil || int(nameLen) > r.Len() {
+		return save, 0, errors.New("DecodeSaveData(): the curve name is truncated")
+	}
+	name := make([]byte, nameLen)
+	_, _ = r.Read(name)
+	ec, ok := tss.GetCurveByName(tss.CurveName(name))
+	if !ok {
+		return save, 0, fmt.Errorf("DecodeSaveData(): unknown curve %q", name)
+	}
+	var threshold, n uint32
+	if binary.Read(r, binary.BigEndian, &threshold) != nil || binary.Read(r, binary.BigEndian, &n) != nil {
+		return save, 0, errors.New("DecodeSaveData(): the counts are truncated")
+	}
+	// every integer takes at least its 4-byte length, which bounds n before allocating
+	if n == 0 || (3*uint64(n)+4)*4 > uint64(r.Len()) {
+		return save, 0, fmt.Errorf("DecodeSaveData(): the data cannot hold %d parties", n)
+	}
+	if threshold < 1 || threshold >= n {
+		return save, 0, fmt.Errorf("DecodeSaveData(): the threshold %d is inconsistent with %d parties", threshold, n)
+	}
+	ints := make([]*big.Int, 2+3*int(n)+2)
+	for i := range ints {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil || int64(size) > int64(r.Len()) {
+			return save, 0, errors.New("DecodeSaveData(): an integer is truncated")
+		}
+		intBz := make([]byte, size)
+		_, _ = r.Read(intBz)
+		ints[i] = new(big.Int).SetBytes(intBz)
+	}
+	if r.Len() != 0 {
+		return save, 0, fmt.Errorf("DecodeSaveData(): %d trailing bytes", r.Len())
+	}
+
+	save = NewLocalPartySaveData(int(n))
+	save.ShareID, save.Xi = ints[0], ints[1]
+	copy(save.Ks, ints[2:2+n])
+	points := ints[2+n:]
+	for j := range save.BigXj {
+		Xj, err := crypto.NewECPoint(ec, points[2*j], points[2*j+1])
+		if err != nil {
+			return LocalPartySaveData{}, 0, fmt.Errorf("DecodeSaveData(): the public share %d is invalid: %v", j, err)
+		}
+		save.BigXj[j] = Xj
+	}
+	pub, err := crypto.NewECPoint(ec, points[2*n], points[2*n+1])
+	if err != nil {
+		return LocalPartySaveData{}, 0, fmt.Errorf("DecodeSaveData(): the public key is invalid: %v", err)
+	}
+	save.EDDSAPub = pub
+	if err := save.validateShares(int(threshold)); err != nil {
+		return LocalPartySaveData{}, 0, fmt.Errorf("DecodeSaveData(): %v", err)
+	}
+	return save, int(threshold), nil
+}
+
+// validateShares checks that the public shares interpolate to EDDSAPub with a polynomial of degree `threshold` and
+// that Xi belongs to the party of ShareID
+func (save LocalPartySaveData) validateShares(threshold int) error {
+	ec := save.EDDSAPub.Curve()
+	if _, err := vss.CheckIndexes(ec, save.Ks); err != nil {
+		return err
+	}
+	q := ec.Params().N
+	// the first threshold+1 shares determine the polynomial; the others and the key must be its values
+	basis := save.Ks[:threshold+1]
+	interpolate := func(x *big.Int) (*crypto.ECPoint, error) {
+		coefs, err := vss.LagrangeCoefficientsAt(q, basis, x)
+		if err != nil {
+			return nil, err
+		}
+		var sum *crypto.ECPoint
+		for m, coef := range coefs {
+			term := save.BigXj[m].ScalarMult(coef)
+			if sum == nil {
+				sum = term
+				continue
+			}
+			if sum, err = sum.Add(term); err != nil {
+				return nil, err
+			}
+		}
+		return sum, nil
+	}
+	for j := threshold + 1; j < len(save.Ks); j++ {
+		if Xj, err := interpolate(save.Ks[j]); err != nil || !Xj.Equals(save.BigXj[j]) {
+			return fmt.Errorf("the public share %d is not consistent with a threshold of %d", j, threshold)
+		}
+	}
+	if y, err := interpolate(big.NewInt(0)); err != nil || !y.Equals(save.EDDSAPub) {
+		return errors.New("the public key does not match the public shares")
+	}
+	for j, kj := range save.Ks {
+		if kj.Cmp(save.ShareID) == 0 {
+			if !crypto.ScalarBaseMult(ec, save.Xi).Equal(save.BigXj[j]) {
+				return errors.New("the secret share does not match its public share")
+			}
+			return nil
+		}
+	}
+	return errors.New("the ShareID is not one of the party indexes")
+}
@@ -0,0 +1,133 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestSaveDataEncodingRoundTrip(t *testing.T) {
+	saves, _, err := LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	for i, save := range saves {
+		bz, err := EncodeSaveData(save, testThreshold)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		decoded, threshold, err := DecodeSaveData(bz)
+		if !assert.NoError(t, err, "save %d", i) {
+			continue
+		}
+		assert.Equal(t, testThreshold, threshold)
+		assert.Equal(t, 0, save.Xi.Cmp(decoded.Xi))
+		assert.Equal(t, 0, save.ShareID.Cmp(decoded.ShareID))
+		assert.Equal(t, save.Ks, decoded.Ks)
+		for j := range save.BigXj {
+			assert.True(t, save.BigXj[j].Equals(decoded.BigXj[j]))
+		}
+		assert.True(t, save.EDDSAPub.Equals(decoded.EDDSAPub))
+	}
+}
+
+func TestDecodeSaveDataRejectsUnknownVersion(t *testing.T) {
+	saves, _, err := LoadKeygenTestFixtures(1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	bz, err := EncodeSaveData(saves[0], testThreshold)
+	assert.NoError(t, err)
+
+	binary.BigEndian.PutUint16(bz[len(saveDataMagic):], SaveDataVersion+1)
+	_, _, err = DecodeSaveData(bz)
+	assert.True(t, errors.Is(err, ErrSaveDataVersion), "unexpected error: %v", err)
+
+	copy(bz, "XXXX")
+	_, _, err = DecodeSaveData(bz)
+	assert.Error(t, err, "data without the magic must be rejected")
+	_, _, err = DecodeSaveData(nil)
+	assert.Error(t, err)
+}
+
+func TestDecodeSaveDataDetectsFlippedBytes(t *testing.T) {
+	saves, _, err := LoadKeygenTestFixtures(1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	bz, err := EncodeSaveData(saves[0], testThreshold)
+	assert.NoError(t, err)
+
+	// the version bytes are covered by TestDecodeSaveDataRejectsUnknownVersion
+	for i := saveDataHeaderLen; i < len(bz); i++ {
+		flipped := append([]byte(nil), bz...)
+		flipped[i] ^= 0x01
+		_, _, err := DecodeSaveData(flipped)
+		if !assert.Error(t, err, "flipping byte %d must be detected", i) {
+			continue
+		}
+		assert.True(t, errors.Is(err, ErrSaveDataChecksum), "byte %d: unexpected error: %v", i, err)
+	}
+	_, _, err = DecodeSaveData(bz[:len(bz)-1])
+	assert.Error(t, err, "truncated data must be rejected")
+}
+
+func TestDecodeSaveDataValidatesShares(t *testing.T) {
+	saves, _, err := LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	one := big.NewInt(1)
+
+	// a public share that is not on the sharing polynomial
+	mutated := copySaves(saves)[0]
+	mutated.BigXj[testParticipants-1] = crypto.ScalarBaseMult(tss.Edwards(), one)
+	bz, err := EncodeSaveData(mutated, testThreshold)
+	assert.NoError(t, err)
+	_, _, err = DecodeSaveData(bz)
+	assert.Error(t, err)
+
+	// a secret share that does not match its public share
+	mutated = copySaves(saves)[0]
+	mutated.Xi = new(big.Int).Add(mutated.Xi, one)
+	bz, err = EncodeSaveData(mutated, testThreshold)
+	assert.NoError(t, err)
+	_, _, err = DecodeSaveData(bz)
+	assert.Error(t, err)
+
+	// a public key that does not reconstruct from the shares
+	mutated = copySaves(saves)[0]
+	mutated.EDDSAPub = mutated.EDDSAPub.ScalarMult(big.NewInt(2))
+	bz, err = EncodeSaveData(mutated, testThreshold)
+	assert.NoError(t, err)
+	_, _, err = DecodeSaveData(bz)
+	assert.Error(t, err)
+
+	// the shares of a degree-2 polynomial do not lie on one of degree 1
+	bz, err = EncodeSaveData(saves[0], testThreshold-1)
+	assert.NoError(t, err)
+	_, _, err = DecodeSaveData(bz)
+	assert.Error(t, err)
+
+	// a threshold that no keygen can have, with a valid checksum
+	bz, err = EncodeSaveData(saves[0], testThreshold)
+	assert.NoError(t, err)
+	name, _ := tss.GetCurveName(tss.Edwards())
+	binary.BigEndian.PutUint32(bz[saveDataHeaderLen+2+len(name):], testParticipants)
+	checksum := sha256.Sum256(bz[:len(bz)-saveDataChecksumLen])
+	copy(bz[len(bz)-saveDataChecksumLen:], checksum[:])
+	_, _, err = DecodeSaveData(bz)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "inconsistent")
+	}
+	_, err = EncodeSaveData(saves[0], testParticipants)
+	assert.Error(t, err)
+	_, err = EncodeSaveData(saves[0], 0)
+	assert.Error(t, err)
+}
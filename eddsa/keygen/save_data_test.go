@@ -0,0 +1,72 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestDerivePublicKey(t *testing.T) {
+	saves, _, err := LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	y, err := DerivePublicKey(saves)
+	assert.NoError(t, err)
+	assert.True(t, y.Equals(saves[0].EDDSAPub), "the derived key must be the group key")
+
+	// a single save is enough
+	y, err = DerivePublicKey(saves[2:3])
+	assert.NoError(t, err)
+	assert.True(t, y.Equals(saves[0].EDDSAPub))
+
+	_, err = DerivePublicKey(nil)
+	assert.Error(t, err)
+}
+
+func TestDerivePublicKeyDetectsInconsistentShares(t *testing.T) {
+	saves, _, err := LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	one := big.NewInt(1)
+
+	// one party's secret share is changed
+	mutated := copySaves(saves)
+	mutated[1].Xi = new(big.Int).Add(mutated[1].Xi, one)
+	_, err = DerivePublicKey(mutated)
+	assert.Error(t, err)
+
+	// one party has a different view of a public share
+	mutated = copySaves(saves)
+	mutated[3].BigXj[0] = mutated[3].BigXj[0].ScalarMult(big.NewInt(2))
+	_, err = DerivePublicKey(mutated)
+	assert.Error(t, err)
+
+	// all the parties agree on a public share that is not on the sharing polynomial
+	mutated = copySaves(saves)
+	bad := crypto.ScalarBaseMult(tss.Edwards(), one)
+	for i := range mutated {
+		mutated[i].BigXj[4] = bad
+	}
+	_, err = DerivePublicKey(mutated[:4])
+	assert.Error(t, err)
+}
+
+// copySaves copies the slices of the saves so that they can be changed independently
+func copySaves(saves []LocalPartySaveData) []LocalPartySaveData {
+	copied := make([]LocalPartySaveData, len(saves))
+	for i, save := range saves {
+		copied[i] = save
+		copied[i].Ks = append([]*big.Int(nil), save.Ks...)
+		copied[i].BigXj = append([]*crypto.ECPoint(nil), save.BigXj...)
+	}
+	return copied
+}
@@ -0,0 +1,155 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// VerifyTranscript replays the recorded messages of a complete keygen session without running any party and returns
+// the public key that the session produced. `params` describe the session: its curve, parties and threshold; its
+// party ID is reported as the victim of the errors.
+//
+// The transcript must hold the round 1 commitment and the round 2 de-commitment and Schnorr proof of every party, in
+// any order. Each de-commitment is checked against its commitment and each proof against the constant term of the
+// party's polynomial. The p2p shares of round 2 are checked against the polynomial of their sender if the transcript
+// holds them; they must carry their recipient, as the messages that the parties send do.
+//
+// The first failure is returned as a *tss.Error that names the party at fault, checking the parties in index order.
+func VerifyTranscript(messages []tss.ParsedMessage, params *tss.Parameters) (*crypto.ECPoint, error) {
+	if params == nil || params.Parties() == nil || len(params.Parties().IDs()) == 0 {
+		return nil, errors.New("VerifyTranscript() received parameters without parties")
+	}
+	Ps := params.Parties().IDs()
+	fail := func(round int, err error, culprits ...*tss.PartyID) (*crypto.ECPoint, error) {
+		return nil, tss.NewError(err, TaskName, round, params.PartyID(), culprits...)
+	}
+	indexOf := func(pID *tss.PartyID) int {
+		if pID == nil || pID.Key == nil {
+			return -1
+		}
+		for j, Pj := range Ps {
+			if Pj.KeyInt().Cmp(pID.KeyInt()) == 0 {
+				return j
+			}
+		}
+		return -1
+	}
+
+	// sort the messages by sender, rejecting duplicates
+	r1msgs := make([]*KGRound1Message, len(Ps))
+	r2msg2s := make([]*KGRound2Message2, len(Ps))
+	r2msg1s := make(map[[2]int]*KGRound2Message1)
+	for _, msg := range messages {
+		if msg == nil {
+			return fail(0, errors.New("the transcript holds a nil message"))
+		}
+		j := indexOf(msg.GetFrom())
+		if j < 0 {
+			return fail(0, fmt.Errorf("the transcript holds a message from %v, which is not a party of the session", msg.GetFrom()))
+		}
+		Pj := Ps[j]
+		if !msg.ValidateBasic() {
+			return fail(0, fmt.Errorf("the transcript holds an invalid %s", msg.Type()), Pj)
+		}
+		switch content := msg.Content().(type) {
+		case *KGRound1Message:
+			if !msg.IsBroadcast() || r1msgs[j] != nil {
+				return fail(1, errors.New("the transcript holds an unexpected round 1 message"), Pj)
+			}
+			r1msgs[j] = content
+		case *KGRound2Message2:
+			if !msg.IsBroadcast() || r2msg2s[j] != nil {
+				return fail(2, errors.New("the transcript holds an unexpected round 2 de-commitment"), Pj)
+			}
+			r2msg2s[j] = content
+		case *KGRound2Message1:
+			if msg.IsBroadcast() || len(msg.GetTo()) != 1 {
+				return fail(2, errors.New("the transcript holds a round 2 share without a single recipient"), Pj)
+			}
+			k := indexOf(msg.GetTo()[0])
+			if k < 0 || r2msg1s[[2]int{j, k}] != nil {
+				return fail(2, errors.New("the transcript holds an unexpected round 2 share"), Pj)
+			}
+			r2msg1s[[2]int{j, k}] = content
+		default:
+			return fail(0, fmt.Errorf("the transcript holds a %s, which is not a keygen message", msg.Type()), Pj)
+		}
+	}
+	for j, Pj := range Ps {
+		if r1msgs[j] == nil {
+			return fail(1, errors.New("the transcript holds no round 1 message of the party"), Pj)
+		}
+		if r2msg2s[j] == nil {
+			return fail(2, errors.New("the transcript holds no round 2 de-commitment of the party"), Pj)
+		}
+	}
+
+	// re-run the checks of round 3 for every party
+	ec := params.EC()
+	ssid := sessionID(params, 1, big.NewInt(0))
+	pjVs := make([]vss.Vs, len(Ps))
+	for j, Pj := range Ps {
+		KGDj, err := r2msg2s[j].UnmarshalDeCommitment(2*(params.Threshold()+1), ec.Params().P)
+		if err != nil {
+			return fail(3, err, Pj)
+		}
+		cmtDeCmt := commitments.HashCommitDecommit{C: r1msgs[j].UnmarshalCommitment(), D: KGDj}
+		ok, flatPolyGs := cmtDeCmt.DeCommit()
+		if !ok || flatPolyGs == nil {
+			return fail(3, commitments.ErrDeCommitVerify, Pj)
+		}
+		Vs, err := crypto.UnFlattenECPoints(ec, flatPolyGs)
+		if err != nil {
+			return fail(3, err, Pj)
+		}
+		for c, V := range Vs {
+			Vs[c] = V.EightInvEight()
+		}
+		proof, err := r2msg2s[j].UnmarshalZKProof(ec)
+		if err != nil {
+			return fail(3, errors.New("failed to unmarshal schnorr proof"), Pj)
+		}
+		if !proof.Verify(common.AppendBigIntToBytesSlice(ssid, big.NewInt(int64(j))), Vs[0]) {
+			return fail(3, errors.New("failed to prove schnorr proof"), Pj)
+		}
+		pjVs[j] = Vs
+	}
+	for j, Pj := range Ps {
+		for k, Pk := range Ps {
+			r2msg1, ok := r2msg1s[[2]int{j, k}]
+			if !ok {
+				continue
+			}
+			share := vss.Share{Threshold: params.Threshold(), ID: Pk.KeyInt(), Share: r2msg1.UnmarshalShare()}
+			if !share.Verify(ec, params.Threshold(), pjVs[j]) {
+				return fail(3, fmt.Errorf("vss verify failed for the share sent to %v", Pk), Pj)
+			}
+		}
+	}
+
+	// the public key is the sum of the constant terms of all the polynomials
+	pubKey := pjVs[0][0]
+	for j := 1; j < len(Ps); j++ {
+		var err error
+		if pubKey, err = pubKey.Add(pjVs[j][0]); err != nil {
+			return fail(3, errors.New("adding the constant terms resulted in a point not on the curve"), Ps[j])
+		}
+	}
+	if pubKey.IsInfinity() {
+		return fail(3, errors.New("public key is the identity"))
+	}
+	return pubKey, nil
+}
@@ -0,0 +1,203 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// recordKeygen runs a keygen and returns every message that the parties sent, together with the saved key of party 0
+func recordKeygen(t *testing.T) ([]tss.ParsedMessage, *tss.Parameters, *LocalPartySaveData) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		parties = append(parties, NewLocalParty(params, outCh, endCh).(*LocalParty))
+	}
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	var transcript []tss.ParsedMessage
+	var save *LocalPartySaveData
+	timeout := time.After(time.Minute)
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			transcript = append(transcript, msg.(tss.ParsedMessage))
+			if dest := msg.GetTo(); dest != nil {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				continue
+			}
+			for _, P := range parties {
+				if P.PartyID().Index != msg.GetFrom().Index {
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			}
+		case data := <-endCh:
+			if data.ShareID.Cmp(pIDs[0].KeyInt()) == 0 {
+				save = data
+			}
+			ended++
+		case <-timeout:
+			assert.FailNow(t, "timed out waiting for keygen")
+		}
+	}
+	return transcript, tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), testThreshold), save
+}
+
+// replaced returns a copy of the transcript with the messages for which `tamper` returns a non-nil message replaced
+func replaced(transcript []tss.ParsedMessage, tamper func(tss.ParsedMessage) tss.ParsedMessage) []tss.ParsedMessage {
+	out := make([]tss.ParsedMessage, len(transcript))
+	for n, msg := range transcript {
+		out[n] = msg
+		if msg2 := tamper(msg); msg2 != nil {
+			out[n] = msg2
+		}
+	}
+	return out
+}
+
+func TestVerifyTranscript(t *testing.T) {
+	setUp("info")
+
+	transcript, params, save := recordKeygen(t)
+	pubKey, err := VerifyTranscript(transcript, params)
+	if assert.NoError(t, err) {
+		assert.True(t, save.EDDSAPub.Equals(pubKey), "the transcript must yield the key of the parties")
+	}
+
+	// the shares are optional
+	broadcasts := make([]tss.ParsedMessage, 0, len(transcript))
+	for _, msg := range transcript {
+		if msg.IsBroadcast() {
+			broadcasts = append(broadcasts, msg)
+		}
+	}
+	pubKey, err = VerifyTranscript(broadcasts, params)
+	if assert.NoError(t, err) {
+		assert.True(t, save.EDDSAPub.Equals(pubKey))
+	}
+}
+
+func TestVerifyTranscriptAttributesTampering(t *testing.T) {
+	setUp("info")
+
+	transcript, params, _ := recordKeygen(t)
+	Ps := params.Parties().IDs()
+	for name, tc := range map[string]struct {
+		tamper  func(tss.ParsedMessage) tss.ParsedMessage
+		culprit int
+		round   int
+		cause   error
+	}{
+		"bad schnorr proof": {
+			tamper: func(msg tss.ParsedMessage) tss.ParsedMessage {
+				if _, ok := msg.Content().(*KGRound2Message2); ok && msg.GetFrom().Index == 2 {
+					return badProof(msg)
+				}
+				return nil
+			},
+			culprit: 2, round: 3,
+		},
+		"commitment swapped": {
+			tamper: func(msg tss.ParsedMessage) tss.ParsedMessage {
+				if content, ok := msg.Content().(*KGRound1Message); ok && msg.GetFrom().Index == 4 {
+					C := new(big.Int).Add(content.UnmarshalCommitment(), big.NewInt(1))
+					return NewKGRound1Message(msg.GetFrom(), C)
+				}
+				return nil
+			},
+			culprit: 4, round: 3, cause: commitments.ErrDeCommitVerify,
+		},
+		"bad share": {
+			tamper: func(msg tss.ParsedMessage) tss.ParsedMessage {
+				if content, ok := msg.Content().(*KGRound2Message1); ok && msg.GetFrom().Index == 1 && msg.GetTo()[0].Index == 3 {
+					share := &vss.Share{Share: new(big.Int).Add(content.UnmarshalShare(), big.NewInt(1))}
+					return NewKGRound2Message1(msg.GetTo()[0], msg.GetFrom(), share)
+				}
+				return nil
+			},
+			culprit: 1, round: 3,
+		},
+	} {
+		_, err := VerifyTranscript(replaced(transcript, tc.tamper), params)
+		var tssErr *tss.Error
+		if assert.True(t, errors.As(err, &tssErr), name) {
+			assert.Equal(t, []*tss.PartyID{Ps[tc.culprit]}, tssErr.Culprits(), name)
+			assert.Equal(t, tc.round, tssErr.Round(), name)
+			if tc.cause != nil {
+				assert.Equal(t, tc.cause, tssErr.Cause(), name)
+			}
+		}
+	}
+
+	// a missing or repeated message is attributed to its sender as well
+	for n, msg := range transcript {
+		if _, ok := msg.Content().(*KGRound2Message2); ok {
+			_, err := VerifyTranscript(append(append([]tss.ParsedMessage{}, transcript[:n]...), transcript[n+1:]...), params)
+			var tssErr *tss.Error
+			if assert.True(t, errors.As(err, &tssErr)) {
+				assert.Equal(t, []*tss.PartyID{Ps[msg.GetFrom().Index]}, tssErr.Culprits())
+			}
+			_, err = VerifyTranscript(append(append([]tss.ParsedMessage{}, transcript...), msg), params)
+			if assert.True(t, errors.As(err, &tssErr)) {
+				assert.Equal(t, []*tss.PartyID{Ps[msg.GetFrom().Index]}, tssErr.Culprits())
+			}
+			break
+		}
+	}
+}
+
+func TestExpectedMessagesMatchTraffic(t *testing.T) {
+	setUp("info")
+
+	transcript, params, _ := recordKeygen(t)
+	P := NewLocalParty(params, make(chan tss.Message, 1), make(chan *LocalPartySaveData, 1)).(*LocalParty)
+	me := params.PartyID()
+	// the messages that reached party 0, by the round that consumes them
+	broadcasts, p2ps := make(map[int]int), make(map[int]int)
+	for _, msg := range transcript {
+		round := 1
+		if _, ok := msg.Content().(*KGRound1Message); !ok {
+			round = 2
+		}
+		switch {
+		case msg.GetFrom().Index == me.Index:
+		case msg.IsBroadcast():
+			broadcasts[round]++
+		case msg.GetTo()[0].Index == me.Index:
+			p2ps[round]++
+		}
+	}
+	for round := 1; round <= 3; round++ {
+		broadcast, p2p := P.ExpectedMessages(round)
+		assert.Equal(t, broadcasts[round], broadcast, "round %d", round)
+		assert.Equal(t, p2ps[round], p2p, "round %d", round)
+	}
+	broadcast, p2p := P.ExpectedMessages(4)
+	assert.Zero(t, broadcast+p2p)
+}
@@ -228,3 +228,169 @@ signing:
 		}
 	}
 }
+
+func TestE2EReshareChangesThreshold(t *testing.T) {
+	setUp("info")
+
+	// the fixtures are a 3-of-5 key (threshold 2); three of its parties reshare it to a 4-of-7 committee (threshold 3)
+	threshold, newThreshold := testThreshold, testThreshold+1
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(threshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pub := oldKeys[0].EDDSAPub
+	newPIDs := tss.GenerateTestPartyIDs(7)
+
+	newKeys := reshare(t, oldKeys, oldPIDs, testParticipants, threshold, newPIDs, newThreshold)
+	for j, key := range newKeys {
+		assert.True(t, key.EDDSAPub.Equals(pub), "the public key must not change")
+		assert.True(t, key.BigXj[j].Equals(crypto.ScalarBaseMult(tss.Edwards(), key.Xi)), "ensure BigX_j == g^x_j")
+	}
+	for _, key := range oldKeys {
+		assert.Equal(t, 0, key.Xi.Sign(), "the old shares must be invalidated")
+	}
+	derived, err := keygen.DerivePublicKey(newKeys)
+	assert.NoError(t, err)
+	assert.True(t, derived.Equals(pub))
+
+	// any 4 of the new shares recover the key, 3 do not
+	ks := make([]*big.Int, 0, len(newPIDs))
+	for _, pID := range newPIDs {
+		ks = append(ks, pID.KeyInt())
+	}
+	sumOfShares := func(from, to int) *crypto.ECPoint {
+		var sum *crypto.ECPoint
+		for i := from; i < to; i++ {
+			wi := signing.PrepareForSigning(tss.Edwards(), i-from, to-from, newKeys[i].Xi, ks[from:to])
+			if wiG := crypto.ScalarBaseMult(tss.Edwards(), wi); sum == nil {
+				sum = wiG
+			} else {
+				sum, err = sum.Add(wiG)
+				assert.NoError(t, err)
+			}
+		}
+		return sum
+	}
+	assert.True(t, sumOfShares(2, 6).Equals(pub))
+	assert.False(t, sumOfShares(2, 5).Equals(pub))
+
+	// 4 of the 7 new parties sign; their IDs are copied so that sorting them does not re-index newPIDs
+	signPIDs := make(tss.UnSortedPartyIDs, 0, newThreshold+1)
+	for _, pID := range newPIDs[2:6] {
+		signPIDs = append(signPIDs, tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+	}
+	msg := big.NewInt(42)
+	signData := sign(t, newKeys[2:6], tss.SortPartyIDs(signPIDs), newThreshold, msg)
+	assert.True(t, signing.Verify(pub, msg, new(big.Int).SetBytes(signData.R), new(big.Int).SetBytes(signData.S)))
+	pk := edwards.PublicKey{Curve: tss.Edwards(), X: pub.X(), Y: pub.Y()}
+	parsed, err := edwards.ParseSignature(signData.Signature)
+	assert.NoError(t, err)
+	assert.True(t, edwards.Verify(&pk, msg.Bytes(), parsed.R, parsed.S), "eddsa verify must pass under the original key")
+}
+
+func TestReshareRejectsUnsatisfiableThreshold(t *testing.T) {
+	setUp("info")
+
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	newPIDs := tss.GenerateTestPartyIDs(3)
+	params := tss.NewReSharingParameters(tss.Edwards(), tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs),
+		oldPIDs[0], testParticipants, testThreshold, len(newPIDs), len(newPIDs))
+	P := NewLocalParty(params, oldKeys[0], make(chan tss.Message, 10), make(chan *keygen.LocalPartySaveData, 1))
+	tssErr := P.Start()
+	if assert.NotNil(t, tssErr) {
+		assert.Contains(t, tssErr.Error(), "new t+1=4 is not satisfied by the new committee of 3")
+	}
+}
+
+// reshare runs resharing from the old committee to the new one and returns the new save data in the order of newPIDs
+func reshare(t *testing.T, oldKeys []keygen.LocalPartySaveData, oldPIDs tss.SortedPartyIDs, partyCount, threshold int,
+	newPIDs tss.SortedPartyIDs, newThreshold int) []keygen.LocalPartySaveData {
+	oldP2PCtx, newP2PCtx := tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs)
+	oldCommittee := make([]*LocalParty, 0, len(oldPIDs))
+	newCommittee := make([]*LocalParty, 0, len(newPIDs))
+	bothCommitteesPax := len(oldPIDs) + len(newPIDs)
+	errCh := make(chan *tss.Error, bothCommitteesPax)
+	outCh := make(chan tss.Message, bothCommitteesPax)
+	endCh := make(chan *keygen.LocalPartySaveData, bothCommitteesPax)
+
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(tss.Edwards(), oldP2PCtx, newP2PCtx, pID, partyCount, threshold, len(newPIDs), newThreshold)
+		oldCommittee = append(oldCommittee, NewLocalParty(params, oldKeys[j], outCh, endCh).(*LocalParty))
+	}
+	for _, pID := range newPIDs {
+		params := tss.NewReSharingParameters(tss.Edwards(), oldP2PCtx, newP2PCtx, pID, partyCount, threshold, len(newPIDs), newThreshold)
+		save := keygen.NewLocalPartySaveData(len(newPIDs))
+		newCommittee = append(newCommittee, NewLocalParty(params, save, outCh, endCh).(*LocalParty))
+	}
+	// the new parties wait for messages, so they are started first
+	for _, P := range append(newCommittee, oldCommittee...) {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	newKeys := make([]keygen.LocalPartySaveData, len(newCommittee))
+	for ended := 0; ended < bothCommitteesPax; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest[:len(oldCommittee)] {
+					go test.SharedPartyUpdater(oldCommittee[destP.Index], msg, errCh)
+				}
+			}
+			if !msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest {
+					go test.SharedPartyUpdater(newCommittee[destP.Index], msg, errCh)
+				}
+			}
+		case save := <-endCh:
+			if save.Xi != nil {
+				index, err := save.OriginalIndex()
+				assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+				newKeys[index] = *save
+			}
+			ended++
+		}
+	}
+	return newKeys
+}
+
+func sign(t *testing.T, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, threshold int, msg *big.Int) *common.SignatureData {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*signing.LocalParty, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	for j, signPID := range signPIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPID, len(signPIDs), threshold)
+		parties = append(parties, signing.NewLocalParty(msg, params, keys[j], outCh, endCh).(*signing.LocalParty))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	for ended := 0; ; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			if dest := msg.GetTo(); dest != nil {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				continue
+			}
+			for _, P := range parties {
+				if P.PartyID().Index != msg.GetFrom().Index {
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			}
+		case data := <-endCh:
+			if ended++; ended == len(signPIDs) {
+				return data
+			}
+		}
+	}
+}
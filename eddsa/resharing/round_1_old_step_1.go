@@ -47,6 +47,12 @@ func (round *round1) Start() *tss.Error {
 	if round.Threshold()+1 > len(ks) {
 		return round.WrapError(fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks)), round.PartyID())
 	}
+	if oldCount := len(round.OldParties().IDs()); round.Threshold()+1 > oldCount {
+		return round.WrapError(fmt.Errorf("t+1=%d is not satisfied by the old committee of %d", round.Threshold()+1, oldCount), round.PartyID())
+	}
+	if newCount := len(round.NewParties().IDs()); round.NewThreshold()+1 > newCount {
+		return round.WrapError(fmt.Errorf("new t+1=%d is not satisfied by the new committee of %d", round.NewThreshold()+1, newCount), round.PartyID())
+	}
 	newKs := round.NewParties().IDs().Keys()
 	wi := signing.PrepareForSigning(round.Params().EC(), i, len(round.OldParties().IDs()), xi, ks)
 
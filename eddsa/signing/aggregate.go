@@ -0,0 +1,134 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/agl/ed25519/edwards25519"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// SignatureShare returns this party's signature share si and the aggregated nonce commitment R, both in their 32-byte
+// little-endian Ed25519 encodings, for a coordinator that aggregates the shares itself with Aggregate. R is the same
+// for all the signers. It is available once the party has started round 3 and must not be called concurrently with
+// Update.
+func (p *LocalParty) SignatureShare() (si, R []byte, err error) {
+	if p.temp.si == nil || p.temp.r == nil {
+		return nil, nil, errors.New("SignatureShare(): the party has not computed its share yet")
+	}
+	si = make([]byte, 32)
+	copy(si, p.temp.si[:])
+	return si, bigIntToEncodedBytes(p.temp.r)[:], nil
+}
+
+// Aggregate sums the signature shares of all the signers, as returned by SignatureShare, into the 64-byte Ed25519
+// signature R || S and checks it against the group public key and the signed message.
+func Aggregate(shares [][]byte, R []byte, pubKey *crypto.ECPoint, msg []byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("Aggregate() received no shares")
+	}
+	if len(R) != 32 {
+		return nil, fmt.Errorf("Aggregate() received an R of %d bytes", len(R))
+	}
+	if pubKey == nil || !pubKey.ValidateBasic() {
+		return nil, errors.New("Aggregate() received an invalid public key")
+	}
+	q := pubKey.Curve().Params().N
+	one := bigIntToEncodedBytes(big.NewInt(1))
+	var sumS [32]byte
+	for j, share := range shares {
+		if len(share) != 32 {
+			return nil, fmt.Errorf("Aggregate() received a share of %d bytes at index %d", len(share), j)
+		}
+		var sj [32]byte
+		copy(sj[:], share)
+		if encodedBytesToBigInt(&sj).Cmp(q) >= 0 {
+			return nil, fmt.Errorf("Aggregate() received a share that is not a canonical scalar at index %d", j)
+		}
+		edwards25519.ScMulAdd(&sumS, &sumS, one, &sj)
+	}
+
+	signature := append(append(make([]byte, 0, 64), R...), sumS[:]...)
+	var encodedR [32]byte
+	copy(encodedR[:], R)
+	pk := edwards.PublicKey{
+		Curve: pubKey.Curve(),
+		X:     pubKey.X(),
+		Y:     pubKey.Y(),
+	}
+	if !edwards.Verify(&pk, msg, encodedBytesToBigInt(&encodedR), encodedBytesToBigInt(&sumS)) {
+		return nil, errors.New("Aggregate(): the aggregated signature does not verify")
+	}
+	return signature, nil
+}
+
+// ComputeAggregateR recomputes the aggregated nonce commitment R of a signing session from the round 2 messages of its
+// signers, without running a party, e.g. for an auditor or a coordinator. decommitments and proofs hold the
+// de-commitment of Rj and the proof of knowledge of rj of every signer of `params`, in index order, as returned by
+// the UnmarshalDeCommitment and UnmarshalZKProof of their SignRound2Messages. ssid is the session ID that the proofs
+// are bound to, as kept in Presignature.SSID.
+//
+// Every proof is verified and R is the sum of the Rj with their cofactor cleared, as round 3 computes it. The
+// de-commitments are not checked against the round 1 commitments; commitments.BatchDeCommit does that. A failure is
+// returned as a *tss.Error of round 3 that names the signers at fault.
+func ComputeAggregateR(params *tss.Parameters, ssid []byte, decommitments []cmt.HashDeCommitment,
+	proofs []*schnorr.ZKProof) (*crypto.ECPoint, error) {
+	if params == nil || params.Parties() == nil {
+		return nil, errors.New("ComputeAggregateR() received parameters without parties")
+	}
+	Ps := params.Parties().IDs()
+	fail := func(err error, culprits ...*tss.PartyID) (*crypto.ECPoint, error) {
+		return nil, tss.NewError(err, TaskName, 3, params.PartyID(), culprits...)
+	}
+	if err := validateSSID(ssid); err != nil {
+		return fail(err)
+	}
+	if len(decommitments) != len(Ps) || len(proofs) != len(Ps) {
+		return fail(fmt.Errorf("expected the de-commitments and proofs of %d signers, got %d and %d",
+			len(Ps), len(decommitments), len(proofs)))
+	}
+	arity, bound := deCommittedRjLen(params), deCommittedRjBound(params)
+	owners := make([]int, len(Ps))
+	values := make([][]*big.Int, len(Ps))
+	var multiErr error
+	culprits := make([]*tss.PartyID, 0, len(Ps))
+	for j, D := range decommitments {
+		if err := cmt.ValidateDeCommitment(D, arity, bound); err != nil {
+			multiErr = multierror.Append(multiErr, err)
+			culprits = append(culprits, Ps[j])
+			continue
+		}
+		owners[j], values[j] = j, D[1:]
+	}
+	if len(culprits) > 0 {
+		return fail(multiErr, culprits...)
+	}
+	Rjs, culprits, err := verifyNonceCommitments(params, ssid, owners, values, proofs)
+	if err != nil {
+		return fail(err, culprits...)
+	}
+	R, j, err := sumNonceCommitments(params.EC(), Rjs)
+	if err != nil {
+		if j < 0 {
+			return fail(err)
+		}
+		return fail(err, Ps[j])
+	}
+	if R.IsInfinity() {
+		return fail(errors.New("the nonce point R is the identity"))
+	}
+	return R, nil
+}
@@ -0,0 +1,174 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestE2EExternalAggregation(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	digest := sha256.Sum256([]byte("aggregated outside of the parties"))
+	msg := digest[:]
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(new(big.Int).SetBytes(msg), params, keys[i], outCh, endCh, len(msg)))
+		_, _, err := parties[i].(*LocalParty).SignatureShare()
+		assert.Error(t, err, "there is no share before round 3")
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	// run rounds 1-3, keeping the round 3 messages from the parties so that they never finalize
+	done := make(chan struct{})
+	round3Msgs := 0
+	dropRound3 := func(msg tss.Message) tss.Message {
+		if _, ok := msg.(tss.ParsedMessage).Content().(*SignRound3Message); ok {
+			if round3Msgs++; round3Msgs == len(parties) {
+				close(done)
+			}
+			return nil
+		}
+		return msg
+	}
+	if err := routeMessages(parties, outCh, errCh, done, dropRound3); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	assert.Empty(t, endCh, "the parties must not finalize")
+
+	shares := make([][]byte, len(parties))
+	var R []byte
+	for i, P := range parties {
+		si, Ri, err := P.(*LocalParty).SignatureShare()
+		assert.NoError(t, err)
+		assert.Len(t, si, 32)
+		if R != nil {
+			assert.Equal(t, R, Ri, "all the parties must agree on R")
+		}
+		shares[i], R = si, Ri
+	}
+
+	signature, err := Aggregate(shares, R, keys[0].EDDSAPub, msg)
+	assert.NoError(t, err)
+	pubKey := ecPointToEncodedBytes(keys[0].EDDSAPub.X(), keys[0].EDDSAPub.Y())
+	assert.True(t, ed25519.Verify(pubKey[:], msg, signature), "ed25519 verify must pass")
+
+	// a missing or altered share is detected
+	_, err = Aggregate(shares[1:], R, keys[0].EDDSAPub, msg)
+	assert.Error(t, err)
+	altered := append([][]byte(nil), shares...)
+	altered[0] = append([]byte{altered[0][0] ^ 1}, altered[0][1:]...)
+	_, err = Aggregate(altered, R, keys[0].EDDSAPub, msg)
+	assert.Error(t, err)
+	nonCanonical := append([][]byte(nil), shares...)
+	nonCanonical[0] = make([]byte, 32)
+	for i := range nonCanonical[0] {
+		nonCanonical[0][i] = 0xff
+	}
+	_, err = Aggregate(nonCanonical, R, keys[0].EDDSAPub, msg)
+	assert.Error(t, err, "a share that is not reduced must be rejected")
+}
+
+func TestComputeAggregateRMatchesRound3(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		for range signPIDs {
+			<-endCh
+		}
+		close(done)
+	}()
+	// record the round 2 messages as they go over the wire
+	r2msgs := make([]*SignRound2Message, len(signPIDs))
+	record := func(msg tss.Message) tss.Message {
+		if content, ok := msg.(tss.ParsedMessage).Content().(*SignRound2Message); ok {
+			r2msgs[msg.GetFrom().Index] = content
+		}
+		return msg
+	}
+	if err := routeMessages(parties, outCh, errCh, done, record); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	P := parties[0].(*LocalParty)
+	fieldP := P.params.EC().Params().P
+	decommitments := make([]cmt.HashDeCommitment, len(signPIDs))
+	proofs := make([]*schnorr.ZKProof, len(signPIDs))
+	for j, r2msg := range r2msgs {
+		decommitments[j], err = r2msg.UnmarshalDeCommitment(2, fieldP)
+		assert.NoError(t, err)
+		proofs[j], err = r2msg.UnmarshalZKProof(tss.Edwards())
+		assert.NoError(t, err)
+	}
+	R, err := ComputeAggregateR(P.params, P.temp.ssid, decommitments, proofs)
+	if assert.NoError(t, err) {
+		_, encodedR, _ := P.SignatureShare()
+		assert.Equal(t, encodedR, ecPointToEncodedBytes(R.X(), R.Y())[:], "R must be the one that round 3 computed")
+	}
+
+	// a proof of another party's nonce is attributed to the signer that sent it
+	swapped := append([]*schnorr.ZKProof{}, proofs...)
+	swapped[1], swapped[2] = proofs[2], proofs[1]
+	_, err = ComputeAggregateR(P.params, P.temp.ssid, decommitments, swapped)
+	var tssErr *tss.Error
+	if assert.True(t, errors.As(err, &tssErr)) {
+		assert.Equal(t, []*tss.PartyID{signPIDs[1], signPIDs[2]}, tssErr.Culprits())
+		assert.Equal(t, 3, tssErr.Round())
+	}
+	// so is a malformed de-commitment, and the proofs are bound to the session
+	malformed := append([]cmt.HashDeCommitment{}, decommitments...)
+	malformed[0] = malformed[0][:2]
+	_, err = ComputeAggregateR(P.params, P.temp.ssid, malformed, proofs)
+	if assert.True(t, errors.As(err, &tssErr)) {
+		assert.Equal(t, []*tss.PartyID{signPIDs[0]}, tssErr.Culprits())
+	}
+	_, err = ComputeAggregateR(P.params, []byte("another session"), decommitments, proofs)
+	assert.Error(t, err)
+	_, err = ComputeAggregateR(P.params, P.temp.ssid, decommitments[1:], proofs[1:])
+	assert.Error(t, err)
+}
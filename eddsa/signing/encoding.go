@@ -0,0 +1,61 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+const (
+	rfc8032SignatureLen = 64
+)
+
+// EncodeSignatureRFC8032 returns the 64-byte RFC 8032 encoding R || S of a signature given as the R and S of its
+// SignatureData, i.e. the integers whose big-endian bytes are the reversed little-endian encodings. R is re-encoded
+// from the point it decodes to, so only its canonical compressed form is output, and S is reduced mod q. It returns
+// nil if r does not encode a point of the curve.
+func EncodeSignatureRFC8032(r, s *big.Int) []byte {
+	if r == nil || s == nil || r.Sign() < 0 || r.BitLen() > 256 {
+		return nil
+	}
+	point, err := edwards.ParsePubKey(bigIntToEncodedBytes(r)[:])
+	if err != nil {
+		return nil
+	}
+	sReduced := new(big.Int).Mod(s, edwards.Edwards().Params().N)
+	signature := make([]byte, 0, rfc8032SignatureLen)
+	signature = append(signature, ecPointToEncodedBytes(point.X, point.Y)[:]...)
+	return append(signature, bigIntToEncodedBytes(sReduced)[:]...)
+}
+
+// DecodeSignatureRFC8032 reverses EncodeSignatureRFC8032. It rejects encodings that a strict RFC 8032 verifier
+// rejects too: an R that is not the canonical compressed encoding of a point, and an S that is not below q.
+func DecodeSignatureRFC8032(signature []byte) (r, s *big.Int, err error) {
+	if len(signature) != rfc8032SignatureLen {
+		return nil, nil, fmt.Errorf("DecodeSignatureRFC8032() received a signature of %d bytes", len(signature))
+	}
+	var encodedR, encodedS [32]byte
+	copy(encodedR[:], signature[:32])
+	copy(encodedS[:], signature[32:])
+	point, err := edwards.ParsePubKey(encodedR[:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("DecodeSignatureRFC8032(): invalid R: %v", err)
+	}
+	if !bytes.Equal(ecPointToEncodedBytes(point.X, point.Y)[:], encodedR[:]) {
+		return nil, nil, errors.New("DecodeSignatureRFC8032(): R is not a canonical point encoding")
+	}
+	s = encodedBytesToBigInt(&encodedS)
+	if s.Cmp(edwards.Edwards().Params().N) >= 0 {
+		return nil, nil, errors.New("DecodeSignatureRFC8032(): S is not reduced mod q")
+	}
+	return encodedBytesToBigInt(&encodedR), s, nil
+}
@@ -0,0 +1,91 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ed25519"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestE2ESignatureRFC8032(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	msg := []byte("the quick brown fox jumps over the lazy dog")
+	_, data, tssErr := signMessage(keys, signPIDs, new(big.Int).SetBytes(msg), nil, len(msg))
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+
+	signature := EncodeSignatureRFC8032(new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S))
+	assert.Len(t, signature, 64)
+	assert.Equal(t, data.Signature, signature)
+	pk := edwards.PublicKey{Curve: tss.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+	assert.True(t, ed25519.Verify(pk.Serialize(), msg, signature), "the standard library must accept the signature")
+
+	r, s, err := DecodeSignatureRFC8032(signature)
+	assert.NoError(t, err)
+	assert.Equal(t, data.R, r.Bytes())
+	assert.Equal(t, data.S, s.Bytes())
+
+	// S is reduced when encoding
+	sPlusQ := new(big.Int).Add(s, tss.Edwards().Params().N)
+	assert.Equal(t, signature, EncodeSignatureRFC8032(r, sPlusQ))
+}
+
+func TestE2EPoseidonOptionsKeepRFC8032(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	digest, err := PoseidonPrehash([]byte("the quick brown fox jumps over the lazy dog"))
+	assert.NoError(t, err)
+	_, data, tssErr := signMessage(keys, signPIDs, digest, func(params *tss.Parameters) {
+		params.SetPoseidonCommitments()
+		params.SetPrehashed()
+	})
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	pk := edwards.PublicKey{Curve: tss.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+	assert.True(t, ed25519.Verify(pk.Serialize(), data.M, data.Signature), "the challenge must still be SHA-512")
+}
+
+func TestDecodeSignatureRFC8032Rejects(t *testing.T) {
+	q := tss.Edwards().Params().N
+	p := tss.Edwards().Params().P
+	one := big.NewInt(1)
+	// the identity (0, 1) in its canonical encoding, and with y + p and the sign bit of x set
+	identity := append(bigIntToEncodedBytes(one)[:], bigIntToEncodedBytes(one)[:]...)
+	_, _, err := DecodeSignatureRFC8032(identity)
+	assert.NoError(t, err)
+
+	yPlusP := append(bigIntToEncodedBytes(new(big.Int).Add(p, one))[:], bigIntToEncodedBytes(one)[:]...)
+	_, _, err = DecodeSignatureRFC8032(yPlusP)
+	assert.Error(t, err, "y must be below p")
+	negativeZero := append([]byte{}, identity...)
+	negativeZero[31] |= 0x80
+	_, _, err = DecodeSignatureRFC8032(negativeZero)
+	assert.Error(t, err, "x = 0 must not have its sign bit set")
+
+	unreduced := append(bigIntToEncodedBytes(one)[:], bigIntToEncodedBytes(q)[:]...)
+	_, _, err = DecodeSignatureRFC8032(unreduced)
+	assert.Error(t, err, "S must be below q")
+	_, _, err = DecodeSignatureRFC8032(identity[:63])
+	assert.Error(t, err)
+
+	assert.Nil(t, EncodeSignatureRFC8032(nil, one))
+	assert.Nil(t, EncodeSignatureRFC8032(big.NewInt(-1), one))
+}
@@ -12,8 +12,10 @@ import (
 	"math/big"
 
 	"github.com/agl/ed25519/edwards25519"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/hashicorp/go-multierror"
 )
 
 func (round *finalization) Start() *tss.Error {
@@ -24,31 +26,43 @@ func (round *finalization) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
+	if err := round.verifySignatureShares(); err != nil {
+		return err
+	}
+
+	Ps := round.Parties().IDs()
+	q := round.Params().EC().Params().N
 	sumS := round.temp.si
-	for j := range round.Parties().IDs() {
+	var multiErr error
+	culprits := make([]*tss.PartyID, 0, len(Ps))
+	for j, Pj := range Ps {
 		round.ok[j] = true
 		if j == round.PartyID().Index {
 			continue
 		}
 		r3msg := round.temp.signRound3Messages[j].Content().(*SignRound3Message)
-		sjBytes := bigIntToEncodedBytes(r3msg.UnmarshalS())
+		// checked here as well, since verifySignatureShares is skipped without the Rj
+		sjBytes, err := crypto.ScalarToLE32(r3msg.UnmarshalS(), q)
+		if err != nil {
+			multiErr = multierror.Append(multiErr, fmt.Errorf("the signature share of party %d is malformed: %v", j, err))
+			culprits = append(culprits, Pj)
+			continue
+		}
 		var tmpSumS [32]byte
 		edwards25519.ScMulAdd(&tmpSumS, sumS, bigIntToEncodedBytes(big.NewInt(1)), sjBytes)
 		sumS = &tmpSumS
 	}
+	if len(culprits) > 0 {
+		return round.WrapError(multiErr, culprits...).WithMessageType(&SignRound3Message{})
+	}
 	s := encodedBytesToBigInt(sumS)
+	round.temp.transcript.recordS(s)
 
 	// save the signature for final output
 	round.data.Signature = append(bigIntToEncodedBytes(round.temp.r)[:], sumS[:]...)
 	round.data.R = round.temp.r.Bytes()
 	round.data.S = s.Bytes()
-	if round.temp.fullBytesLen == 0 {
-		round.data.M = round.temp.m.Bytes()
-	} else {
-		var mBytes = make([]byte, round.temp.fullBytesLen)
-		round.temp.m.FillBytes(mBytes)
-		round.data.M = mBytes
-	}
+	round.data.M = round.messageBytes()
 
 	pk := edwards.PublicKey{
 		Curve: round.Params().EC(),
@@ -65,6 +79,48 @@ func (round *finalization) Start() *tss.Error {
 	return nil
 }
 
+// verifySignatureShares checks the share sj of every other signer against its nonce commitment Rj and its public key
+// share Wj = coef_j * BigXj, where coef_j is the Lagrange coefficient of PrepareForSigning: sj*G must equal
+// Rj + lambda*Wj. The senders of the shares that do not are returned as the culprits, rather than only failing the
+// verification of the aggregated signature. It is skipped when the Rj are not known, i.e. when signing from a
+// Presignature whose Rjs are missing or not one per signer, which SignPresig accepts, or from a saved state of such a
+// party; Start still checks that every sj is below q, and a bad share then only fails the aggregated signature.
+func (round *finalization) verifySignatureShares() *tss.Error {
+	bigRjs := round.temp.bigRjs
+	Ps := round.Parties().IDs()
+	if len(bigRjs) != len(Ps) {
+		return nil
+	}
+	ec := round.Params().EC()
+	q := ec.Params().N
+	ks := round.key.Ks
+	lambda := challenge(bigIntToEncodedBytes(round.temp.r), round.key.EDDSAPub, round.messageBytes())
+
+	var multiErr error
+	culprits := make([]*tss.PartyID, 0, len(Ps))
+	for j, Pj := range Ps {
+		if j == round.PartyID().Index {
+			continue
+		}
+		sj := round.temp.signRound3Messages[j].Content().(*SignRound3Message).UnmarshalS()
+		if sj.Sign() < 0 || sj.Cmp(q) >= 0 || bigRjs[j] == nil {
+			multiErr = multierror.Append(multiErr, fmt.Errorf("the signature share of party %d is malformed", j))
+			culprits = append(culprits, Pj)
+			continue
+		}
+		coef := PrepareForSigning(ec, j, len(ks), big.NewInt(1), ks)
+		expected, err := bigRjs[j].Add(round.key.BigXj[j].ScalarMult(coef).ScalarMult(lambda))
+		if err != nil || !crypto.ScalarBaseMult(ec, sj).Equal(expected) {
+			multiErr = multierror.Append(multiErr, fmt.Errorf("the signature share of party %d does not verify", j))
+			culprits = append(culprits, Pj)
+		}
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(multiErr, culprits...).WithMessageType(&SignRound3Message{})
+	}
+	return nil
+}
+
 func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
 	// not expecting any incoming messages in this round
 	return false
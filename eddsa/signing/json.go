@@ -0,0 +1,105 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// The JSON encodings below are meant for debugging and for tooling outside of Go. Every field of the Protocol Buffers
+// content is written under its proto field name as a lowercase hex string, so that the output is stable and survives
+// a round trip back to the same Protocol Buffers message.
+
+type signRound2MessageJSON struct {
+	DeCommitment []string `json:"de_commitment"`
+	ProofAlphaX  string   `json:"proof_alpha_x"`
+	ProofAlphaY  string   `json:"proof_alpha_y"`
+	ProofT       string   `json:"proof_t"`
+}
+
+type signRound3MessageJSON struct {
+	S           string `json:"s"`
+	MessageHash string `json:"message_hash"`
+}
+
+func (m *SignRound2Message) MarshalJSON() ([]byte, error) {
+	aux := signRound2MessageJSON{
+		DeCommitment: make([]string, len(m.GetDeCommitment())),
+		ProofAlphaX:  hex.EncodeToString(m.GetProofAlphaX()),
+		ProofAlphaY:  hex.EncodeToString(m.GetProofAlphaY()),
+		ProofT:       hex.EncodeToString(m.GetProofT()),
+	}
+	for i, bz := range m.GetDeCommitment() {
+		aux.DeCommitment[i] = hex.EncodeToString(bz)
+	}
+	return json.Marshal(&aux)
+}
+
+func (m *SignRound2Message) UnmarshalJSON(bz []byte) error {
+	aux := new(signRound2MessageJSON)
+	if err := decodeStrictJSON(bz, aux); err != nil {
+		return fmt.Errorf("SignRound2Message.UnmarshalJSON(): %v", err)
+	}
+	var deCommitment [][]byte
+	if aux.DeCommitment != nil {
+		deCommitment = make([][]byte, len(aux.DeCommitment))
+	}
+	for i, s := range aux.DeCommitment {
+		var err error
+		if deCommitment[i], err = hex.DecodeString(s); err != nil {
+			return fmt.Errorf("SignRound2Message.UnmarshalJSON(): de_commitment[%d]: %v", i, err)
+		}
+	}
+	proofAlphaX, err := hex.DecodeString(aux.ProofAlphaX)
+	if err != nil {
+		return fmt.Errorf("SignRound2Message.UnmarshalJSON(): proof_alpha_x: %v", err)
+	}
+	proofAlphaY, err := hex.DecodeString(aux.ProofAlphaY)
+	if err != nil {
+		return fmt.Errorf("SignRound2Message.UnmarshalJSON(): proof_alpha_y: %v", err)
+	}
+	proofT, err := hex.DecodeString(aux.ProofT)
+	if err != nil {
+		return fmt.Errorf("SignRound2Message.UnmarshalJSON(): proof_t: %v", err)
+	}
+	m.DeCommitment, m.ProofAlphaX, m.ProofAlphaY, m.ProofT = deCommitment, proofAlphaX, proofAlphaY, proofT
+	return nil
+}
+
+func (m *SignRound3Message) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&signRound3MessageJSON{
+		S:           hex.EncodeToString(m.GetS()),
+		MessageHash: hex.EncodeToString(m.GetMessageHash()),
+	})
+}
+
+func (m *SignRound3Message) UnmarshalJSON(bz []byte) error {
+	aux := new(signRound3MessageJSON)
+	if err := decodeStrictJSON(bz, aux); err != nil {
+		return fmt.Errorf("SignRound3Message.UnmarshalJSON(): %v", err)
+	}
+	s, err := hex.DecodeString(aux.S)
+	if err != nil {
+		return fmt.Errorf("SignRound3Message.UnmarshalJSON(): s: %v", err)
+	}
+	messageHash, err := hex.DecodeString(aux.MessageHash)
+	if err != nil {
+		return fmt.Errorf("SignRound3Message.UnmarshalJSON(): message_hash: %v", err)
+	}
+	m.S, m.MessageHash = s, messageHash
+	return nil
+}
+
+// decodeStrictJSON decodes `bz` into `v`, rejecting fields that `v` does not have
+func decodeStrictJSON(bz []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
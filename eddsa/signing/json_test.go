@@ -0,0 +1,100 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// protoJSONProto encodes `content` with Protocol Buffers, decodes it, goes through JSON into `out` and returns the
+// Protocol Buffers encodings before and after
+func protoJSONProto(t *testing.T, content, out proto.Message) ([]byte, []byte) {
+	wire, err := proto.Marshal(content)
+	assert.NoError(t, err)
+	decoded := content.ProtoReflect().New().Interface()
+	assert.NoError(t, proto.Unmarshal(wire, decoded))
+	bz, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(bz, out))
+	again, err := proto.Marshal(out)
+	assert.NoError(t, err)
+	return wire, again
+}
+
+func TestSignRound2MessageJSONRoundTrip(t *testing.T) {
+	ec := tss.Edwards()
+	pID := tss.GenerateTestPartyIDs(1)[0]
+	ssid := []byte("session")
+	ri := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+	Ri := crypto.ScalarBaseMult(ec, ri)
+	commitment := cmt.NewHashCommitmentWithContext(rand.Reader, ssid, Ri.X(), Ri.Y())
+	proof, err := schnorr.NewZKProof(ssid, ri, Ri, rand.Reader)
+	assert.NoError(t, err)
+	content := NewSignRound2Message(pID, commitment.D, proof).Content().(*SignRound2Message)
+
+	out := new(SignRound2Message)
+	wire, again := protoJSONProto(t, content, out)
+	assert.Equal(t, wire, again)
+	assert.True(t, proto.Equal(content, out))
+	assert.True(t, out.ValidateBasic())
+
+	outD, err := out.UnmarshalDeCommitment(2, ec.Params().P)
+	assert.NoError(t, err)
+	assert.Equal(t, commitment.D, outD)
+	ok, D := (&cmt.HashCommitDecommit{C: commitment.C, D: outD, Ctx: ssid}).DeCommit()
+	assert.True(t, ok)
+	assert.Equal(t, []*big.Int{Ri.X(), Ri.Y()}, D)
+	outProof, err := out.UnmarshalZKProof(ec)
+	assert.NoError(t, err)
+	assert.True(t, outProof.Verify(ssid, Ri))
+}
+
+func TestSignRound3MessageJSONRoundTrip(t *testing.T) {
+	pID := tss.GenerateTestPartyIDs(1)[0]
+	si := common.GetRandomPositiveInt(rand.Reader, tss.Edwards().Params().N)
+	mHash := common.SHA512_256([]byte("message"))
+	content := NewSignRound3Message(pID, si, mHash).Content().(*SignRound3Message)
+
+	out := new(SignRound3Message)
+	wire, again := protoJSONProto(t, content, out)
+	assert.Equal(t, wire, again)
+	assert.Equal(t, si, out.UnmarshalS())
+	assert.Equal(t, mHash, out.GetMessageHash())
+}
+
+func TestSigningMessagesJSONEncoding(t *testing.T) {
+	m2 := &SignRound2Message{
+		DeCommitment: [][]byte{{0x01, 0xab}, {0x00, 0x02}},
+		ProofAlphaX:  []byte{0x0c},
+		ProofAlphaY:  []byte{0xd0},
+		ProofT:       []byte{0xff, 0xee},
+	}
+	bz, err := json.Marshal(m2)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"de_commitment":["01ab","0002"],"proof_alpha_x":"0c","proof_alpha_y":"d0","proof_t":"ffee"}`, string(bz))
+	bz, err = json.Marshal(&SignRound3Message{S: []byte{0x00, 0x2a}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"s":"002a","message_hash":""}`, string(bz))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"s":"2a","r":"01"}`), new(SignRound3Message)), "unknown fields are rejected")
+	assert.Error(t, json.Unmarshal([]byte(`{"s":"0x2a"}`), new(SignRound3Message)))
+	assert.Error(t, json.Unmarshal([]byte(`{"s":"abc"}`), new(SignRound3Message)))
+	assert.Error(t, json.Unmarshal([]byte(`{"de_commitment":["01","zz"]}`), new(SignRound2Message)))
+	assert.Error(t, json.Unmarshal([]byte(`{"proof_t":42}`), new(SignRound2Message)))
+}
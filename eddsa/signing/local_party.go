@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"math/big"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
@@ -22,6 +24,7 @@ import (
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.MessageCounter = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -33,8 +36,12 @@ type (
 		data *common.SignatureData
 
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- *common.SignatureData
+		out       chan<- tss.Message
+		end       chan<- *common.SignatureData
+		presigEnd chan<- *Presignature
+
+		// restored with NewLocalPartyFromState
+		resumed bool
 	}
 
 	localMessageStore struct {
@@ -59,13 +66,25 @@ type (
 		si  *[32]byte
 
 		// round 3
-		r *big.Int
+		r      *big.Int
+		bigRjs []*crypto.ECPoint // the nonce commitment of every signer, in index order
+
+		// presignature: the encoded R of a presignature that replaces rounds 1-2
+		encodedR *[32]byte
 
 		ssid      []byte
 		ssidNonce *big.Int
+
+		// set by RecordTranscript
+		transcript *transcriptRecorder
 	}
 )
 
+// NewLocalParty returns a party that signs msg with the key share in `key`. The party does not modify `key`, so the
+// same save data may be passed to parties of concurrent signing sessions. A message given as bytes can be reduced to a
+// digest with PoseidonPrehash, which is then signed with Parameters.SetPrehashed. Poseidon only ever computes the
+// commitments and the pre-hash: the challenge is always the SHA-512 of RFC 8032, so the signatures verify with
+// crypto/ed25519 with every option of the parameters.
 func NewLocalParty(
 	msg *big.Int,
 	params *tss.Parameters,
@@ -101,24 +120,49 @@ func NewLocalParty(
 }
 
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end)
+	round := newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end, p.presigEnd)
+	if p.resumed {
+		return p.restoredRound(round.(*round1))
+	}
+	if p.temp.encodedR != nil {
+		// signing from a presignature resumes at round 3
+		return &round3{&round2{round.(*round1)}}
+	}
+	return round
 }
 
 func (p *LocalParty) Start() *tss.Error {
-	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
-		round1, ok := round.(*round1)
-		if !ok {
+	err := tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
+		var r1 *round1
+		switch rnd := round.(type) {
+		case *round1:
+			r1 = rnd
+		case *round3:
+			r1 = rnd.round1
+		case *resumedRound:
+			r1 = &round1{rnd.base}
+		default:
 			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
 		}
-		if err := round1.prepare(); err != nil {
+		if err := r1.Params().ValidateParties(); err != nil {
+			return round.WrapError(err)
+		}
+		if err := r1.checkMessage(); err != nil {
+			return round.WrapError(err)
+		}
+		if err := r1.prepare(); err != nil {
 			return round.WrapError(err)
 		}
 		return nil
 	})
+	p.temp.transcript.fail(err)
+	return err
 }
 
 func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
-	return tss.BaseUpdate(p, msg, TaskName)
+	ok, err = tss.BaseUpdate(p, msg, TaskName)
+	p.temp.transcript.fail(err)
+	return ok, err
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
@@ -158,15 +202,71 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 		p.temp.signRound2Messages[fromPIdx] = msg
 
 	case *SignRound3Message:
+		// a party that sends two different signature shares is equivocating; an identical resend is accepted as is
+		if prev := p.temp.signRound3Messages[fromPIdx]; prev != nil {
+			if !proto.Equal(prev.Content(), msg.Content()) {
+				return false, p.WrapError(errors.New("received a second, different SignRound3Message from the same party"),
+					msg.GetFrom()).WithMessageType(msg.Content())
+			}
+			return true, nil
+		}
 		p.temp.signRound3Messages[fromPIdx] = msg
 
 	default: // unrecognised message, just ignore!
 		common.Logger.Warningf("unrecognised message ignored: %v", msg)
 		return false, nil
 	}
+	p.temp.transcript.recordMessage(msg)
 	return true, nil
 }
 
+// Missing returns the indices of the parties whose message of the current round the party still waits for, in
+// increasing order, so that a coordinator can have them sent again with Retransmit. It is empty once the party has
+// ended or before it has started.
+func (p *LocalParty) Missing() []int {
+	waiting := p.WaitingFor()
+	missing := make([]int, 0, len(waiting))
+	for _, pID := range waiting {
+		missing = append(missing, pID.Index)
+	}
+	return missing
+}
+
+// Retransmit returns the message that the party broadcast in the round, 1 to 3, to be delivered again to the parties
+// that lost it. The parties accept an identical message a second time. It fails if the party has not sent a message
+// in that round yet, and it must not be called concurrently with Update.
+func (p *LocalParty) Retransmit(round int) (tss.ParsedMessage, error) {
+	var msgs []tss.ParsedMessage
+	switch round {
+	case 1:
+		msgs = p.temp.signRound1Messages
+	case 2:
+		msgs = p.temp.signRound2Messages
+	case 3:
+		msgs = p.temp.signRound3Messages
+	default:
+		return nil, fmt.Errorf("Retransmit(): the signing protocol has no round %d", round)
+	}
+	msg := msgs[p.PartyID().Index]
+	if msg == nil {
+		return nil, fmt.Errorf("Retransmit(): the party has not sent its message of round %d", round)
+	}
+	return msg, nil
+}
+
+// ExpectedMessages implements tss.MessageCounter: rounds 1 through 3 each wait for a broadcast from every other
+// signer, except for round 3 of presigning, which completes without messages
+func (p *LocalParty) ExpectedMessages(round int) (broadcast, p2p int) {
+	others := len(p.params.Parties().IDs()) - 1
+	switch {
+	case round == 1, round == 2:
+		return others, 0
+	case round == 3 && p.presigEnd == nil:
+		return others, 0
+	}
+	return 0, 0
+}
+
 func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
@@ -7,11 +7,18 @@
 package signing
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	mrand "math/rand"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/agl/ed25519/edwards25519"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
@@ -19,6 +26,10 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/poseidon"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
 	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/test"
 	"github.com/bnb-chain/tss-lib/v2/tss"
@@ -248,3 +259,1369 @@ signing:
 		}
 	}
 }
+
+func TestE2EConcurrentEmptyMessage(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Equal(t, testThreshold+1, len(keys))
+	assert.Equal(t, testThreshold+1, len(signPIDs))
+
+	// PHASE: signing
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	msg := []byte{}
+	// init the parties
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		P := NewLocalParty(new(big.Int).SetBytes(msg), params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case <-endCh:
+			atomic.AddInt32(&ended, 1)
+			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+				t.Logf("Done. Received signature data from %d participants", ended)
+
+				// BEGIN EDDSA verify
+				pkX, pkY := keys[0].EDDSAPub.X(), keys[0].EDDSAPub.Y()
+				pk := edwards.PublicKey{
+					Curve: tss.Edwards(),
+					X:     pkX,
+					Y:     pkY,
+				}
+
+				newSig, err := edwards.ParseSignature(parties[0].data.Signature)
+				assert.NoError(t, err)
+				assert.Empty(t, parties[0].data.M)
+
+				ok := edwards.Verify(&pk, msg, newSig.R, newSig.S)
+				assert.True(t, ok, "eddsa verify must pass for an empty message")
+				t.Log("EDDSA signing test done.")
+				// END EDDSA verify
+
+				break signing
+			}
+		}
+	}
+}
+
+func TestE2ERound3Culprits(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	culprit := signPIDs[0]
+
+	r2Content := func(msg tss.Message) (cmt.HashDeCommitment, *schnorr.ZKProof) {
+		r2msg := msg.(tss.ParsedMessage).Content().(*SignRound2Message)
+		proof, err := r2msg.UnmarshalZKProof(tss.Edwards())
+		assert.NoError(t, err)
+		deCommit, err := r2msg.UnmarshalDeCommitment(2, tss.Edwards().Params().P)
+		assert.NoError(t, err)
+		return deCommit, proof
+	}
+	// the session id of the parties, which the commitments are bound to
+	var ssid []byte
+	// recommit makes the culprit commit to `values` in round 1 and open that commitment in round 2
+	recommit := func(values ...*big.Int) func(tss.Message) tss.Message {
+		var deCommit cmt.HashDeCommitment
+		return func(msg tss.Message) tss.Message {
+			switch msg.Type() {
+			case "binance.tsslib.eddsa.signing.SignRound1Message":
+				commitment := cmt.NewHashCommitmentWithContext(rand.Reader, ssid, values...)
+				deCommit = commitment.D
+				return NewSignRound1Message(culprit, commitment.C)
+			case "binance.tsslib.eddsa.signing.SignRound2Message":
+				_, proof := r2Content(msg)
+				return NewSignRound2Message(culprit, deCommit, proof)
+			}
+			return msg
+		}
+	}
+	tests := []struct {
+		name   string
+		round  int
+		cause  error // matched with errors.Is when set
+		tamper func(tss.Message) tss.Message
+	}{{
+		name:  "de-commitment",
+		round: 3,
+		cause: cmt.ErrDeCommitVerify,
+		tamper: func(msg tss.Message) tss.Message {
+			if msg.Type() != "binance.tsslib.eddsa.signing.SignRound2Message" {
+				return msg
+			}
+			deCommit, proof := r2Content(msg)
+			deCommit[1] = new(big.Int).Add(deCommit[1], big.NewInt(1))
+			return NewSignRound2Message(culprit, deCommit, proof)
+		},
+	}, {
+		// caught by SignRound2Message.ValidateBasic before round 3 starts
+		name:   "coordinate count",
+		round:  2,
+		tamper: recommit(big.NewInt(1), big.NewInt(2), big.NewInt(3)),
+	}, {
+		// a single secret passes ValidateBasic, which also allows a compressed Rj
+		name:   "de-commitment arity",
+		round:  3,
+		cause:  cmt.ErrDeCommitArity,
+		tamper: recommit(big.NewInt(1)),
+	}, {
+		name:   "off-curve Rj",
+		round:  3,
+		tamper: recommit(big.NewInt(1), big.NewInt(2)),
+	}, {
+		name:  "Schnorr proof",
+		round: 3,
+		tamper: func(msg tss.Message) tss.Message {
+			if msg.Type() != "binance.tsslib.eddsa.signing.SignRound2Message" {
+				return msg
+			}
+			deCommit, proof := r2Content(msg)
+			proof.T = new(big.Int).Add(proof.T, big.NewInt(1))
+			return NewSignRound2Message(culprit, deCommit, proof)
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p2pCtx := tss.NewPeerContext(signPIDs)
+			parties := make([]tss.Party, 0, len(signPIDs))
+			errCh := make(chan *tss.Error, len(signPIDs))
+			outCh := make(chan tss.Message, len(signPIDs))
+			endCh := make(chan *common.SignatureData, len(signPIDs))
+			for i := 0; i < len(signPIDs); i++ {
+				params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+				parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+			}
+			for _, P := range parties {
+				if err := P.Start(); err != nil {
+					assert.FailNow(t, err.Error())
+				}
+			}
+			ssid = parties[0].(*LocalParty).temp.ssid
+			tamper := func(msg tss.Message) tss.Message {
+				if msg.GetFrom().Index != culprit.Index {
+					return msg
+				}
+				return tt.tamper(msg)
+			}
+			err := routeMessages(parties, outCh, errCh, nil, tamper)
+			if !assert.NotNil(t, err, "signing must abort") {
+				return
+			}
+			assert.Equal(t, []*tss.PartyID{culprit}, err.Culprits(), err.Error())
+			assert.Equal(t, tt.round, err.Round(), "the error must carry the round it occurred in")
+			assert.Equal(t, "binance.tsslib.eddsa.signing.SignRound2Message", err.MessageType())
+			if tt.cause != nil {
+				assert.True(t, errors.Is(err, tt.cause), err.Error())
+			}
+		})
+	}
+}
+
+func TestE2ESignatureShareCulprit(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	culprit := signPIDs[1]
+	q := tss.Edwards().Params().N
+
+	tests := []struct {
+		name string
+		si   func(si *big.Int) *big.Int
+	}{{
+		name: "off by one",
+		si:   func(si *big.Int) *big.Int { return new(big.Int).Add(si, big.NewInt(1)) },
+	}, {
+		name: "unreduced",
+		si:   func(si *big.Int) *big.Int { return new(big.Int).Add(si, q) },
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p2pCtx := tss.NewPeerContext(signPIDs)
+			parties := make([]tss.Party, 0, len(signPIDs))
+			errCh := make(chan *tss.Error, len(signPIDs))
+			outCh := make(chan tss.Message, len(signPIDs))
+			endCh := make(chan *common.SignatureData, len(signPIDs))
+			for i := 0; i < len(signPIDs); i++ {
+				params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+				parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+			}
+			for _, P := range parties {
+				if err := P.Start(); err != nil {
+					assert.FailNow(t, err.Error())
+				}
+			}
+			tamper := func(msg tss.Message) tss.Message {
+				if msg.GetFrom().Index != culprit.Index || msg.Type() != "binance.tsslib.eddsa.signing.SignRound3Message" {
+					return msg
+				}
+				r3msg := msg.(tss.ParsedMessage).Content().(*SignRound3Message)
+				return NewSignRound3Message(culprit, tt.si(r3msg.UnmarshalS()), r3msg.GetMessageHash())
+			}
+			err := routeMessages(parties, outCh, errCh, nil, tamper)
+			if !assert.NotNil(t, err, "signing must abort") {
+				return
+			}
+			assert.Contains(t, err.Error(), "signature share")
+			assert.Equal(t, []*tss.PartyID{culprit}, err.Culprits(), err.Error())
+			assert.Equal(t, 4, err.Round())
+			assert.Equal(t, "binance.tsslib.eddsa.signing.SignRound3Message", err.MessageType())
+		})
+	}
+}
+
+func TestE2EResumeFromState(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// a stand-in for encryption
+	xor := func(bz []byte) ([]byte, error) {
+		out := make([]byte, len(bz))
+		for i := range bz {
+			out[i] = bz[i] ^ 0x5a
+		}
+		return out, nil
+	}
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	outCh := make(chan tss.Message, 4*len(signPIDs)*len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs)+1)
+	msg := big.NewInt(42)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	// route the messages synchronously; once party 0 has started round 2, save its state and restore it into a
+	// second party that receives the same messages as the original from then on
+	var restored tss.Party
+	errCh := make(chan *tss.Error, 4*len(signPIDs))
+	for len(endCh) < len(signPIDs)+1 {
+		out := <-outCh
+		if _, ok := out.(tss.ParsedMessage).Content().(*SignRound2Message); ok && out.GetFrom().Index == 0 && restored == nil {
+			state, err := parties[0].(*LocalParty).MarshalState(xor)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(state), parties[0].(*LocalParty).temp.ri.String(), "the nonce must be sealed")
+
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[0], len(signPIDs), testThreshold)
+			restored, err = NewLocalPartyFromState(state, xor, params, keys[0], outCh, endCh)
+			assert.NoError(t, err)
+			if err := restored.Start(); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+		}
+		for j, P := range parties {
+			if j == out.GetFrom().Index {
+				continue
+			}
+			test.SharedPartyUpdater(P, out, errCh)
+			if j == 0 && restored != nil {
+				test.SharedPartyUpdater(restored, out, errCh)
+			}
+		}
+		if len(errCh) > 0 {
+			assert.FailNow(t, (<-errCh).Error())
+		}
+	}
+
+	// the original and the restored party 0 as well as the others all produce the same signature
+	sig := (<-endCh).Signature
+	for len(endCh) > 0 {
+		assert.Equal(t, sig, (<-endCh).Signature)
+	}
+	pk := edwards.PublicKey{
+		Curve: tss.Edwards(),
+		X:     keys[0].EDDSAPub.X(),
+		Y:     keys[0].EDDSAPub.Y(),
+	}
+	parsed, err := edwards.ParseSignature(sig)
+	assert.NoError(t, err)
+	assert.True(t, edwards.Verify(&pk, msg.Bytes(), parsed.R, parsed.S), "eddsa verify must pass")
+}
+
+func TestE2ECompressedCommitments(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pk := edwards.PublicKey{
+		Curve: tss.Edwards(),
+		X:     keys[0].EDDSAPub.X(),
+		Y:     keys[0].EDDSAPub.Y(),
+	}
+
+	sign := func(compressed func(i int) bool) (*common.SignatureData, *tss.Error) {
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		parties := make([]tss.Party, 0, len(signPIDs))
+		errCh := make(chan *tss.Error, len(signPIDs))
+		outCh := make(chan tss.Message, len(signPIDs))
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		for i := 0; i < len(signPIDs); i++ {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+			if compressed(i) {
+				params.SetCompressedCommitments()
+			}
+			parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+		}
+		for _, P := range parties {
+			if err := P.Start(); err != nil {
+				return nil, err
+			}
+		}
+		var data *common.SignatureData
+		done := make(chan struct{})
+		go func() {
+			for range signPIDs {
+				data = <-endCh
+			}
+			close(done)
+		}()
+		return data, routeMessages(parties, outCh, errCh, done, nil)
+	}
+
+	data, tssErr := sign(func(int) bool { return true })
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	sig, err := edwards.ParseSignature(data.Signature)
+	assert.NoError(t, err)
+	assert.True(t, edwards.Verify(&pk, big.NewInt(42).Bytes(), sig.R, sig.S), "eddsa verify must pass")
+
+	// the two modes do not interoperate
+	_, tssErr = sign(func(i int) bool { return i == 0 })
+	assert.NotNil(t, tssErr, "signing must abort when the parties use different commitment encodings")
+}
+
+func TestCompressedCommitmentsDiffer(t *testing.T) {
+	R := crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(42))
+	encodedR := ecPointToEncodedBytes(R.X(), R.Y())
+	salt := big.NewInt(1234)
+	pair := cmt.NewHashCommitmentWithRandomness(salt, R.X(), R.Y())
+	compressed := cmt.NewHashCommitmentWithRandomness(salt, new(big.Int).SetBytes(encodedR[:]))
+	assert.NotEqual(t, 0, pair.C.Cmp(compressed.C), "the same point commits differently in the two modes")
+
+	// but both open to the same point
+	params := tss.NewParameters(tss.Edwards(), nil, nil, 0, 0)
+	ok, values := pair.DeCommit()
+	assert.True(t, ok)
+	fromPair, err := deCommittedRj(params, values)
+	assert.NoError(t, err)
+	params.SetCompressedCommitments()
+	ok, values = compressed.DeCommit()
+	assert.True(t, ok)
+	fromCompressed, err := deCommittedRj(params, values)
+	assert.NoError(t, err)
+	assert.True(t, R.Equals(fromPair))
+	assert.True(t, R.Equals(fromCompressed))
+}
+
+func TestE2EPoseidonCommitments(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pk := edwards.PublicKey{
+		Curve: tss.Edwards(),
+		X:     keys[0].EDDSAPub.X(),
+		Y:     keys[0].EDDSAPub.Y(),
+	}
+
+	sign := func(poseidon func(i int) bool) (*common.SignatureData, *tss.Error) {
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		parties := make([]tss.Party, 0, len(signPIDs))
+		errCh := make(chan *tss.Error, len(signPIDs))
+		outCh := make(chan tss.Message, len(signPIDs))
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		for i := 0; i < len(signPIDs); i++ {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+			if poseidon(i) {
+				params.SetPoseidonCommitments()
+			}
+			parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+		}
+		for _, P := range parties {
+			if err := P.Start(); err != nil {
+				return nil, err
+			}
+		}
+		var data *common.SignatureData
+		done := make(chan struct{})
+		go func() {
+			for range signPIDs {
+				data = <-endCh
+			}
+			close(done)
+		}()
+		return data, routeMessages(parties, outCh, errCh, done, nil)
+	}
+
+	data, tssErr := sign(func(int) bool { return true })
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	sig, err := edwards.ParseSignature(data.Signature)
+	assert.NoError(t, err)
+	assert.True(t, edwards.Verify(&pk, big.NewInt(42).Bytes(), sig.R, sig.S), "eddsa verify must pass")
+
+	// a commitment hashed with Poseidon does not open with SHA-512/256, nor the other way around
+	_, tssErr = sign(func(i int) bool { return i == 0 })
+	if assert.NotNil(t, tssErr, "signing must abort when the parties use different commitment hashes") {
+		assert.True(t, errors.Is(tssErr, cmt.ErrDeCommitVerify))
+	}
+}
+
+func TestE2ESecretsWiped(t *testing.T) {
+	setUp("info")
+
+	// record the buffers that are wiped, so that they can be checked once signing is over
+	var mtx sync.Mutex
+	var wiped [][]byte
+	wipe = func(b []byte) {
+		mtx.Lock()
+		wiped = append(wiped, b)
+		mtx.Unlock()
+		common.Zeroize(b)
+	}
+	defer func() { wipe = common.Zeroize }()
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	parties, _, tssErr := signMessage(keys, signPIDs, big.NewInt(42), nil)
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+
+	// ri, wi, lambda and lambdaReduced in round 3; parties left over from the failing sessions of earlier tests may
+	// add more
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.GreaterOrEqual(t, len(wiped), 4*len(parties))
+	for _, b := range wiped {
+		assert.Equal(t, make([]byte, len(b)), b, "a wiped buffer must be zero")
+	}
+	for i, P := range parties {
+		temp := P.(*LocalParty).temp
+		assert.Equal(t, 0, temp.ri.Sign(), "ri must be wiped")
+		assert.Equal(t, 0, temp.wi.Sign(), "wi must be wiped")
+		assert.NotEqual(t, 0, keys[i].Xi.Sign(), "the key share must not be wiped")
+	}
+}
+
+// signMessage runs a signing session with the parties' parameters passed through `setup` unless it is nil
+func signMessage(keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, msg *big.Int,
+	setup func(*tss.Parameters), fullBytesLen ...int) ([]tss.Party, *common.SignatureData, *tss.Error) {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		if setup != nil {
+			setup(params)
+		}
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh, fullBytesLen...))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			return parties, nil, err
+		}
+	}
+	var data *common.SignatureData
+	done := make(chan struct{})
+	go func() {
+		for range signPIDs {
+			data = <-endCh
+		}
+		close(done)
+	}()
+	if err := routeMessages(parties, outCh, errCh, done, nil); err != nil {
+		return parties, nil, err
+	}
+	return parties, data, nil
+}
+
+func TestExpectedMessagesMatchTraffic(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		for range signPIDs {
+			<-endCh
+		}
+		close(done)
+	}()
+	// the messages that reached party 0, by the round that consumes them
+	broadcasts, p2ps := make(map[int]int), make(map[int]int)
+	count := func(msg tss.Message) tss.Message {
+		var round int
+		switch msg.(tss.ParsedMessage).Content().(type) {
+		case *SignRound1Message:
+			round = 1
+		case *SignRound2Message:
+			round = 2
+		case *SignRound3Message:
+			round = 3
+		}
+		switch {
+		case msg.GetFrom().Index == 0:
+		case msg.IsBroadcast():
+			broadcasts[round]++
+		case msg.GetTo()[0].Index == 0:
+			p2ps[round]++
+		}
+		return msg
+	}
+	if err := routeMessages(parties, outCh, errCh, done, count); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	counter := parties[0].(tss.MessageCounter)
+	for round := 1; round <= 4; round++ {
+		broadcast, p2p := counter.ExpectedMessages(round)
+		assert.Equal(t, broadcasts[round], broadcast, "round %d", round)
+		assert.Equal(t, p2ps[round], p2p, "round %d", round)
+	}
+	assert.Equal(t, len(signPIDs)-1, broadcasts[3], "round 3 waits for n-1 broadcasts")
+
+	// presigning completes round 3 without messages
+	params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[0], len(signPIDs), testThreshold)
+	presigner := NewPresignParty(params, keys[0], outCh, make(chan *Presignature, 1)).(tss.MessageCounter)
+	broadcast, p2p := presigner.ExpectedMessages(3)
+	assert.Zero(t, broadcast+p2p)
+}
+
+func TestE2EMismatchedMessageIsAttributed(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, 2*len(signPIDs)*len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	culprit := signPIDs[1]
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		msg := big.NewInt(42)
+		if i == culprit.Index {
+			msg = big.NewInt(43)
+		}
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	// every party fails in round 3; the parties that agree on the message all name the one that does not
+	victims := make(map[int]*tss.Error)
+	timeout := time.After(time.Minute)
+	for len(victims) < len(signPIDs) {
+		select {
+		case err := <-errCh:
+			if _, ok := victims[err.Victim().Index]; !ok {
+				victims[err.Victim().Index] = err
+			}
+		case msg := <-outCh:
+			if dest := msg.GetTo(); dest != nil {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				continue
+			}
+			for _, P := range parties {
+				if P.PartyID().Index != msg.GetFrom().Index {
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			}
+		case <-endCh:
+			assert.FailNow(t, "no party may finish with a share of another message")
+		case <-timeout:
+			assert.FailNow(t, "timed out waiting for the mismatch to be detected")
+		}
+	}
+	for index, err := range victims {
+		assert.Equal(t, 3, err.Round())
+		assert.Contains(t, err.Error(), "signed a different message")
+		if index != culprit.Index {
+			assert.Equal(t, []*tss.PartyID{culprit}, err.Culprits())
+		}
+	}
+}
+
+func TestE2EPrehashed(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pk := edwards.PublicKey{
+		Curve: tss.Edwards(),
+		X:     keys[0].EDDSAPub.X(),
+		Y:     keys[0].EDDSAPub.Y(),
+	}
+	// a digest whose first byte is zero
+	digest := sha256.Sum256([]byte("payload"))
+	digest[0] = 0x00
+	msg := new(big.Int).SetBytes(digest[:])
+	assert.Less(t, len(msg.Bytes()), len(digest), "big.Int.Bytes drops the leading zero")
+
+	_, data, tssErr := signMessage(keys, signPIDs, msg, (*tss.Parameters).SetPrehashed)
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	assert.Equal(t, digest[:], data.M, "the digest must be signed with its leading zero")
+	sig, err := edwards.ParseSignature(data.Signature)
+	assert.NoError(t, err)
+	assert.True(t, edwards.Verify(&pk, digest[:], sig.R, sig.S), "eddsa verify over the full digest must pass")
+	assert.False(t, edwards.Verify(&pk, msg.Bytes(), sig.R, sig.S), "the truncated digest is a different message")
+
+	// an explicit full length is still honoured
+	_, data, tssErr = signMessage(keys, signPIDs, msg, (*tss.Parameters).SetPrehashed, 64)
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	assert.Equal(t, append(make([]byte, 32), digest[:]...), data.M)
+}
+
+func TestE2EPoseidonPrehash(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	payload := make([]byte, 4096)
+	_, err = rand.Read(payload)
+	assert.NoError(t, err)
+	digest, err := PoseidonPrehash(payload)
+	assert.NoError(t, err)
+	assert.True(t, digest.Cmp(poseidon.FieldOrder()) < 0, "the digest is a field element")
+
+	_, data, tssErr := signMessage(keys, signPIDs, digest, (*tss.Parameters).SetPrehashed)
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	assert.Len(t, data.M, 32)
+	assert.True(t, Verify(keys[0].EDDSAPub, digest, new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S), 32))
+
+	// any change to the message, including trailing zeros, changes the digest
+	tampered := append([]byte{}, payload...)
+	tampered[len(tampered)-1] ^= 1
+	for _, other := range [][]byte{tampered, append(append([]byte{}, payload...), 0)} {
+		otherDigest, err := PoseidonPrehash(other)
+		assert.NoError(t, err)
+		assert.NotEqual(t, 0, digest.Cmp(otherDigest))
+		assert.False(t, Verify(keys[0].EDDSAPub, otherDigest, new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S), 32))
+	}
+	_, err = PoseidonPrehash(nil)
+	assert.Error(t, err)
+}
+
+func TestPrehashedRejectsLongMessage(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	msg := new(big.Int).Lsh(big.NewInt(1), 8*32) // 33 bytes
+	_, _, tssErr := signMessage(keys, signPIDs, msg, (*tss.Parameters).SetPrehashed)
+	assert.Error(t, tssErr, "a message longer than a digest must be rejected")
+
+	_, _, tssErr = signMessage(keys, signPIDs, nil, nil)
+	assert.Error(t, tssErr, "a nil message must be rejected")
+}
+
+func TestE2ECancelMidSigning(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	// every delivery after the cancellation fails, so leave room for all the errors
+	errCh := make(chan *tss.Error, len(signPIDs)*len(signPIDs)*3)
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := tss.StartWithContext(ctx, P); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	// cancel the session once the parties are in round 2
+	cancelInRound2 := func(msg tss.Message) tss.Message {
+		if msg.Type() == "binance.tsslib.eddsa.signing.SignRound2Message" {
+			cancel()
+		}
+		return msg
+	}
+	result := make(chan *tss.Error, 1)
+	go func() {
+		result <- routeMessages(parties, outCh, errCh, nil, cancelInRound2)
+	}()
+	select {
+	case tssErr := <-result:
+		if assert.NotNil(t, tssErr, "the cancelled session must fail") {
+			assert.True(t, errors.Is(tssErr, context.Canceled), "the cause must be context.Canceled: %v", tssErr)
+			assert.Equal(t, context.Canceled, tssErr.Cause())
+			assert.Equal(t, TaskName, tssErr.Task())
+		}
+	case <-time.After(10 * time.Second):
+		assert.FailNow(t, "the cancelled session did not return")
+	}
+	assert.Empty(t, endCh, "no party may finish after cancellation")
+
+	// the parties stay stopped
+	_, tssErr := parties[0].Update(NewSignRound3Message(signPIDs[1], big.NewInt(1), []byte{1}))
+	assert.Error(t, tssErr)
+}
+
+func TestE2ECommitmentReplayAcrossSessions(t *testing.T) {
+	setUp("info")
+
+	// two sessions with different signer sets, and so different session ids, that share the party with Id "2"
+	run := func(start int, tamper func(culprit *tss.PartyID, msg tss.Message) tss.Message) *tss.Error {
+		keys, signPIDs, err := keygen.LoadKeygenTestFixtures(start+testThreshold+1, start)
+		assert.NoError(t, err, "should load keygen fixtures")
+		var culprit *tss.PartyID
+		for _, pid := range signPIDs {
+			if pid.Id == "2" {
+				culprit = pid
+			}
+		}
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		parties := make([]tss.Party, 0, len(signPIDs))
+		errCh := make(chan *tss.Error, len(signPIDs))
+		outCh := make(chan tss.Message, len(signPIDs))
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		for i := 0; i < len(signPIDs); i++ {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+			parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+		}
+		for _, P := range parties {
+			if err := P.Start(); err != nil {
+				assert.FailNow(t, err.Error())
+			}
+		}
+		done := make(chan struct{})
+		go func() {
+			for range signPIDs {
+				<-endCh
+			}
+			close(done)
+		}()
+		return routeMessages(parties, outCh, errCh, done, func(msg tss.Message) tss.Message {
+			if msg.GetFrom().Index != culprit.Index {
+				return msg
+			}
+			return tamper(culprit, msg)
+		})
+	}
+
+	// record the commitment and the de-commitment of the shared party in the first session
+	var commitment cmt.HashCommitment
+	var deCommit cmt.HashDeCommitment
+	record := func(_ *tss.PartyID, msg tss.Message) tss.Message {
+		switch content := msg.(tss.ParsedMessage).Content().(type) {
+		case *SignRound1Message:
+			commitment = content.UnmarshalCommitment()
+		case *SignRound2Message:
+			deCommit, _ = content.UnmarshalDeCommitment(2, tss.Edwards().Params().P)
+		}
+		return msg
+	}
+	if err := run(0, record); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	// and replay them in the second one
+	replay := func(culprit *tss.PartyID, msg tss.Message) tss.Message {
+		switch content := msg.(tss.ParsedMessage).Content().(type) {
+		case *SignRound1Message:
+			return NewSignRound1Message(culprit, commitment)
+		case *SignRound2Message:
+			proof, err := content.UnmarshalZKProof(tss.Edwards())
+			assert.NoError(t, err)
+			return NewSignRound2Message(culprit, deCommit, proof)
+		}
+		return msg
+	}
+	err := run(1, replay)
+	if assert.NotNil(t, err, "the replayed commitment must be rejected") {
+		assert.Contains(t, err.Error(), "de-commitment verify failed")
+		assert.Len(t, err.Culprits(), 1)
+		assert.Equal(t, "2", err.Culprits()[0].Id)
+	}
+}
+
+func TestDuplicateSignRound3Message(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+	P := NewLocalParty(big.NewInt(42), params, keys[0], make(chan tss.Message, len(signPIDs)), make(chan *common.SignatureData, 1))
+
+	sender := signPIDs[1]
+	si := big.NewInt(1234567)
+	mHash := common.SHA512_256(big.NewInt(42).Bytes())
+	ok, tssErr := P.Update(NewSignRound3Message(sender, si, mHash))
+	assert.True(t, ok)
+	assert.Nil(t, tssErr)
+
+	// an identical resend is accepted and changes nothing
+	ok, tssErr = P.Update(NewSignRound3Message(sender, new(big.Int).Set(si), mHash))
+	assert.True(t, ok)
+	assert.Nil(t, tssErr)
+
+	// a different share from the same party is an equivocation
+	ok, tssErr = P.Update(NewSignRound3Message(sender, new(big.Int).Add(si, big.NewInt(1)), mHash))
+	assert.False(t, ok)
+	if assert.NotNil(t, tssErr) {
+		assert.Equal(t, []*tss.PartyID{sender}, tssErr.Culprits())
+		assert.Equal(t, "binance.tsslib.eddsa.signing.SignRound3Message", tssErr.MessageType())
+	}
+	stored := P.(*LocalParty).temp.signRound3Messages[sender.Index].Content().(*SignRound3Message)
+	assert.Equal(t, si, stored.UnmarshalS(), "the first share is kept")
+
+	// the other parties are not affected
+	ok, tssErr = P.Update(NewSignRound3Message(signPIDs[2], si, mHash))
+	assert.True(t, ok)
+	assert.Nil(t, tssErr)
+}
+
+func TestStartRejectsInvalidParties(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	// copies of the sorted party IDs, which the cases below modify
+	copyPIDs := func() tss.SortedPartyIDs {
+		pIDs := make(tss.SortedPartyIDs, len(signPIDs))
+		for i, pID := range signPIDs {
+			pIDs[i] = tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt())
+			pIDs[i].Index = pID.Index
+		}
+		return pIDs
+	}
+	n := len(signPIDs)
+	tests := []struct {
+		name       string
+		pIDs       func() tss.SortedPartyIDs
+		partyCount int
+		threshold  int
+		contains   string
+	}{{
+		name: "threshold equal to the party count", pIDs: copyPIDs, partyCount: n, threshold: n,
+		contains: "1 <= t < n",
+	}, {
+		name: "zero threshold", pIDs: copyPIDs, partyCount: n, threshold: 0,
+		contains: "1 <= t < n",
+	}, {
+		name: "party count one more than the peers", pIDs: copyPIDs, partyCount: n + 1, threshold: testThreshold,
+		contains: "party count",
+	}, {
+		name: "duplicate index",
+		pIDs: func() tss.SortedPartyIDs {
+			pIDs := copyPIDs()
+			pIDs[2].Index = 1
+			return pIDs
+		},
+		partyCount: n, threshold: testThreshold,
+		contains: "contiguous",
+	}, {
+		name: "indices starting at 1",
+		pIDs: func() tss.SortedPartyIDs {
+			pIDs := copyPIDs()
+			for _, pID := range pIDs {
+				pID.Index++
+			}
+			return pIDs
+		},
+		partyCount: n, threshold: testThreshold,
+		contains: "contiguous",
+	}, {
+		name: "duplicate key",
+		pIDs: func() tss.SortedPartyIDs {
+			pIDs := copyPIDs()
+			pIDs[1] = tss.NewPartyID("copy", "copy", pIDs[0].KeyInt())
+			pIDs[1].Index = 1
+			return pIDs
+		},
+		partyCount: n, threshold: testThreshold,
+		contains: "same key",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pIDs := tt.pIDs()
+			params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[0], tt.partyCount, tt.threshold)
+			P := NewLocalParty(big.NewInt(42), params, keys[0], make(chan tss.Message, n), make(chan *common.SignatureData, 1))
+			tssErr := P.Start()
+			if assert.NotNil(t, tssErr) {
+				assert.Contains(t, tssErr.Error(), tt.contains)
+			}
+		})
+	}
+
+	// and a party that is not one of the peers
+	outsider := tss.NewPartyID("outsider", "outsider", big.NewInt(1))
+	outsider.Index = 0
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(copyPIDs()), outsider, n, testThreshold)
+	tssErr := NewLocalParty(big.NewInt(42), params, keys[0], make(chan tss.Message, n), make(chan *common.SignatureData, 1)).Start()
+	if assert.NotNil(t, tssErr) {
+		assert.Contains(t, tssErr.Error(), "not in the peer context")
+	}
+}
+
+// recordingObserver records the round events of a party, with any duration outside of (0, 1 minute] as an extra event
+type recordingObserver struct {
+	mtx    sync.Mutex
+	events []string
+	total  time.Duration
+	msgs   int
+}
+
+func (o *recordingObserver) OnRoundStart(task string, round int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.events = append(o.events, fmt.Sprintf("%s start %d", task, round))
+}
+
+func (o *recordingObserver) OnRoundComplete(task string, round int, duration time.Duration, messages int) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.events = append(o.events, fmt.Sprintf("%s complete %d", task, round))
+	if duration <= 0 || duration > time.Minute {
+		o.events = append(o.events, fmt.Sprintf("round %d took %v", round, duration))
+	}
+	o.total += duration
+	o.msgs += messages
+}
+
+func TestE2ERoundObserver(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	observers := make(map[string]*recordingObserver, len(signPIDs))
+	setup := func(params *tss.Parameters) {
+		o := new(recordingObserver)
+		observers[params.PartyID().Id] = o
+		params.SetRoundObserver(o)
+	}
+	start := time.Now()
+	_, _, tssErr := signMessage(keys, signPIDs, big.NewInt(42), setup)
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+
+	expected := make([]string, 0, 8)
+	for round := 1; round <= 4; round++ {
+		expected = append(expected, fmt.Sprintf("%s start %d", TaskName, round), fmt.Sprintf("%s complete %d", TaskName, round))
+	}
+	assert.Len(t, observers, len(signPIDs))
+	for id, o := range observers {
+		// the last round completes just after the party has output its signature
+		assert.Eventually(t, func() bool {
+			o.mtx.Lock()
+			defer o.mtx.Unlock()
+			return len(o.events) >= len(expected)
+		}, 10*time.Second, time.Millisecond, "party %s", id)
+		o.mtx.Lock()
+		assert.Equal(t, expected, o.events, "party %s", id)
+		elapsed := time.Since(start)
+		assert.True(t, o.total <= elapsed, "party %s: the rounds took %v, longer than the session's %v", id, o.total, elapsed)
+		// three broadcast rounds, each delivering a message from every other party
+		assert.Equal(t, 3*(len(signPIDs)-1), o.msgs, "party %s", id)
+		o.mtx.Unlock()
+	}
+}
+
+func TestE2ERetryWithoutCulprit(t *testing.T) {
+	setUp("info")
+
+	// one signer more than the threshold needs, so that the session can be retried without one of them
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+2, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	culprit := signPIDs[1]
+	msg := big.NewInt(42)
+
+	run := func(params []*tss.Parameters, keys []keygen.LocalPartySaveData, tamper func(tss.Message) tss.Message) (*common.SignatureData, *tss.Error) {
+		parties := make([]tss.Party, 0, len(params))
+		errCh := make(chan *tss.Error, len(params))
+		outCh := make(chan tss.Message, len(params))
+		endCh := make(chan *common.SignatureData, len(params))
+		for i := range params {
+			parties = append(parties, NewLocalParty(msg, params[i], keys[i], outCh, endCh))
+		}
+		for _, P := range parties {
+			if err := P.Start(); err != nil {
+				return nil, err
+			}
+		}
+		var data *common.SignatureData
+		done := make(chan struct{})
+		go func() {
+			for range params {
+				data = <-endCh
+			}
+			close(done)
+		}()
+		if err := routeMessages(parties, outCh, errCh, done, tamper); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	// the culprit sends a bad signature share
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	params := make([]*tss.Parameters, len(signPIDs))
+	for i := range signPIDs {
+		params[i] = tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+	}
+	tamper := func(msg tss.Message) tss.Message {
+		if msg.GetFrom().Index != culprit.Index || msg.Type() != "binance.tsslib.eddsa.signing.SignRound3Message" {
+			return msg
+		}
+		r3msg := msg.(tss.ParsedMessage).Content().(*SignRound3Message)
+		return NewSignRound3Message(culprit, new(big.Int).Add(r3msg.UnmarshalS(), big.NewInt(1)), r3msg.GetMessageHash())
+	}
+	_, tssErr := run(params, keys, tamper)
+	if !assert.NotNil(t, tssErr, "signing must abort") {
+		return
+	}
+	assert.Equal(t, []*tss.PartyID{culprit}, tssErr.Culprits())
+
+	// the honest parties retry without it
+	retryParams := make([]*tss.Parameters, 0, len(signPIDs)-1)
+	retryKeys := make([]keygen.LocalPartySaveData, 0, len(signPIDs)-1)
+	for i := range signPIDs {
+		retry, err := params[i].WithoutParties(tssErr.Culprits()...)
+		if signPIDs[i] == culprit {
+			assert.Error(t, err, "the culprit is not part of the retry")
+			continue
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, len(signPIDs)-1, retry.PartyCount())
+		assert.Equal(t, testThreshold, retry.Threshold())
+		assert.NoError(t, retry.ValidateParties())
+		retryParams = append(retryParams, retry)
+		retryKeys = append(retryKeys, keys[i])
+	}
+	for i, pID := range signPIDs {
+		assert.Equal(t, i, pID.Index, "the party IDs of the aborted session are not modified")
+	}
+	data, tssErr := run(retryParams, retryKeys, nil)
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	assert.True(t, Verify(keys[0].EDDSAPub, msg, new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)))
+
+	// another exclusion would leave fewer than threshold+1 parties
+	_, err = retryParams[0].WithoutParties(retryParams[1].PartyID())
+	assert.Error(t, err)
+}
+
+func TestE2EConcurrentSessionsShareSaveData(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pub := keys[0].EDDSAPub
+
+	// every session signs a different message with the same save data; run under -race to check for data races
+	const sessions = 16
+	var wg sync.WaitGroup
+	results := make([]*common.SignatureData, sessions)
+	errs := make([]*tss.Error, sessions)
+	for i := 0; i < sessions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, results[i], errs[i] = signMessage(keys, signPIDs, big.NewInt(int64(1000+i)), nil)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < sessions; i++ {
+		if !assert.Nil(t, errs[i], "session %d", i) {
+			continue
+		}
+		r, s := new(big.Int).SetBytes(results[i].R), new(big.Int).SetBytes(results[i].S)
+		assert.True(t, Verify(pub, big.NewInt(int64(1000+i)), r, s), "the signature of session %d must verify", i)
+	}
+}
+
+func TestE2ESortPartyIDsByMoniker(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	byMoniker := func(pid *tss.PartyID) []byte { return []byte(pid.Moniker) }
+
+	// two nodes receive the party list in different orders and sort it by moniker
+	lists := make([]tss.SortedPartyIDs, 2)
+	for node := range lists {
+		ids := make(tss.UnSortedPartyIDs, len(signPIDs))
+		for i, j := range mrand.Perm(len(signPIDs)) {
+			ids[i] = tss.NewPartyID(signPIDs[j].Id, signPIDs[j].Moniker, signPIDs[j].KeyInt())
+		}
+		sorted, err := tss.SortPartyIDsBy(ids, byMoniker)
+		if !assert.NoError(t, err) {
+			return
+		}
+		lists[node] = sorted
+	}
+	if !assert.Equal(t, lists[0].Keys(), lists[1].Keys(), "the canonical ordering must not depend on the input order") {
+		return
+	}
+	assert.Equal(t, lists[0].Fingerprint(), lists[1].Fingerprint())
+	assert.NotEqual(t, signPIDs.Fingerprint(), tss.SortedPartyIDs{signPIDs[1], signPIDs[0], signPIDs[2]}.Fingerprint())
+
+	// the parties sign in the moniker order
+	ordered := make([]keygen.LocalPartySaveData, len(keys))
+	for i, pid := range lists[0] {
+		for _, key := range keys {
+			if key.ShareID.Cmp(pid.KeyInt()) == 0 {
+				ordered[i] = key
+			}
+		}
+	}
+	_, data, tssErr := signMessage(ordered, lists[0], big.NewInt(42), nil)
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+	assert.True(t, Verify(keys[0].EDDSAPub, big.NewInt(42), r, s))
+
+	duplicate := tss.UnSortedPartyIDs{lists[0][0], tss.NewPartyID("x", lists[0][0].Moniker, big.NewInt(7))}
+	_, err = tss.SortPartyIDsBy(duplicate, byMoniker)
+	assert.Error(t, err, "two parties with one identifier have no canonical order")
+}
+
+// memoryNonceLog is a tss.NonceLog kept in memory
+type memoryNonceLog struct {
+	mtx  sync.Mutex
+	seen map[string]bool
+}
+
+func (log *memoryNonceLog) RecordNonce(commitment []byte) error {
+	log.mtx.Lock()
+	defer log.mtx.Unlock()
+	if log.seen[string(commitment)] {
+		return tss.ErrNonceReused
+	}
+	log.seen[string(commitment)] = true
+	return nil
+}
+
+func TestE2ENonceLogCatchesReusedNonce(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	logs := make([]*memoryNonceLog, len(signPIDs))
+	for i := range logs {
+		logs[i] = &memoryNonceLog{seen: make(map[string]bool)}
+	}
+	// party 0 draws its nonce from the same seeded source in every session, as a broken RNG would
+	setup := func(params *tss.Parameters) {
+		params.SetNonceLog(logs[params.PartyID().Index])
+		if params.PartyID().Index == 0 {
+			params.SetRand(mrand.New(mrand.NewSource(42)))
+		}
+	}
+	msg := big.NewInt(42)
+
+	_, data, tssErr := signMessage(keys, signPIDs, msg, setup)
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	assert.NotEmpty(t, data.Signature)
+	for _, log := range logs {
+		assert.Len(t, log.seen, 1, "every party records its nonce commitment once")
+	}
+
+	// the second session replays party 0's ri
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		setup(params)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	var leakedSi int32
+	watch := func(msg tss.Message) tss.Message {
+		if msg.GetFrom().Index == 0 && msg.Type() == "binance.tsslib.eddsa.signing.SignRound3Message" {
+			atomic.StoreInt32(&leakedSi, 1)
+		}
+		return msg
+	}
+	tssErr = routeMessages(parties, outCh, errCh, make(chan struct{}), watch)
+	if assert.NotNil(t, tssErr) {
+		assert.True(t, errors.Is(tssErr, tss.ErrNonceReused), tssErr.Error())
+		assert.Equal(t, 3, tssErr.Round())
+		assert.Equal(t, 0, tssErr.Victim().Index)
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&leakedSi), "si must not be broadcast with a reused nonce")
+}
+
+func TestMissingAndRetransmit(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	n := len(signPIDs)
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, n)
+	errCh := make(chan *tss.Error, n)
+	outCh := make(chan tss.Message, n)
+	endCh := make(chan *common.SignatureData, n)
+	for i := 0; i < n; i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], n, testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+	}
+	P := parties[0].(*LocalParty)
+	assert.Empty(t, P.Missing(), "nothing is missing before the start")
+	_, err = P.Retransmit(1)
+	assert.Error(t, err, "no message was sent yet")
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+
+	// run rounds 1 and 2, and hold back all the round 3 messages
+	r3msgs := make([]tss.ParsedMessage, n)
+	done := make(chan struct{})
+	held := 0
+	hold := func(msg tss.Message) tss.Message {
+		if _, ok := msg.(tss.ParsedMessage).Content().(*SignRound3Message); !ok {
+			return msg
+		}
+		r3msgs[msg.GetFrom().Index] = msg.(tss.ParsedMessage)
+		if held++; held == n {
+			close(done)
+		}
+		return nil
+	}
+	if err := routeMessages(parties, outCh, errCh, done, hold); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	others := make([]int, 0, n-1)
+	for j := 1; j < n; j++ {
+		others = append(others, j)
+	}
+	assert.Equal(t, others, P.Missing())
+
+	// deliver the last and the first message only, the last one twice
+	for _, j := range []int{n - 1, 1, n - 1} {
+		if _, err := P.Update(r3msgs[j]); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	assert.Equal(t, others[1:len(others)-1], P.Missing())
+
+	// the coordinator has the missing messages sent again
+	for _, j := range P.Missing() {
+		msg, err := parties[j].(*LocalParty).Retransmit(3)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, r3msgs[j], msg)
+		if _, err := P.Update(msg); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	select {
+	case data := <-endCh:
+		assert.NotEmpty(t, data.Signature)
+	case <-time.After(time.Minute):
+		assert.FailNow(t, "the party did not end")
+	}
+	assert.Empty(t, P.Missing())
+	_, err = P.Retransmit(4)
+	assert.Error(t, err)
+	msg, err := P.Retransmit(2)
+	assert.NoError(t, err)
+	assert.IsType(t, &SignRound2Message{}, msg.Content())
+}
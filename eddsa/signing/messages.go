@@ -79,15 +79,23 @@ func NewSignRound2Message(
 
 func (m *SignRound2Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 3) &&
+		common.NonEmptyMultiBytes(m.DeCommitment) &&
+		// salt and either the compressed Ri or its coordinates
+		(len(m.DeCommitment) == 2 || len(m.DeCommitment) == 3) &&
 		common.NonEmptyBytes(m.ProofAlphaX) &&
 		common.NonEmptyBytes(m.ProofAlphaY) &&
 		common.NonEmptyBytes(m.ProofT)
 }
 
-func (m *SignRound2Message) UnmarshalDeCommitment() []*big.Int {
+// UnmarshalDeCommitment returns the de-commitment of Ri. It fails unless it holds exactly `arity` values, each in
+// [0, bound), see cmt.ValidateDeCommitment.
+func (m *SignRound2Message) UnmarshalDeCommitment(arity int, bound *big.Int) (cmt.HashDeCommitment, error) {
 	deComBzs := m.GetDeCommitment()
-	return cmt.NewHashDeCommitmentFromBytes(deComBzs)
+	D := cmt.NewHashDeCommitmentFromBytes(deComBzs)
+	if err := cmt.ValidateDeCommitment(D, arity, bound); err != nil {
+		return nil, err
+	}
+	return D, nil
 }
 
 func (m *SignRound2Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProof, error) {
@@ -106,16 +114,20 @@ func (m *SignRound2Message) UnmarshalZKProof(ec elliptic.Curve) (*schnorr.ZKProo
 
 // ----- //
 
+// NewSignRound3Message returns the broadcast of the signature share si, together with the hash of the message that
+// the share signs, see base.messageHash
 func NewSignRound3Message(
 	from *tss.PartyID,
 	si *big.Int,
+	messageHash []byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
 		IsBroadcast: true,
 	}
 	content := &SignRound3Message{
-		S: si.Bytes(),
+		S:           si.Bytes(),
+		MessageHash: messageHash,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -123,7 +135,9 @@ func NewSignRound3Message(
 
 func (m *SignRound3Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.S)
+		common.NonEmptyBytes(m.S) &&
+		len(m.S) <= 32 &&
+		common.NonEmptyBytes(m.MessageHash)
 }
 
 func (m *SignRound3Message) UnmarshalS() *big.Int {
@@ -0,0 +1,74 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestSigningMessagesTypeURLRoundTrip(t *testing.T) {
+	ec := tss.Edwards()
+	pID := tss.GenerateTestPartyIDs(1)[0]
+	ssid := []byte("session")
+	ri := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+	Ri := crypto.ScalarBaseMult(ec, ri)
+	commitment := cmt.NewHashCommitmentWithContext(rand.Reader, ssid, Ri.X(), Ri.Y())
+	proof, err := schnorr.NewZKProof(ssid, ri, Ri, rand.Reader)
+	assert.NoError(t, err)
+	const prefix = "tss-lib.example.org/v2"
+
+	for name, msg := range map[string]tss.ParsedMessage{
+		"round 1": NewSignRound1Message(pID, commitment.C),
+		"round 2": NewSignRound2Message(pID, commitment.D, proof),
+		"round 3": NewSignRound3Message(pID, ri, common.SHA512_256([]byte("message"))),
+	} {
+		bz, routing, err := tss.WireWithTypeURL(msg, prefix)
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		assert.Equal(t, pID, routing.From, name)
+		assert.True(t, routing.IsBroadcast, name)
+
+		// the type URL is readable without knowing the Go type
+		any := new(anypb.Any)
+		assert.NoError(t, proto.Unmarshal(bz, any), name)
+		url := prefix + "/" + string(msg.Content().ProtoReflect().Descriptor().FullName())
+		assert.Equal(t, url, any.GetTypeUrl(), name)
+		assert.Equal(t, url, tss.TypeURL(prefix+"/", msg.Content()), name)
+
+		parsed, err := tss.ParseWireWithTypeURL(bz, prefix, pID, true)
+		if assert.NoError(t, err, name) {
+			assert.IsType(t, msg.Content(), parsed.Content(), name)
+			assert.True(t, proto.Equal(msg.Content(), parsed.Content()), name)
+			assert.True(t, parsed.ValidateBasic(), name)
+		}
+		parsed, err = tss.ParseWireMessage(bz, pID, true)
+		if assert.NoError(t, err, name) {
+			assert.True(t, proto.Equal(msg.Content(), parsed.Content()), name)
+		}
+
+		_, err = tss.ParseWireWithTypeURL(bz, "tss-lib.example.org", pID, true)
+		assert.Error(t, err, "%s: a type URL under another prefix", name)
+		_, err = tss.ParseWireWithTypeURL(bz, "", pID, true)
+		assert.Error(t, err, "%s: a type URL under another prefix than the default", name)
+		original, _, err := msg.WireBytes()
+		assert.NoError(t, err, name)
+		_, err = tss.ParseWireWithTypeURL(original, "", pID, true)
+		assert.NoError(t, err, "%s: the default prefix", name)
+	}
+}
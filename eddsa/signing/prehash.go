@@ -0,0 +1,33 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto/poseidon"
+)
+
+// poseidonPrehashTag separates the pre-hash of PoseidonPrehash from the other uses of poseidon.HashInputs
+var poseidonPrehashTag = []byte("tss-lib/eddsa/poseidon-prehash")
+
+// PoseidonPrehash absorbs a message of any length into a single BN254 field element with the Poseidon sponge, as
+// poseidon.HashInputs of a fixed domain tag followed by the message. Every party must be given the digest as the
+// message to sign, with Parameters.SetPrehashed, so that it is signed as 32 big-endian bytes whatever its leading
+// zeros; Verify then takes the digest with a full length of 32. The message length is framed into the hash, so
+// messages that differ only in trailing zero bytes have different digests.
+func PoseidonPrehash(msg []byte) (*big.Int, error) {
+	if msg == nil {
+		return nil, errors.New("PoseidonPrehash: the message is nil")
+	}
+	if uint64(len(msg)) > math.MaxUint32 {
+		return nil, errors.New("PoseidonPrehash: the message is longer than 2^32-1 bytes")
+	}
+	return poseidon.HashInputs(poseidonPrehashTag, msg)
+}
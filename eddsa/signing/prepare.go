@@ -12,6 +12,7 @@ import (
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 )
 
 // PrepareForSigning(), Fig. 7
@@ -24,20 +25,15 @@ func PrepareForSigning(ec elliptic.Curve, i, pax int, xi *big.Int, ks []*big.Int
 		panic(fmt.Errorf("PrepareForSigning: len(ks) <= i (%d <= %d)", len(ks), i))
 	}
 
+	coefs, err := vss.LagrangeCoefficients(ec.Params().N, ks)
+	if err != nil {
+		panic(err)
+	}
+
 	// 1-4.
 	wi = xi
-	for j := 0; j < pax; j++ {
-		if j == i {
-			continue
-		}
-		ksj := ks[j]
-		ksi := ks[i]
-		if ksj.Cmp(ksi) == 0 {
-			panic(fmt.Errorf("index of two parties are equal"))
-		}
-		// big.Int Div is calculated as: a/b = a * modInv(b,q)
-		coef := modQ.Mul(ks[j], modQ.ModInverse(new(big.Int).Sub(ksj, ksi)))
-		wi = modQ.Mul(wi, coef)
+	if pax > 1 {
+		wi = modQ.Mul(xi, coefs[i])
 	}
 
 	return
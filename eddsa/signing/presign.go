@@ -0,0 +1,193 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Presignature is the output of the message-independent rounds 1 and 2 of signing: this party's nonce ri and the
+// aggregated nonce commitment R of all the signers. It can be serialized (e.g. with encoding/json) and is consumed by
+// SignPresig once the message is known.
+//
+// A presignature must only ever be used once: signing two messages with the same nonce reveals the key share.
+// SignPresig clears Ri and refuses presignatures that have already been used, but copies that were persisted before
+// that must be deleted by the caller.
+//
+// R is fixed before the message exists and, unlike a FROST nonce, is not bound to it. An adversary holding many
+// presignatures of a key share at once could pick the messages that they are used on as a function of all their R
+// (the ROS attack, solved with Wagner's algorithm) and forge a signature on a message that no signer approved. A key
+// share may therefore have at most one outstanding presignature: presigning fails in its last round, after the
+// nonce was committed to but before it is handed out, while a presignature of the same key share has not yet been
+// consumed by SignPresig or released with Discard. The bound is kept in the memory of this process; a caller that
+// persists presignatures across restarts must keep at most one per key share itself.
+type Presignature struct {
+	mtx sync.Mutex
+
+	SSID []byte
+	Ri   *big.Int          // secret
+	R    []byte            // encoded aggregated nonce commitment
+	Rjs  []*crypto.ECPoint // nonce commitments of the signing parties, in order
+	Ks   []*big.Int        // keys of the signing parties, in order
+}
+
+// NewPresignParty returns a party that runs signing rounds 1 and 2 without a message and sends the resulting
+// presignature to `end`. All the signers of a future message must take part.
+func NewPresignParty(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *Presignature,
+) tss.Party {
+	p := NewLocalParty(nil, params, key, out, nil).(*LocalParty)
+	p.presigEnd = end
+	return p
+}
+
+// SignPresig returns a party that signs `msg` from a presignature produced by NewPresignParty, starting directly at
+// round 3. Every signer must use its own presignature from the same presigning session. The presignature is consumed
+// and cannot be used again. The party broadcasts its round 3 message as soon as it is started, so all the signers
+// should be started before their messages are delivered.
+func SignPresig(
+	presig *Presignature,
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+	fullBytesLen ...int,
+) (tss.Party, error) {
+	if presig == nil || msg == nil {
+		return nil, errors.New("SignPresig() received a nil presignature or message")
+	}
+	presig.mtx.Lock()
+	defer presig.mtx.Unlock()
+	if presig.Ri == nil {
+		return nil, errors.New("SignPresig() received a presignature that has already been used")
+	}
+	if len(presig.R) != 32 {
+		return nil, fmt.Errorf("SignPresig() received a presignature with an R of %d bytes", len(presig.R))
+	}
+	ks := params.Parties().IDs().Keys()
+	if len(ks) != len(presig.Ks) {
+		return nil, errors.New("SignPresig() received a presignature for a different set of parties")
+	}
+	for j, k := range ks {
+		if k.Cmp(presig.Ks[j]) != 0 {
+			return nil, errors.New("SignPresig() received a presignature for a different set of parties")
+		}
+	}
+	p := NewLocalParty(msg, params, key, out, end, fullBytesLen...).(*LocalParty)
+	releasePresignature(&p.keys)
+	p.temp.ssid = presig.SSID
+	p.temp.ri = presig.Ri
+	p.temp.encodedR = new([32]byte)
+	copy(p.temp.encodedR[:], presig.R)
+	if len(presig.Rjs) == len(ks) {
+		p.temp.bigRjs = presig.Rjs
+	}
+	presig.Ri = nil
+	return p, nil
+}
+
+// Discard destroys a presignature that will not be signed with, so that the key share `key` can presign again. It
+// may also be called on a copy of the outstanding presignature, e.g. one restored from storage.
+func (presig *Presignature) Discard(key keygen.LocalPartySaveData) {
+	presig.mtx.Lock()
+	defer presig.mtx.Unlock()
+	presig.Ri = nil
+	releasePresignature(&key)
+}
+
+// outstandingPresigs holds the key shares, by presignatureShareID, that have a presignature which was neither
+// consumed by SignPresig nor released with Discard
+var outstandingPresigs = struct {
+	sync.Mutex
+	shares map[string]bool
+}{shares: make(map[string]bool)}
+
+// presignatureShareID identifies a key share by its index and the public key that it is a share of
+func presignatureShareID(key *keygen.LocalPartySaveData) string {
+	id := key.ShareID.Text(16)
+	if key.EDDSAPub != nil {
+		id += "," + key.EDDSAPub.X().Text(16) + "," + key.EDDSAPub.Y().Text(16)
+	}
+	return id
+}
+
+// claimPresignature records that the key share has an outstanding presignature; it fails if it has one already
+func claimPresignature(key *keygen.LocalPartySaveData) error {
+	id := presignatureShareID(key)
+	outstandingPresigs.Lock()
+	defer outstandingPresigs.Unlock()
+	if outstandingPresigs.shares[id] {
+		return errors.New("the key share already has an outstanding presignature; sign with it or discard it first")
+	}
+	outstandingPresigs.shares[id] = true
+	return nil
+}
+
+func releasePresignature(key *keygen.LocalPartySaveData) {
+	id := presignatureShareID(key)
+	outstandingPresigs.Lock()
+	delete(outstandingPresigs.shares, id)
+	outstandingPresigs.Unlock()
+}
+
+// ----- //
+
+func (round *presignFinalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	encodedR, err := round.computeR()
+	if err != nil {
+		return err
+	}
+	// ri is dropped if the presignature cannot be handed out
+	if err := claimPresignature(round.key); err != nil {
+		round.temp.ri = nil
+		return round.WrapError(err)
+	}
+	for j := range round.ok {
+		round.ok[j] = true
+	}
+	round.presigEnd <- &Presignature{
+		SSID: round.temp.ssid,
+		Ri:   round.temp.ri,
+		R:    encodedR[:],
+		Rjs:  round.temp.bigRjs,
+		Ks:   round.Parties().IDs().Keys(),
+	}
+	return nil
+}
+
+func (round *presignFinalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *presignFinalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *presignFinalization) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -0,0 +1,283 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// routeMessages delivers the messages of `parties` until `done` is closed or a party fails, returning the failure.
+// Messages are passed through `tamper` first unless it is nil; a message that `tamper` turns into nil is dropped.
+func routeMessages(parties []tss.Party, outCh <-chan tss.Message, errCh chan *tss.Error, done <-chan struct{},
+	tamper func(tss.Message) tss.Message) *tss.Error {
+	for {
+		select {
+		case <-done:
+			return nil
+		case err := <-errCh:
+			return err
+		case msg := <-outCh:
+			if tamper != nil {
+				if msg = tamper(msg); msg == nil {
+					continue
+				}
+			}
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		}
+	}
+}
+
+func runPresign(t *testing.T, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs) []*Presignature {
+	presigs, err := presign(keys, signPIDs)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	return presigs
+}
+
+// presign runs a presigning session and returns the presignatures in party order, or the first failure
+func presign(keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs) ([]*Presignature, *tss.Error) {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *Presignature, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		P := NewPresignParty(params, keys[i], outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	// route until every party has either handed out its presignature or failed, so that none of them is still
+	// running when presign returns
+	var mtx sync.Mutex
+	presigs := make([]*Presignature, 0, len(signPIDs))
+	outcomes := 0
+	done := make(chan struct{})
+	finish := func() {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if outcomes++; outcomes == len(signPIDs) {
+			close(done)
+		}
+	}
+	go func() {
+		for range signPIDs {
+			presig := <-endCh
+			mtx.Lock()
+			presigs = append(presigs, presig)
+			mtx.Unlock()
+			finish()
+		}
+	}()
+	var failure *tss.Error
+	for {
+		err := routeMessages(parties, outCh, errCh, done, nil)
+		if err == nil {
+			break
+		}
+		if failure == nil {
+			failure = err
+		}
+		finish()
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	// the presignatures arrive in any order
+	ordered := make([]*Presignature, len(signPIDs))
+	for _, presig := range presigs {
+		for i, P := range parties {
+			if presig.Ri.Cmp(P.(*LocalParty).temp.ri) == 0 {
+				ordered[i] = presig
+			}
+		}
+	}
+	return ordered, nil
+}
+
+func signWithPresigs(t *testing.T, presigs []*Presignature, msg []byte, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs) []byte {
+	sig, err := signPresigned(t, presigs, msg, keys, signPIDs, nil)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	return sig
+}
+
+// signPresigned signs msg from the presignatures, passing the messages through `tamper` as routeMessages does, and
+// returns the signature or the first failure
+func signPresigned(t *testing.T, presigs []*Presignature, msg []byte, keys []keygen.LocalPartySaveData,
+	signPIDs tss.SortedPartyIDs, tamper func(tss.Message) tss.Message) ([]byte, *tss.Error) {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		P, err := SignPresig(presigs[i], new(big.Int).SetBytes(msg), params, keys[i], outCh, endCh, len(msg))
+		assert.NoError(t, err)
+		parties = append(parties, P)
+	}
+	// round 3 broadcasts as soon as a party starts, so start them all before routing
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			return nil, err
+		}
+	}
+	var sig []byte
+	done := make(chan struct{})
+	go func() {
+		for range signPIDs {
+			sig = (<-endCh).Signature
+		}
+		close(done)
+	}()
+	if err := routeMessages(parties, outCh, errCh, done, tamper); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func TestE2EPresign(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pk := edwards.PublicKey{
+		Curve: tss.Edwards(),
+		X:     keys[0].EDDSAPub.X(),
+		Y:     keys[0].EDDSAPub.Y(),
+	}
+
+	for _, msg := range [][]byte{[]byte("first message"), []byte("second message")} {
+		presigs := runPresign(t, keys, signPIDs)
+		for _, presig := range presigs[1:] {
+			assert.Equal(t, presigs[0].R, presig.R, "all parties must agree on R")
+			assert.Equal(t, presigs[0].Rjs, presig.Rjs, "all parties must agree on the Rj")
+		}
+		assert.Len(t, presigs[0].Rjs, len(signPIDs))
+
+		// a presignature survives serialization
+		bz, err := json.Marshal(presigs[0])
+		assert.NoError(t, err)
+		restored := new(Presignature)
+		assert.NoError(t, json.Unmarshal(bz, restored))
+		presigs[0] = restored
+
+		sig := signWithPresigs(t, presigs, msg, keys, signPIDs)
+		parsed, err := edwards.ParseSignature(sig)
+		assert.NoError(t, err)
+		assert.True(t, edwards.Verify(&pk, msg, parsed.R, parsed.S), "eddsa verify must pass")
+
+		// a presignature is single use
+		params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+		_, err = SignPresig(presigs[0], big.NewInt(1), params, keys[0], nil, nil)
+		assert.Error(t, err)
+	}
+}
+
+func TestE2EPresignOneOutstanding(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	first := runPresign(t, keys, signPIDs)
+	_, tssErr := presign(keys, signPIDs)
+	if assert.NotNil(t, tssErr, "a second presignature of a key share must be refused") {
+		assert.Equal(t, 3, tssErr.Round())
+	}
+
+	// discarding releases the key share, even through a restored copy
+	bz, err := json.Marshal(first[0])
+	assert.NoError(t, err)
+	restored := new(Presignature)
+	assert.NoError(t, json.Unmarshal(bz, restored))
+	restored.Discard(keys[0])
+	for i, presig := range first[1:] {
+		presig.Discard(keys[i+1])
+	}
+	assert.Nil(t, restored.Ri)
+	second := runPresign(t, keys, signPIDs)
+
+	// and so does signing
+	signWithPresigs(t, second, []byte("message"), keys, signPIDs)
+	for _, presig := range runPresign(t, keys, signPIDs) {
+		assert.NotNil(t, presig.Ri)
+	}
+	for i := range keys {
+		releasePresignature(&keys[i])
+	}
+}
+
+func TestE2EPresignWithoutRjsRejectsBadShare(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	culprit := signPIDs[1]
+	q := tss.Edwards().Params().N
+
+	tests := []struct {
+		name string
+		si   func(si *big.Int) *big.Int
+	}{{
+		name: "33 bytes",
+		si:   func(si *big.Int) *big.Int { return new(big.Int).Add(si, new(big.Int).Lsh(big.NewInt(1), 256)) },
+	}, {
+		name: "unreduced",
+		si:   func(si *big.Int) *big.Int { return new(big.Int).Add(si, q) },
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the per-share verification of round 4 needs the Rj, which these presignatures lack
+			presigs := runPresign(t, keys, signPIDs)
+			for _, presig := range presigs {
+				presig.Rjs = nil
+			}
+			tamper := func(msg tss.Message) tss.Message {
+				if msg.GetFrom().Index != culprit.Index || msg.Type() != "binance.tsslib.eddsa.signing.SignRound3Message" {
+					return msg
+				}
+				r3msg := msg.(tss.ParsedMessage).Content().(*SignRound3Message)
+				return NewSignRound3Message(culprit, tt.si(r3msg.UnmarshalS()), r3msg.GetMessageHash())
+			}
+			_, tssErr := signPresigned(t, presigs, []byte("message"), keys, signPIDs, tamper)
+			if assert.NotNil(t, tssErr, "signing must abort") {
+				assert.Equal(t, []*tss.PartyID{culprit}, tssErr.Culprits(), tssErr.Error())
+			}
+		})
+	}
+}
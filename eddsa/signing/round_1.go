@@ -19,9 +19,9 @@ import (
 )
 
 // round 1 represents round 1 of the signing part of the EDDSA TSS spec
-func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Round {
+func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData, presigEnd chan<- *Presignature) tss.Round {
 	return &round1{
-		&base{params, key, data, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1},
+		&base{params, key, data, temp, out, end, presigEnd, make([]bool, len(params.Parties().IDs())), false, 1},
 	}
 }
 
@@ -40,12 +40,30 @@ func (round *round1) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(err)
 	}
+	if err = validateSSID(round.temp.ssid); err != nil {
+		return round.WrapError(err)
+	}
 	// 1. select ri
-	ri := common.GetRandomPositiveInt(round.Rand(), round.Params().EC().Params().N)
+	ri, err := round.selectRi()
+	if err != nil {
+		return round.WrapError(err)
+	}
 
 	// 2. make commitment
 	pointRi := crypto.ScalarBaseMult(round.Params().EC(), ri)
-	cmt := commitments.NewHashCommitment(round.Rand(), pointRi.X(), pointRi.Y())
+	// bound to the ssid so that it cannot be replayed in another session
+	secrets := []*big.Int{pointRi.X(), pointRi.Y()}
+	if round.CompressedCommitments() {
+		encodedRi, err := pointRi.CompressedBytes()
+		if err != nil {
+			return round.WrapError(err)
+		}
+		secrets = []*big.Int{new(big.Int).SetBytes(encodedRi)}
+	}
+	cmt, err := commitments.NewHashCommitmentWith(round.Rand(), commitmentHasher(round.Params()), round.temp.ssid, secrets...)
+	if err != nil {
+		return round.WrapError(err)
+	}
 
 	// 3. store r1 message pieces
 	round.temp.ri = ri
@@ -58,6 +76,8 @@ func (round *round1) Start() *tss.Error {
 	// 4. broadcast commitment
 	r1msg2 := NewSignRound1Message(round.PartyID(), cmt.C)
 	round.temp.signRound1Messages[i] = r1msg2
+	round.temp.transcript.recordSession(round.messageBytes(), round.temp.ssid)
+	round.temp.transcript.recordMessage(r1msg2)
 	round.out <- r1msg2
 
 	return nil
@@ -69,7 +89,14 @@ func (round *round1) Update() (bool, *tss.Error) {
 		if round.ok[j] {
 			continue
 		}
-		if msg == nil || !round.CanAccept(msg) {
+		if msg == nil {
+			ret = false
+			continue
+		}
+		if !round.CanAccept(msg) {
+			if err := round.rejectMessage(msg); err != nil {
+				return false, err
+			}
 			ret = false
 			continue
 		}
@@ -103,7 +130,29 @@ func (round *round1) prepare() error {
 		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
 	}
 	wi := PrepareForSigning(round.Params().EC(), i, len(ks), xi, ks)
+	if wi == xi {
+		// wi is wiped after round 3 and must not alias the key share
+		wi = new(big.Int).Set(xi)
+	}
 
 	round.temp.wi = wi
 	return nil
 }
+
+// selectRi returns a fresh nonce ri in [1, q), from the NonceGenerator of the parameters if there is one
+func (round *round1) selectRi() (*big.Int, error) {
+	q := round.Params().EC().Params().N
+	generator := round.NonceGenerator()
+	if generator == nil {
+		return common.GetRandomPositiveInt(round.Rand(), q), nil
+	}
+	ri, err := generator.GenerateNonce(new(big.Int).Set(q))
+	if err != nil {
+		return nil, fmt.Errorf("NonceGenerator.GenerateNonce(): %v", err)
+	}
+	if ri == nil || ri.Sign() <= 0 || ri.Cmp(q) >= 0 {
+		return nil, errors.New("NonceGenerator.GenerateNonce() returned a nonce that is not in [1, q)")
+	}
+	// ri is zeroized after round 3 and must not alias a value that the generator keeps
+	return new(big.Int).Set(ri), nil
+}
@@ -0,0 +1,88 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// mockHSM stands in for a nonce generator backed by an HSM, handing out a fixed nonce or error
+type mockHSM struct {
+	nonce *big.Int
+	err   error
+	calls int
+}
+
+func (hsm *mockHSM) GenerateNonce(q *big.Int) (*big.Int, error) {
+	hsm.calls++
+	return hsm.nonce, hsm.err
+}
+
+func newNonceRound(generator tss.NonceGenerator) (*round1, chan tss.Message) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[0], 2, 1)
+	params.SetNonceGenerator(generator)
+	temp := &localTempData{
+		localMessageStore: localMessageStore{
+			signRound1Messages: make([]tss.ParsedMessage, 2),
+		},
+	}
+	// the ssid commits to the public key shares, so the key needs some
+	key := &keygen.LocalPartySaveData{BigXj: []*crypto.ECPoint{
+		crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(1)),
+		crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(2)),
+	}}
+	out := make(chan tss.Message, 1)
+	round := newRound1(params, key, &common.SignatureData{}, temp, out, nil, nil).(*round1)
+	return round, out
+}
+
+func TestRound1TakesNonceFromGenerator(t *testing.T) {
+	hsm := &mockHSM{nonce: big.NewInt(123456789)}
+	round, out := newNonceRound(hsm)
+	if err := round.Start(); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	assert.Equal(t, 1, hsm.calls)
+	assert.Equal(t, 0, round.temp.ri.Cmp(hsm.nonce))
+	assert.True(t, round.temp.pointRi.Equals(crypto.ScalarBaseMult(tss.Edwards(), hsm.nonce)))
+	assert.Len(t, out, 1, "the commitment must be broadcast")
+
+	// the nonce is zeroized after use, which must not reach into the generator
+	common.ZeroizeBigInt(round.temp.ri)
+	assert.Equal(t, 0, hsm.nonce.Cmp(big.NewInt(123456789)))
+}
+
+func TestRound1RejectsInvalidGeneratedNonces(t *testing.T) {
+	q := tss.Edwards().Params().N
+	for name, hsm := range map[string]*mockHSM{
+		"nil":      {},
+		"zero":     {nonce: big.NewInt(0)},
+		"negative": {nonce: big.NewInt(-1)},
+		"q":        {nonce: new(big.Int).Set(q)},
+		"q + 1":    {nonce: new(big.Int).Add(q, big.NewInt(1))},
+		"error":    {nonce: big.NewInt(1), err: errors.New("the HSM is unavailable")},
+	} {
+		round, out := newNonceRound(hsm)
+		tssErr := round.Start()
+		if assert.NotNil(t, tssErr, name) {
+			assert.Contains(t, tssErr.Error(), "NonceGenerator.GenerateNonce()", name)
+			assert.Equal(t, 1, tssErr.Round(), name)
+		}
+		assert.Nil(t, round.temp.ri, name)
+		assert.Empty(t, out, "%s: no commitment must be broadcast", name)
+	}
+}
@@ -23,6 +23,9 @@ func (round *round2) Start() *tss.Error {
 	round.number = 2
 	round.started = true
 	round.resetOK()
+	if err := validateSSID(round.temp.ssid); err != nil {
+		return round.WrapError(err)
+	}
 
 	i := round.PartyID().Index
 
@@ -42,6 +45,7 @@ func (round *round2) Start() *tss.Error {
 	// 3. BROADCAST de-commitments of Shamir poly*G and Schnorr prove
 	r2msg2 := NewSignRound2Message(round.PartyID(), round.temp.deCommit, pir)
 	round.temp.signRound2Messages[i] = r2msg2
+	round.temp.transcript.recordMessage(r2msg2)
 	round.out <- r2msg2
 
 	return nil
@@ -60,7 +64,14 @@ func (round *round2) Update() (bool, *tss.Error) {
 		if round.ok[j] {
 			continue
 		}
-		if msg == nil || !round.CanAccept(msg) {
+		if msg == nil {
+			ret = false
+			continue
+		}
+		if !round.CanAccept(msg) {
+			if err := round.rejectMessage(msg); err != nil {
+				return false, err
+			}
 			ret = false
 			continue
 		}
@@ -71,5 +82,8 @@ func (round *round2) Update() (bool, *tss.Error) {
 
 func (round *round2) NextRound() tss.Round {
 	round.started = false
+	if round.presigEnd != nil {
+		return &presignFinalization{round}
+	}
 	return &round3{round}
 }
@@ -7,19 +7,38 @@
 package signing
 
 import (
+	"bytes"
+	"crypto/elliptic"
 	"crypto/sha512"
 	"math/big"
+	"sync"
 
-	"github.com/agl/ed25519/edwards25519"
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
+type rjOut struct {
+	unWrappedErr error
+	Rj           *crypto.ECPoint
+}
+
 func (round *round3) Start() *tss.Error {
+	// a round that already ran on this state, e.g. before a crash, is not run again: ri is wiped by then, so its si
+	// and r are kept as they are, and its message is not broadcast a second time
+	if round.completed() {
+		if !round.started {
+			round.number = 3
+			round.started = true
+			round.resetOK()
+		}
+		return nil
+	}
 	if round.started {
 		return round.WrapError(errors.New("round already started"))
 	}
@@ -27,103 +46,264 @@ func (round *round3) Start() *tss.Error {
 	round.number = 3
 	round.started = true
 	round.resetOK()
+	if err := validateSSID(round.temp.ssid); err != nil {
+		return round.WrapError(err)
+	}
 
-	// 1. init R
-	var R edwards25519.ExtendedGroupElement
-	riBytes := bigIntToEncodedBytes(round.temp.ri)
-	edwards25519.GeScalarMultBase(&R, riBytes)
+	round.temp.transcript.recordSession(round.messageBytes(), round.temp.ssid)
+	round.temp.transcript.recordSecrets(round.temp.ri, round.temp.wi)
 
-	// 2-6. compute R
-	i := round.PartyID().Index
-	for j, Pj := range round.Parties().IDs() {
-		if j == i {
-			continue
-		}
+	// the nonce ri and the share wi are not needed after this round, whether it succeeds or not
+	defer func() {
+		common.ZeroizeBigInt(round.temp.ri)
+		common.ZeroizeBigInt(round.temp.wi)
+	}()
 
-		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
-		msg := round.temp.signRound2Messages[j]
-		r2msg := msg.Content().(*SignRound2Message)
-		cmtDeCmt := commitments.HashCommitDecommit{C: round.temp.cjs[j], D: r2msg.UnmarshalDeCommitment()}
-		ok, coordinates := cmtDeCmt.DeCommit()
-		if !ok {
-			return round.WrapError(errors.New("de-commitment verify failed"))
-		}
-		if len(coordinates) != 2 {
-			return round.WrapError(errors.New("length of de-commitment should be 2"))
+	// 0. refuse to sign with a nonce that was used before, e.g. because of a broken RNG or a replayed state
+	if log := round.NonceLog(); log != nil {
+		pointRi := crypto.ScalarBaseMult(round.Params().EC(), round.temp.ri)
+		if err := log.RecordNonce(ecPointToEncodedBytes(pointRi.X(), pointRi.Y())[:]); err != nil {
+			return round.WrapError(errors.Wrap(err, "NonceLog.RecordNonce(Ri)"))
 		}
+	}
 
-		Rj, err := crypto.NewECPoint(round.Params().EC(), coordinates[0], coordinates[1])
-		Rj = Rj.EightInvEight()
-		if err != nil {
-			return round.WrapError(errors.Wrapf(err, "NewECPoint(Rj)"), Pj)
-		}
-		proof, err := r2msg.UnmarshalZKProof(round.Params().EC())
-		if err != nil {
-			return round.WrapError(errors.New("failed to unmarshal Rj proof"), Pj)
+	// 1-6. compute R, unless it was taken from a presignature
+	encodedR := round.temp.encodedR
+	if encodedR == nil {
+		var err *tss.Error
+		if encodedR, err = round.computeR(); err != nil {
+			return err
 		}
-		ok = proof.Verify(ContextJ, Rj)
-		if !ok {
-			return round.WrapError(errors.New("failed to prove Rj"), Pj)
-		}
-
-		extendedRj := ecPointToExtendedElement(round.Params().EC(), Rj.X(), Rj.Y(), round.Rand())
-		R = addExtendedElements(R, extendedRj)
 	}
-
-	// 7. compute lambda
-	var encodedR [32]byte
-	R.ToBytes(&encodedR)
+	round.temp.transcript.recordR(encodedR)
+	riBytes, err := encodeScalar(round.temp.ri)
+	if err != nil {
+		return round.WrapError(errors.Wrap(err, "encode ri"))
+	}
+	defer wipe(riBytes[:])
 	encodedPubKey := ecPointToEncodedBytes(round.key.EDDSAPub.X(), round.key.EDDSAPub.Y())
 
+	// 7. compute lambda
 	// h = hash512(k || A || M)
 	h := sha512.New()
 	h.Reset()
 	h.Write(encodedR[:])
 	h.Write(encodedPubKey[:])
-	if round.temp.fullBytesLen == 0 {
-		h.Write(round.temp.m.Bytes())
-	} else {
-		var mBytes = make([]byte, round.temp.fullBytesLen)
-		round.temp.m.FillBytes(mBytes)
-		h.Write(mBytes)
-	}
+	h.Write(round.messageBytes())
 
 	var lambda [64]byte
+	defer wipe(lambda[:])
 	h.Sum(lambda[:0])
 	var lambdaReduced [32]byte
-	edwards25519.ScReduce(&lambdaReduced, &lambda)
+	defer wipe(lambdaReduced[:])
+	scReduce(&lambdaReduced, &lambda)
 
-	// 8. compute si
+	// 8. compute si; it is broadcast below, so only its inputs are wiped
 	var localS [32]byte
-	edwards25519.ScMulAdd(&localS, &lambdaReduced, bigIntToEncodedBytes(round.temp.wi), riBytes)
+	wiBytes, err := encodeScalar(round.temp.wi)
+	if err != nil {
+		return round.WrapError(errors.Wrap(err, "encode wi"))
+	}
+	defer wipe(wiBytes[:])
+	if err := scMulAdd(&localS, &lambdaReduced, wiBytes, riBytes); err != nil {
+		return round.WrapError(err)
+	}
 
 	// 9. store r3 message pieces
 	round.temp.si = &localS
-	round.temp.r = encodedBytesToBigInt(&encodedR)
+	round.temp.r = encodedBytesToBigInt(encodedR)
 
 	// 10. broadcast si to other parties
-	r3msg := NewSignRound3Message(round.PartyID(), encodedBytesToBigInt(&localS))
+	r3msg := NewSignRound3Message(round.PartyID(), encodedBytesToBigInt(&localS), round.messageHash())
 	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
+	round.temp.transcript.recordMessage(r3msg)
 	round.out <- r3msg
 
 	return nil
 }
 
+// computeR verifies the de-commitments and proofs of the other parties' Rj and returns the encoded sum of all Rj.
+// The Rj are kept in temp.bigRjs so that the signature shares can be checked against them in the finalization.
+// It does not depend on the message, which lets it also run when producing a presignature.
+func (round *round2) computeR() (*[32]byte, *tss.Error) {
+	// 2-6. compute R
+	// the de-commitments of all the other parties are verified together first; owners maps them back to the parties
+	i := round.PartyID().Index
+	Ps := round.Parties().IDs()
+	pairs := make([]commitments.HashCommitDecommit, 0, len(Ps)-1)
+	owners := make([]int, 0, len(Ps)-1)
+	{
+		// malformed de-commitments are rejected before any hashing
+		arity, bound := deCommittedRjLen(round.Params()), deCommittedRjBound(round.Params())
+		hasher := commitmentHasher(round.Params())
+		var multiErr error
+		culprits := make([]*tss.PartyID, 0, len(Ps))
+		for j := range Ps {
+			if j == i {
+				continue
+			}
+			r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
+			D, err := r2msg.UnmarshalDeCommitment(arity, bound)
+			if err != nil {
+				multiErr = multierror.Append(multiErr, err)
+				culprits = append(culprits, Ps[j])
+				continue
+			}
+			pairs = append(pairs, commitments.HashCommitDecommit{C: round.temp.cjs[j], D: D, Ctx: round.temp.ssid, Hasher: hasher})
+			owners = append(owners, j)
+		}
+		if len(culprits) > 0 {
+			return nil, round.WrapError(multiErr, culprits...).WithMessageType(&SignRound2Message{})
+		}
+	}
+	if results, err := commitments.BatchDeCommit(pairs); err != nil {
+		culprits := make([]*tss.PartyID, 0, len(Ps))
+		for k, ok := range results {
+			if !ok {
+				culprits = append(culprits, Ps[owners[k]])
+			}
+		}
+		return nil, round.WrapError(commitments.ErrDeCommitVerify, culprits...).
+			WithMessageType(&SignRound2Message{})
+	}
+	// the proofs are verified by a bounded pool of workers; the Rj are summed afterwards in index order
+	values := make([][]*big.Int, len(owners))
+	proofs := make([]*schnorr.ZKProof, len(owners))
+	for k, j := range owners {
+		values[k] = pairs[k].D[1:] // already de-committed; [1:] skips the randomness r
+		// a proof that cannot be unmarshalled is left nil and reported along with the proofs that do not verify
+		proofs[k], _ = round.temp.signRound2Messages[j].Content().(*SignRound2Message).UnmarshalZKProof(round.Params().EC())
+	}
+	Rjs, culprits, err := verifyNonceCommitments(round.Params(), round.temp.ssid, owners, values, proofs)
+	if err != nil {
+		return nil, round.WrapError(err, culprits...).WithMessageType(&SignRound2Message{})
+	}
+	// 1. init R with this party's Ri, then add the verified Rj
+	bigRjs := make([]*crypto.ECPoint, len(Ps))
+	bigRjs[i] = round.temp.pointRi
+	for k, Rj := range Rjs {
+		bigRjs[owners[k]] = Rj
+	}
+	sumR, j, err := sumNonceCommitments(round.Params().EC(), bigRjs)
+	if err != nil {
+		if j < 0 || j == i {
+			return nil, round.WrapError(err)
+		}
+		return nil, round.WrapError(err, Ps[j])
+	}
+	round.temp.bigRjs = bigRjs
+
+	// the nonces of the parties must not cancel out, which the commitments only make unlikely
+	if sumR.IsInfinity() {
+		return nil, round.WrapError(errors.New("the nonce point R is the identity"))
+	}
+	return ecPointToEncodedBytes(sumR.X(), sumR.Y()), nil
+}
+
+// verifyNonceCommitments decodes the Rj that the parties `owners` de-committed to in `values`, clears their cofactor
+// and verifies their proofs of knowledge of rj, bound to the session `ssid`, with a bounded pool of workers. It
+// returns the Rj in the order of owners, or the errors together with the parties at fault.
+func verifyNonceCommitments(params *tss.Parameters, ssid []byte, owners []int, values [][]*big.Int,
+	proofs []*schnorr.ZKProof) ([]*crypto.ECPoint, []*tss.PartyID, error) {
+	results := make([]rjOut, len(owners))
+	sem := make(chan struct{}, params.Concurrency())
+	wg := sync.WaitGroup{}
+	for k, j := range owners {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(k, j int) {
+			defer func() { <-sem; wg.Done() }()
+			Rj, err := verifyNonceCommitment(params, ssid, j, values[k], proofs[k])
+			results[k] = rjOut{err, Rj}
+		}(k, j)
+	}
+	wg.Wait()
+
+	Ps := params.Parties().IDs()
+	Rjs := make([]*crypto.ECPoint, len(owners))
+	var multiErr error
+	culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+	for k, result := range results {
+		if result.unWrappedErr != nil {
+			multiErr = multierror.Append(multiErr, result.unWrappedErr)
+			culprits = append(culprits, Ps[owners[k]])
+		}
+		Rjs[k] = result.Rj
+	}
+	if len(culprits) > 0 {
+		return nil, culprits, multiErr
+	}
+	return Rjs, nil, nil
+}
+
+// verifyNonceCommitment decodes party j's Rj from its de-committed `values`, whose arity was checked already, clears
+// its cofactor and verifies its proof of knowledge of rj. It is safe to call concurrently.
+func verifyNonceCommitment(params *tss.Parameters, ssid []byte, j int, values []*big.Int, proof *schnorr.ZKProof) (*crypto.ECPoint, error) {
+	Rj, err := deCommittedRj(params, values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "NewECPoint(Rj)")
+	}
+	Rj = Rj.EightInvEight()
+	if proof == nil {
+		return nil, errors.New("failed to unmarshal Rj proof")
+	}
+	ContextJ := common.AppendBigIntToBytesSlice(ssid, big.NewInt(int64(j)))
+	if ok := proof.Verify(ContextJ, Rj); !ok {
+		return nil, errors.New("failed to prove Rj")
+	}
+	return Rj, nil
+}
+
+// sumNonceCommitments returns the sum of the nonce commitments Rjs. On failure it also returns the index of the Rj
+// that could not be added, or -1 if the sum itself failed. The Rjs are added in index order only so that the index
+// of a failure is deterministic: the sum, and so the encoding of R, is the same in any order, as the Rj have their
+// cofactor cleared and crypto.PointAccumulator returns canonical affine coordinates.
+func sumNonceCommitments(ec elliptic.Curve, Rjs []*crypto.ECPoint) (*crypto.ECPoint, int, error) {
+	R := crypto.NewPointAccumulator(ec)
+	for j, Rj := range Rjs {
+		if err := R.Add(Rj); err != nil {
+			return nil, j, err
+		}
+	}
+	sumR, err := R.Result()
+	if err != nil {
+		return nil, -1, err
+	}
+	return sumR, -1, nil
+}
+
 func (round *round3) Update() (bool, *tss.Error) {
 	ret := true
 	for j, msg := range round.temp.signRound3Messages {
 		if round.ok[j] {
 			continue
 		}
-		if msg == nil || !round.CanAccept(msg) {
+		if msg == nil {
+			ret = false
+			continue
+		}
+		if !round.CanAccept(msg) {
+			if err := round.rejectMessage(msg); err != nil {
+				return false, err
+			}
 			ret = false
 			continue
 		}
+		// a share of another message would only surface as an invalid signature, so the sender is named here
+		if !bytes.Equal(msg.Content().(*SignRound3Message).GetMessageHash(), round.messageHash()) {
+			return false, round.WrapError(errors.New("the party signed a different message"), msg.GetFrom()).
+				WithMessageType(&SignRound3Message{})
+		}
 		round.ok[j] = true
 	}
 	return ret, nil
 }
 
+// completed reports whether round 3 has already broadcast the share si of the party on this state
+func (round *round3) completed() bool {
+	return round.temp.si != nil && round.temp.r != nil && round.temp.signRound3Messages[round.PartyID().Index] != nil
+}
+
 func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
 	if _, ok := msg.Content().(*SignRound3Message); ok {
 		return msg.IsBroadcast()
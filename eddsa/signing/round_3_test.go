@@ -0,0 +1,324 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// newComputeRRound returns party 0's round 2 of an n-party session whose round 1 and 2 messages have all been
+// received, together with the sum of the nonces of all the parties computed sequentially
+func newComputeRRound(tb testing.TB, n int) (*round2, *crypto.ECPoint) {
+	pIDs := tss.GenerateTestPartyIDs(n)
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[0], n, n-1)
+	temp := &localTempData{
+		localMessageStore: localMessageStore{
+			signRound1Messages: make([]tss.ParsedMessage, n),
+			signRound2Messages: make([]tss.ParsedMessage, n),
+			signRound3Messages: make([]tss.ParsedMessage, n),
+		},
+		cjs:  make([]*big.Int, n),
+		ssid: []byte("session"),
+	}
+	q := tss.Edwards().Params().N
+	var R *crypto.ECPoint
+	for j, pID := range pIDs {
+		rj := common.GetRandomPositiveInt(rand.Reader, q)
+		Rj := crypto.ScalarBaseMult(tss.Edwards(), rj)
+		if j == 0 {
+			temp.ri, temp.pointRi, R = rj, Rj, Rj
+			continue
+		}
+		var err error
+		if R, err = R.Add(Rj); err != nil {
+			tb.Fatal(err)
+		}
+		commitment := cmt.NewHashCommitmentWithContext(rand.Reader, temp.ssid, Rj.X(), Rj.Y())
+		proof, err := schnorr.NewZKProof(common.AppendBigIntToBytesSlice(temp.ssid, big.NewInt(int64(j))), rj, Rj, rand.Reader)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		temp.cjs[j] = commitment.C
+		temp.signRound2Messages[j] = NewSignRound2Message(pID, commitment.D, proof)
+	}
+	round := newRound1(params, &keygen.LocalPartySaveData{}, &common.SignatureData{}, temp, nil, nil, nil).(*round1)
+	return &round2{round}, R
+}
+
+func TestComputeRMatchesSequentialSum(t *testing.T) {
+	round, R := newComputeRRound(t, 12)
+	expected := ecPointToEncodedBytes(R.X(), R.Y())
+	for _, concurrency := range []int{1, 3, 16} {
+		round.Params().SetConcurrency(concurrency)
+		encodedR, err := round.computeR()
+		if err != nil {
+			assert.FailNow(t, err.Error())
+		}
+		assert.Equal(t, expected, encodedR, "concurrency %d", concurrency)
+	}
+}
+
+func TestSumNonceCommitmentsOrderIndependent(t *testing.T) {
+	ec := tss.Edwards()
+	q := ec.Params().N
+	Rjs := make([]*crypto.ECPoint, 12)
+	for j := range Rjs {
+		Rjs[j] = crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(rand.Reader, q)).EightInvEight()
+	}
+	// Rj + Rk = 0 for two of the signers
+	Rjs[4] = crypto.ScalarBaseMult(ec, big.NewInt(3)).EightInvEight()
+	Rjs[9] = crypto.ScalarBaseMult(ec, new(big.Int).Sub(q, big.NewInt(3))).EightInvEight()
+	R, _, err := sumNonceCommitments(ec, Rjs)
+	if !assert.NoError(t, err) {
+		return
+	}
+	expected := ecPointToEncodedBytes(R.X(), R.Y())
+
+	rnd := mrand.New(mrand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		permuted := make([]*crypto.ECPoint, len(Rjs))
+		for k, j := range rnd.Perm(len(Rjs)) {
+			permuted[k] = Rjs[j]
+		}
+		sum, _, err := sumNonceCommitments(ec, permuted)
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, ecPointToEncodedBytes(sum.X(), sum.Y()), "trial %d", trial)
+		}
+	}
+}
+
+func TestComputeRCulprits(t *testing.T) {
+	round, _ := newComputeRRound(t, 12)
+	// parties 4 and 9 send the proofs of each other, which do not verify for their own Rj
+	pIDs := round.Parties().IDs()
+	msg4 := round.temp.signRound2Messages[4].Content().(*SignRound2Message)
+	msg9 := round.temp.signRound2Messages[9].Content().(*SignRound2Message)
+	proof4, err := msg4.UnmarshalZKProof(tss.Edwards())
+	assert.NoError(t, err)
+	proof9, err := msg9.UnmarshalZKProof(tss.Edwards())
+	assert.NoError(t, err)
+	deCommit4, err := msg4.UnmarshalDeCommitment(2, tss.Edwards().Params().P)
+	assert.NoError(t, err)
+	deCommit9, err := msg9.UnmarshalDeCommitment(2, tss.Edwards().Params().P)
+	assert.NoError(t, err)
+	round.temp.signRound2Messages[4] = NewSignRound2Message(pIDs[4], deCommit4, proof9)
+	round.temp.signRound2Messages[9] = NewSignRound2Message(pIDs[9], deCommit9, proof4)
+
+	round.Params().SetConcurrency(4)
+	_, tssErr := round.computeR()
+	if assert.NotNil(t, tssErr) {
+		assert.Contains(t, tssErr.Error(), "failed to prove Rj")
+		assert.Equal(t, []*tss.PartyID{pIDs[4], pIDs[9]}, tssErr.Culprits())
+		assert.Equal(t, "binance.tsslib.eddsa.signing.SignRound2Message", tssErr.MessageType())
+	}
+}
+
+func BenchmarkComputeR(b *testing.B) {
+	round, _ := newComputeRRound(b, 50)
+	for _, concurrency := range []int{1, 8} {
+		round.Params().SetConcurrency(concurrency)
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := round.computeR(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestRound3RejectsNonCanonicalScalars(t *testing.T) {
+	q := tss.Edwards().Params().N
+	// round 3 zeroizes ri and wi, so each case gets its own copies
+	for name, tc := range map[string]struct{ ri, wi func() *big.Int }{
+		"ri = L":     {ri: func() *big.Int { return new(big.Int).Set(q) }},
+		"wi = L":     {wi: func() *big.Int { return new(big.Int).Set(q) }},
+		"wi = L + 1": {wi: func() *big.Int { return new(big.Int).Add(q, big.NewInt(1)) }},
+		"wi = 2^255": {wi: func() *big.Int { return new(big.Int).Lsh(big.NewInt(1), 255) }},
+	} {
+		round2, R := newComputeRRound(t, 3)
+		out := make(chan tss.Message, 1)
+		round2.out = out
+		round2.key.EDDSAPub = R
+		round2.temp.m = big.NewInt(42)
+		round2.temp.wi = common.GetRandomPositiveInt(rand.Reader, q)
+		if tc.ri != nil {
+			round2.temp.ri = tc.ri()
+		}
+		if tc.wi != nil {
+			round2.temp.wi = tc.wi()
+		}
+		tssErr := (&round3{round2}).Start()
+		if assert.NotNil(t, tssErr, name) {
+			assert.Contains(t, tssErr.Error(), "not reduced", name)
+			assert.Equal(t, 3, tssErr.Round(), name)
+		}
+		assert.Empty(t, out, "%s: si must not be broadcast", name)
+	}
+}
+
+func TestRound3StartIsIdempotent(t *testing.T) {
+	q := tss.Edwards().Params().N
+	r2, R := newComputeRRound(t, 3)
+	out := make(chan tss.Message, 3)
+	r2.out = out
+	r2.key.EDDSAPub = R
+	r2.temp.m = big.NewInt(42)
+	r2.temp.wi = common.GetRandomPositiveInt(rand.Reader, q)
+	round := &round3{r2}
+	if err := round.Start(); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	si, r := *round.temp.si, new(big.Int).Set(round.temp.r)
+
+	assert.Nil(t, round.Start(), "starting the completed round again")
+	// a new round over the same state, as after a restart, does not run again either
+	b := *r2.base
+	b.started = false
+	again := &round3{&round2{&round1{&b}}}
+	assert.Nil(t, again.Start())
+	assert.True(t, again.started)
+	assert.Equal(t, 3, again.RoundNumber())
+
+	assert.Equal(t, si, *round.temp.si)
+	assert.Equal(t, r, round.temp.r)
+	if assert.Len(t, out, 1, "si must be broadcast once") {
+		msg := (<-out).(tss.ParsedMessage)
+		assert.Equal(t, encodedBytesToBigInt(&si), msg.Content().(*SignRound3Message).UnmarshalS())
+	}
+}
+
+func TestRound3AbortsOnInvalidMessage(t *testing.T) {
+	q := tss.Edwards().Params().N
+	r2, R := newComputeRRound(t, 3)
+	r2.out = make(chan tss.Message, 3)
+	r2.key.EDDSAPub = R
+	r2.temp.m = big.NewInt(42)
+	r2.temp.wi = common.GetRandomPositiveInt(rand.Reader, q)
+	round := &round3{r2}
+	if err := round.Start(); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	Ps := round.Parties().IDs()
+	// a signature share sent point-to-point and a message of round 2 in the slot of round 3
+	meta := tss.MessageRouting{From: Ps[1], To: []*tss.PartyID{Ps[0]}}
+	content := &SignRound3Message{S: big.NewInt(1).Bytes(), MessageHash: round.messageHash()}
+	p2p := tss.NewMessage(meta, content, tss.NewMessageWrapper(meta, content))
+	invalid := map[string]tss.ParsedMessage{
+		"p2p share":  p2p,
+		"wrong type": round.temp.signRound2Messages[1],
+	}
+	// by default the round keeps waiting for a message that it can accept
+	for name, msg := range invalid {
+		round.temp.signRound3Messages[1] = msg
+		ok, err := round.Update()
+		assert.False(t, ok, name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, []int{1, 2}, round.Missing(), name)
+	}
+
+	round.Params().SetAbortOnInvalidMessage()
+	for name, msg := range invalid {
+		round.temp.signRound3Messages[1] = msg
+		ok, err := round.Update()
+		assert.False(t, ok, name)
+		if assert.NotNil(t, err, name) {
+			assert.Equal(t, []*tss.PartyID{Ps[1]}, err.Culprits(), name)
+			assert.Equal(t, 3, err.Round(), name)
+		}
+		assert.Equal(t, []int{1, 2}, round.Missing(), name)
+	}
+}
+
+func TestRoundsRejectMissingSSID(t *testing.T) {
+	for name, ssid := range map[string][]byte{
+		"nil":      nil,
+		"empty":    {},
+		"too long": make([]byte, 33),
+	} {
+		r2, R := newComputeRRound(t, 3)
+		out := make(chan tss.Message, 1)
+		r2.out = out
+		r2.key.EDDSAPub = R
+		r2.temp.m = big.NewInt(42)
+		r2.temp.wi = big.NewInt(1)
+		r2.temp.ssid = ssid
+		ri := new(big.Int).Set(r2.temp.ri)
+
+		tssErr := r2.Start()
+		if assert.NotNil(t, tssErr, name) {
+			assert.Contains(t, tssErr.Error(), "session ID", name)
+			assert.Equal(t, 2, tssErr.Round(), name)
+		}
+		r2.started = false
+		tssErr = (&round3{r2}).Start()
+		if assert.NotNil(t, tssErr, name) {
+			assert.Contains(t, tssErr.Error(), "session ID", name)
+			assert.Equal(t, 3, tssErr.Round(), name)
+		}
+		assert.Empty(t, out, "%s: nothing must be broadcast", name)
+		assert.Equal(t, ri, r2.temp.ri, "%s: the round must fail before it uses ri", name)
+
+		_, err := ComputeAggregateR(r2.Params(), ssid, make([]cmt.HashDeCommitment, 3), make([]*schnorr.ZKProof, 3))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestComputeRRejectsMalformedDeCommitments(t *testing.T) {
+	round, _ := newComputeRRound(t, 8)
+	pIDs := round.Parties().IDs()
+	P := tss.Edwards().Params().P
+	// party 2 opens a coordinate that is not reduced and party 5 a single value, before any commitment is checked
+	for j, values := range map[int][]*big.Int{2: {P, big.NewInt(1)}, 5: {big.NewInt(1)}} {
+		r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
+		proof, err := r2msg.UnmarshalZKProof(tss.Edwards())
+		assert.NoError(t, err)
+		deCommit := append(cmt.HashDeCommitment{big.NewInt(7)}, values...)
+		round.temp.signRound2Messages[j] = NewSignRound2Message(pIDs[j], deCommit, proof)
+	}
+
+	_, tssErr := round.computeR()
+	if assert.NotNil(t, tssErr) {
+		assert.Equal(t, []*tss.PartyID{pIDs[2], pIDs[5]}, tssErr.Culprits())
+		assert.True(t, errors.Is(tssErr, cmt.ErrDeCommitRange))
+		assert.True(t, errors.Is(tssErr, cmt.ErrDeCommitArity))
+		assert.False(t, errors.Is(tssErr, cmt.ErrDeCommitVerify))
+		assert.Equal(t, "binance.tsslib.eddsa.signing.SignRound2Message", tssErr.MessageType())
+	}
+}
+
+func TestComputeRRejectsIdentity(t *testing.T) {
+	round, R := newComputeRRound(t, 4)
+	neg := func(p *crypto.ECPoint) *crypto.ECPoint {
+		return crypto.NewECPointNoCurveCheck(tss.Edwards(), new(big.Int).Sub(tss.Edwards().Params().P, p.X()), p.Y())
+	}
+	// party 0's Ri cancels the nonce points of the others
+	others, err := R.Add(neg(round.temp.pointRi))
+	if !assert.NoError(t, err) {
+		return
+	}
+	round.temp.pointRi = neg(others)
+
+	_, tssErr := round.computeR()
+	if assert.NotNil(t, tssErr) {
+		assert.Contains(t, tssErr.Error(), "the nonce point R is the identity")
+	}
+}
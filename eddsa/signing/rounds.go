@@ -8,6 +8,7 @@ package signing
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
@@ -18,19 +19,26 @@ import (
 
 const (
 	TaskName = "eddsa-signing"
+
+	// prehashedDigestLen is the length of a prehashed message unless the party is given another one
+	prehashedDigestLen = 32
+
+	// maxSSIDLen is the length of a session ID, a SHA-512/256 digest, which may be shorter by its leading zero bytes
+	maxSSIDLen = 32
 )
 
 type (
 	base struct {
 		*tss.Parameters
-		key     *keygen.LocalPartySaveData
-		data    *common.SignatureData
-		temp    *localTempData
-		out     chan<- tss.Message
-		end     chan<- *common.SignatureData
-		ok      []bool // `ok` tracks parties which have been verified by Update()
-		started bool
-		number  int
+		key       *keygen.LocalPartySaveData
+		data      *common.SignatureData
+		temp      *localTempData
+		out       chan<- tss.Message
+		end       chan<- *common.SignatureData
+		presigEnd chan<- *Presignature
+		ok        []bool // `ok` tracks parties which have been verified by Update()
+		started   bool
+		number    int
 	}
 	round1 struct {
 		*base
@@ -44,6 +52,9 @@ type (
 	finalization struct {
 		*round3
 	}
+	presignFinalization struct {
+		*round2
+	}
 )
 
 var (
@@ -51,6 +62,7 @@ var (
 	_ tss.Round = (*round2)(nil)
 	_ tss.Round = (*round3)(nil)
 	_ tss.Round = (*finalization)(nil)
+	_ tss.Round = (*presignFinalization)(nil)
 )
 
 // ----- //
@@ -79,16 +91,26 @@ func (round *base) CanProceed() bool {
 // WaitingFor is called by a Party for reporting back to the caller
 func (round *base) WaitingFor() []*tss.PartyID {
 	Ps := round.Parties().IDs()
-	ids := make([]*tss.PartyID, 0, len(round.ok))
-	for j, ok := range round.ok {
-		if ok {
-			continue
-		}
+	missing := round.Missing()
+	ids := make([]*tss.PartyID, 0, len(missing))
+	for _, j := range missing {
 		ids = append(ids, Ps[j])
 	}
 	return ids
 }
 
+// Missing returns the indices of the parties whose message of the round has not been accepted by Update yet, in
+// increasing order
+func (round *base) Missing() []int {
+	missing := make([]int, 0, len(round.ok))
+	for j, ok := range round.ok {
+		if !ok {
+			missing = append(missing, j)
+		}
+	}
+	return missing
+}
+
 func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
 	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
 }
@@ -102,6 +124,56 @@ func (round *base) resetOK() {
 	}
 }
 
+// rejectMessage returns an error that names the sender of a stored message that the round cannot accept if the
+// parameters abort on an invalid message, and nil otherwise, in which case the round keeps waiting for another one
+func (round *base) rejectMessage(msg tss.ParsedMessage) *tss.Error {
+	if !round.AbortOnInvalidMessage() {
+		return nil
+	}
+	return round.WrapError(fmt.Errorf("round %d cannot accept the %s that the party sent", round.number, msg.Type()),
+		msg.GetFrom()).WithMessageType(msg.Content())
+}
+
+// messageBytes returns the message as it is signed, see messageToInput. checkMessage has ruled out the messages
+// that it cannot encode.
+func (round *base) messageBytes() []byte {
+	mBytes, _ := messageToInput(round.temp.m, round.messageLen())
+	return mBytes
+}
+
+// messageHash returns the commitment to the message that is broadcast with the signature share: the SHA-512/256 hash of
+// messageBytes. It lets the parties detect a signer that was given another message, or another full length.
+func (round *base) messageHash() []byte {
+	return common.SHA512_256(round.messageBytes())
+}
+
+// messageLen returns the full length of the message: the one given to the party, or the digest length of a
+// prehashed message when none was given
+func (round *base) messageLen() int {
+	if round.temp.fullBytesLen == 0 && round.Prehashed() {
+		return prehashedDigestLen
+	}
+	return round.temp.fullBytesLen
+}
+
+// checkMessage rejects a message that messageBytes cannot encode
+func (round *base) checkMessage() error {
+	if round.temp.m == nil && round.presigEnd != nil {
+		return nil // presigning has no message yet
+	}
+	_, err := messageToInput(round.temp.m, round.messageLen())
+	return err
+}
+
+// validateSSID returns an error unless ssid has the length of a session ID. The proofs and commitments of the rounds
+// are bound to it, so a missing one would leave them replayable across sessions rather than fail.
+func validateSSID(ssid []byte) error {
+	if len(ssid) == 0 || len(ssid) > maxSSIDLen {
+		return fmt.Errorf("the session ID has %d bytes, expected 1 to %d", len(ssid), maxSSIDLen)
+	}
+	return nil
+}
+
 // get ssid from local params
 func (round *base) getSSID() ([]byte, error) {
 	ssidList := []*big.Int{round.EC().Params().P, round.EC().Params().N, round.EC().Params().Gx, round.EC().Params().Gy} // ec curve
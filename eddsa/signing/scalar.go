@@ -0,0 +1,51 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/agl/ed25519/edwards25519"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// The edwards25519 scalar routines take raw little-endian arrays and do not check them: ScMulAdd ignores the top bit
+// of its inputs and does not reduce values above the group order L, so a malformed scalar yields a wrong si instead
+// of an error. The wrappers below only pass canonical scalars in [0, L) on.
+
+// edwardsOrder is the order L of the ed25519 base point
+var edwardsOrder = tss.Edwards().Params().N
+
+// encodeScalar returns the little-endian encoding of the scalar s. It fails unless 0 <= s < L.
+func encodeScalar(s *big.Int) (*[32]byte, error) {
+	return crypto.ScalarToLE32(s, edwardsOrder)
+}
+
+// isCanonicalScalar reports whether the little-endian encoding s is below L
+func isCanonicalScalar(s *[32]byte) bool {
+	return crypto.ScalarFromLE32(s).Cmp(edwardsOrder) < 0
+}
+
+// scReduce sets out to the 64-byte little-endian integer h reduced mod L. Every h is a valid input.
+func scReduce(out *[32]byte, h *[64]byte) {
+	edwards25519.ScReduce(out, h)
+}
+
+// scMulAdd sets out to a*b + c mod L. It fails, leaving out unchanged, unless a, b and c are canonical scalars.
+func scMulAdd(out, a, b, c *[32]byte) error {
+	if out == nil || a == nil || b == nil || c == nil {
+		return errors.New("scMulAdd() received a nil scalar")
+	}
+	if !isCanonicalScalar(a) || !isCanonicalScalar(b) || !isCanonicalScalar(c) {
+		return errors.New("scMulAdd() received a scalar that is not below the group order")
+	}
+	edwards25519.ScMulAdd(out, a, b, c)
+	return nil
+}
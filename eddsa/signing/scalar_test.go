@@ -0,0 +1,100 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// nonCanonicalScalars are 32-byte encodings that are not below the group order L
+func nonCanonicalScalars() map[string]*[32]byte {
+	l := new([32]byte)
+	copy(l[:], reversed(edwardsOrder.Bytes()))
+	lPlusOne := *l
+	lPlusOne[0]++
+	topBit := new([32]byte)
+	topBit[31] = 0x80
+	allOnes := new([32]byte)
+	for i := range allOnes {
+		allOnes[i] = 0xff
+	}
+	return map[string]*[32]byte{"L": l, "L + 1": &lPlusOne, "2^255": topBit, "2^256 - 1": allOnes}
+}
+
+func reversed(bz []byte) []byte {
+	out := make([]byte, len(bz))
+	for i, b := range bz {
+		out[len(bz)-1-i] = b
+	}
+	return out
+}
+
+func TestEncodeScalarRejectsNonCanonical(t *testing.T) {
+	for name, s := range map[string]*big.Int{
+		"nil":      nil,
+		"negative": big.NewInt(-1),
+		"L":        edwardsOrder,
+		"L + 1":    new(big.Int).Add(edwardsOrder, big.NewInt(1)),
+		"2^255":    new(big.Int).Lsh(big.NewInt(1), 255),
+		"2^256":    new(big.Int).Lsh(big.NewInt(1), 256),
+	} {
+		_, err := encodeScalar(s)
+		assert.Error(t, err, name)
+	}
+	lMinusOne := new(big.Int).Sub(edwardsOrder, big.NewInt(1))
+	for _, s := range []*big.Int{big.NewInt(0), lMinusOne} {
+		bz, err := encodeScalar(s)
+		if assert.NoError(t, err) {
+			assert.True(t, isCanonicalScalar(bz))
+			assert.Equal(t, 0, s.Cmp(encodedBytesToBigInt(bz)))
+		}
+	}
+	for name, bz := range nonCanonicalScalars() {
+		assert.False(t, isCanonicalScalar(bz), name)
+	}
+}
+
+func TestScMulAdd(t *testing.T) {
+	modL := common.ModInt(edwardsOrder)
+	a, b, c := common.GetRandomPositiveInt(rand.Reader, edwardsOrder), common.GetRandomPositiveInt(rand.Reader, edwardsOrder),
+		common.GetRandomPositiveInt(rand.Reader, edwardsOrder)
+	aBz, _ := encodeScalar(a)
+	bBz, _ := encodeScalar(b)
+	cBz, _ := encodeScalar(c)
+	var out [32]byte
+	if assert.NoError(t, scMulAdd(&out, aBz, bBz, cBz)) {
+		assert.Equal(t, 0, modL.Add(modL.Mul(a, b), c).Cmp(encodedBytesToBigInt(&out)))
+	}
+
+	// every operand is checked, and a rejected call leaves out unchanged
+	for name, bad := range nonCanonicalScalars() {
+		for i, args := range [][3]*[32]byte{{bad, bBz, cBz}, {aBz, bad, cBz}, {aBz, bBz, bad}} {
+			res := out
+			err := scMulAdd(&res, args[0], args[1], args[2])
+			assert.Error(t, err, "%s as operand %d", name, i)
+			assert.Equal(t, out, res, "%s as operand %d", name, i)
+		}
+	}
+	assert.Error(t, scMulAdd(&out, nil, bBz, cBz))
+	assert.Error(t, scMulAdd(nil, aBz, bBz, cBz))
+}
+
+func TestScReduce(t *testing.T) {
+	var h [64]byte
+	_, _ = rand.Read(h[:])
+	var out [32]byte
+	scReduce(&out, &h)
+	assert.True(t, isCanonicalScalar(&out))
+	expected := new(big.Int).Mod(new(big.Int).SetBytes(reversed(h[:])), edwardsOrder)
+	assert.Equal(t, 0, expected.Cmp(encodedBytesToBigInt(&out)))
+}
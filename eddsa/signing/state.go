@@ -0,0 +1,222 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	signingStateVersion = 1
+)
+
+type (
+	// signingState is the serialized form of localTempData. The secrets are kept apart so that they can be sealed.
+	signingState struct {
+		Version      int
+		M            *big.Int
+		FullBytesLen int
+		PointRi      *crypto.ECPoint
+		DeCommit     []*big.Int
+		Cjs          []*big.Int
+		R            *big.Int
+		EncodedR     []byte
+		BigRjs       []*crypto.ECPoint
+		SSID         []byte
+		SSIDNonce    *big.Int
+		Messages     [3][][]byte // wire bytes of the stored messages per round and party index
+		Secrets      []byte
+	}
+
+	signingSecrets struct {
+		Ri *big.Int
+		Si []byte
+	}
+)
+
+// MarshalState serializes the signing state of a party that is waiting for the messages of a round, so that it can
+// be restored later with NewLocalPartyFromState. The secret nonce and share are passed through `seal` (e.g. to
+// encrypt them) before they are written; a nil `seal` stores them in the clear. It must not be called concurrently
+// with Update, and it is not supported for parties created with NewPresignParty.
+func (p *LocalParty) MarshalState(seal func(secret []byte) ([]byte, error)) ([]byte, error) {
+	if p.presigEnd != nil {
+		return nil, errors.New("MarshalState() is not supported for presigning parties")
+	}
+	return p.temp.marshalState(seal)
+}
+
+// NewLocalPartyFromState restores a party from the output of MarshalState. `open` must reverse the `seal` given to
+// MarshalState and may be nil if that was nil too. The other arguments are the ones the party was created with.
+// Calling Start on the restored party resumes the round it was waiting in without repeating it; the messages that
+// were delivered before the state was saved must not be delivered again.
+func NewLocalPartyFromState(
+	state []byte,
+	open func(sealed []byte) ([]byte, error),
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) (tss.Party, error) {
+	p := NewLocalParty(nil, params, key, out, end).(*LocalParty)
+	if err := p.temp.unmarshalState(state, open, params); err != nil {
+		return nil, err
+	}
+	p.resumed = true
+	return p, nil
+}
+
+// ----- //
+
+func (temp *localTempData) marshalState(seal func([]byte) ([]byte, error)) ([]byte, error) {
+	secrets := signingSecrets{Ri: temp.ri}
+	if temp.si != nil {
+		secrets.Si = temp.si[:]
+	}
+	secretsBz, err := json.Marshal(&secrets)
+	if err != nil {
+		return nil, err
+	}
+	if seal != nil {
+		if secretsBz, err = seal(secretsBz); err != nil {
+			return nil, fmt.Errorf("MarshalState(): failed to seal the secrets: %v", err)
+		}
+	}
+	state := signingState{
+		Version:      signingStateVersion,
+		M:            temp.m,
+		FullBytesLen: temp.fullBytesLen,
+		PointRi:      temp.pointRi,
+		DeCommit:     temp.deCommit,
+		Cjs:          temp.cjs,
+		R:            temp.r,
+		BigRjs:       temp.bigRjs,
+		SSID:         temp.ssid,
+		SSIDNonce:    temp.ssidNonce,
+		Secrets:      secretsBz,
+	}
+	if temp.encodedR != nil {
+		state.EncodedR = temp.encodedR[:]
+	}
+	for r, msgs := range [][]tss.ParsedMessage{temp.signRound1Messages, temp.signRound2Messages, temp.signRound3Messages} {
+		state.Messages[r] = make([][]byte, len(msgs))
+		for j, msg := range msgs {
+			if msg == nil {
+				continue
+			}
+			if state.Messages[r][j], _, err = msg.WireBytes(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return json.Marshal(&state)
+}
+
+func (temp *localTempData) unmarshalState(bz []byte, open func([]byte) ([]byte, error), params *tss.Parameters) error {
+	state := new(signingState)
+	if err := json.Unmarshal(bz, state); err != nil {
+		return fmt.Errorf("UnmarshalState(): %v", err)
+	}
+	if state.Version != signingStateVersion {
+		return fmt.Errorf("UnmarshalState(): unsupported state version %d", state.Version)
+	}
+	secretsBz := state.Secrets
+	if open != nil {
+		var err error
+		if secretsBz, err = open(secretsBz); err != nil {
+			return fmt.Errorf("UnmarshalState(): failed to open the secrets: %v", err)
+		}
+	}
+	secrets := new(signingSecrets)
+	if err := json.Unmarshal(secretsBz, secrets); err != nil {
+		return fmt.Errorf("UnmarshalState(): %v", err)
+	}
+	if secrets.Ri == nil || state.M == nil {
+		return errors.New("UnmarshalState(): the state is incomplete")
+	}
+	partyIDs := params.Parties().IDs()
+	if len(state.Cjs) != len(partyIDs) {
+		return errors.New("UnmarshalState(): the state is for a different set of parties")
+	}
+
+	temp.m = state.M
+	temp.fullBytesLen = state.FullBytesLen
+	temp.ri = secrets.Ri
+	temp.pointRi = state.PointRi
+	temp.deCommit = cmt.HashDeCommitment(state.DeCommit)
+	temp.cjs = state.Cjs
+	temp.r = state.R
+	if len(state.BigRjs) == len(partyIDs) {
+		temp.bigRjs = state.BigRjs
+	}
+	temp.ssid = state.SSID
+	temp.ssidNonce = state.SSIDNonce
+	if len(secrets.Si) == 32 {
+		temp.si = new([32]byte)
+		copy(temp.si[:], secrets.Si)
+	}
+	if len(state.EncodedR) == 32 {
+		temp.encodedR = new([32]byte)
+		copy(temp.encodedR[:], state.EncodedR)
+	}
+	for r, msgs := range [][]tss.ParsedMessage{temp.signRound1Messages, temp.signRound2Messages, temp.signRound3Messages} {
+		if len(state.Messages[r]) != len(msgs) {
+			return errors.New("UnmarshalState(): the state is for a different set of parties")
+		}
+		for j, wire := range state.Messages[r] {
+			if wire == nil {
+				continue
+			}
+			msg, err := tss.ParseWireMessage(wire, partyIDs[j], true)
+			if err != nil {
+				return fmt.Errorf("UnmarshalState(): %v", err)
+			}
+			msgs[j] = msg
+		}
+	}
+	return nil
+}
+
+// restoredRound returns the round that a party restored from a saved state was waiting in: the last round for which
+// it stored its own message
+func (p *LocalParty) restoredRound(round1 *round1) tss.Round {
+	i := p.PartyID().Index
+	switch {
+	case p.temp.signRound3Messages[i] != nil:
+		return &resumedRound{&round3{&round2{round1}}, round1.base, 3}
+	case p.temp.signRound2Messages[i] != nil:
+		return &resumedRound{&round2{round1}, round1.base, 2}
+	default:
+		return &resumedRound{round1, round1.base, 1}
+	}
+}
+
+// resumedRound wraps the round a restored party continues in. That round was already started before the state was
+// saved, so starting it again only marks it as started.
+type resumedRound struct {
+	tss.Round
+	base   *base
+	number int
+}
+
+func (round *resumedRound) Start() *tss.Error {
+	if round.base.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.base.number = round.number
+	round.base.started = true
+	round.base.resetOK()
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/hex"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+type (
+	// Transcript is a diagnostic record of the signing session of one party, as collected once RecordTranscript is
+	// called, e.g. for an operator to find out why a session produced no valid signature. It is meant to be
+	// serialized with encoding/json: every value is a lowercase hex string of big-endian bytes, as in the JSON
+	// encodings of the messages, and the fields that the party has not reached yet are left empty.
+	Transcript struct {
+		Party   string `json:"party"`
+		Message string `json:"message,omitempty"`
+		SSID    string `json:"ssid,omitempty"`
+		// Signers holds what every signer sent, in index order, including the party itself
+		Signers []TranscriptSigner `json:"signers"`
+		// R is the encoded aggregated nonce commitment of round 3 and S the sum of the signature shares
+		R string `json:"r,omitempty"`
+		S string `json:"s,omitempty"`
+		// Secrets is nil unless the transcript is recorded with them
+		Secrets *TranscriptSecrets `json:"secrets,omitempty"`
+		// Round, Error and Culprits describe the first failure of the party
+		Round    int      `json:"round,omitempty"`
+		Error    string   `json:"error,omitempty"`
+		Culprits []string `json:"culprits,omitempty"`
+	}
+
+	// TranscriptSigner holds the messages of one signer: the commitment of round 1, the de-commitment and the proof
+	// of knowledge of its nonce of round 2 and the signature share of round 3
+	TranscriptSigner struct {
+		ID             string   `json:"id"`
+		Commitment     string   `json:"commitment,omitempty"`
+		DeCommitment   []string `json:"de_commitment,omitempty"`
+		ProofAlphaX    string   `json:"proof_alpha_x,omitempty"`
+		ProofAlphaY    string   `json:"proof_alpha_y,omitempty"`
+		ProofT         string   `json:"proof_t,omitempty"`
+		SignatureShare string   `json:"signature_share,omitempty"`
+	}
+
+	// TranscriptSecrets holds the secret inputs of round 3 of the party: its nonce ri and its share wi
+	TranscriptSecrets struct {
+		Ri string `json:"ri"`
+		Wi string `json:"wi"`
+	}
+
+	// transcriptRecorder collects the Transcript of a party. The rounds record into it with the lock of the party
+	// held and the party dumps it after Start or Update has returned, so it has a lock of its own. All the methods
+	// may be called on a nil recorder, which records nothing.
+	transcriptRecorder struct {
+		mtx         sync.Mutex
+		transcript  Transcript
+		withSecrets bool
+		dump        func(*Transcript)
+		failed      bool
+	}
+)
+
+// RecordTranscript makes the party record a Transcript of its session from then on, so it must be called before
+// Start. On the first error that Start or Update returns, `dump` is called with the transcript, outside of the lock
+// of the party; it may be nil if the transcript is only read with Transcript. The secret nonce and share of the party
+// are only recorded if `withSecrets` is set: a transcript with secrets reveals the key share of the party and must
+// be handled like it.
+func (p *LocalParty) RecordTranscript(dump func(*Transcript), withSecrets bool) {
+	Ps := p.params.Parties().IDs()
+	tr := &transcriptRecorder{withSecrets: withSecrets, dump: dump}
+	tr.transcript.Party = p.PartyID().String()
+	tr.transcript.Signers = make([]TranscriptSigner, len(Ps))
+	for j, Pj := range Ps {
+		tr.transcript.Signers[j].ID = Pj.String()
+	}
+	p.temp.transcript = tr
+}
+
+// Transcript returns a copy of the transcript that the party has recorded so far, or nil if RecordTranscript was not
+// called
+func (p *LocalParty) Transcript() *Transcript {
+	return p.temp.transcript.snapshot()
+}
+
+// ----- //
+
+func (tr *transcriptRecorder) snapshot() *Transcript {
+	if tr == nil {
+		return nil
+	}
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	out := tr.transcript
+	out.Signers = append([]TranscriptSigner{}, tr.transcript.Signers...)
+	for j, signer := range out.Signers {
+		out.Signers[j].DeCommitment = append([]string(nil), signer.DeCommitment...)
+	}
+	out.Culprits = append([]string(nil), tr.transcript.Culprits...)
+	if tr.transcript.Secrets != nil {
+		secrets := *tr.transcript.Secrets
+		out.Secrets = &secrets
+	}
+	return &out
+}
+
+// recordMessage records a message that the party sent or stored
+func (tr *transcriptRecorder) recordMessage(msg tss.ParsedMessage) {
+	if tr == nil || msg == nil {
+		return
+	}
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	j := msg.GetFrom().Index
+	if j < 0 || j >= len(tr.transcript.Signers) {
+		return
+	}
+	signer := &tr.transcript.Signers[j]
+	switch content := msg.Content().(type) {
+	case *SignRound1Message:
+		signer.Commitment = hex.EncodeToString(content.GetCommitment())
+	case *SignRound2Message:
+		signer.DeCommitment = make([]string, len(content.GetDeCommitment()))
+		for n, bz := range content.GetDeCommitment() {
+			signer.DeCommitment[n] = hex.EncodeToString(bz)
+		}
+		signer.ProofAlphaX = hex.EncodeToString(content.GetProofAlphaX())
+		signer.ProofAlphaY = hex.EncodeToString(content.GetProofAlphaY())
+		signer.ProofT = hex.EncodeToString(content.GetProofT())
+	case *SignRound3Message:
+		signer.SignatureShare = hex.EncodeToString(content.GetS())
+	}
+}
+
+// recordSession records the message and the session ID that the party signs with
+func (tr *transcriptRecorder) recordSession(message, ssid []byte) {
+	if tr == nil {
+		return
+	}
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	tr.transcript.Message = hex.EncodeToString(message)
+	tr.transcript.SSID = hex.EncodeToString(ssid)
+}
+
+// recordSecrets records ri and wi, unless the transcript is recorded without secrets
+func (tr *transcriptRecorder) recordSecrets(ri, wi *big.Int) {
+	if tr == nil || !tr.withSecrets || ri == nil || wi == nil {
+		return
+	}
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	tr.transcript.Secrets = &TranscriptSecrets{Ri: hex.EncodeToString(ri.Bytes()), Wi: hex.EncodeToString(wi.Bytes())}
+}
+
+// recordR records the encoded aggregated nonce commitment
+func (tr *transcriptRecorder) recordR(encodedR *[32]byte) {
+	if tr == nil {
+		return
+	}
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	tr.transcript.R = hex.EncodeToString(encodedR[:])
+}
+
+// recordS records the sum of the signature shares
+func (tr *transcriptRecorder) recordS(s *big.Int) {
+	if tr == nil {
+		return
+	}
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	tr.transcript.S = hex.EncodeToString(s.Bytes())
+}
+
+// fail records the first error of the party and dumps the transcript; later errors are ignored
+func (tr *transcriptRecorder) fail(err *tss.Error) {
+	if tr == nil || err == nil {
+		return
+	}
+	tr.mtx.Lock()
+	if tr.failed {
+		tr.mtx.Unlock()
+		return
+	}
+	tr.failed = true
+	tr.transcript.Round = err.Round()
+	tr.transcript.Error = err.Error()
+	for _, culprit := range err.Culprits() {
+		tr.transcript.Culprits = append(tr.transcript.Culprits, culprit.String())
+	}
+	tr.mtx.Unlock()
+	if tr.dump != nil {
+		tr.dump(tr.snapshot())
+	}
+}
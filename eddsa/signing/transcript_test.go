@@ -0,0 +1,149 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestTranscriptDumpedOnFailure(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	culprit := signPIDs[1]
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	withSecrets, redacted := make(chan *Transcript, 1), make(chan *Transcript, 1)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		P := NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh).(*LocalParty)
+		switch i {
+		case 0:
+			P.RecordTranscript(func(tr *Transcript) { withSecrets <- tr }, true)
+		case 2:
+			P.RecordTranscript(func(tr *Transcript) { redacted <- tr }, false)
+		}
+		parties = append(parties, P)
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	tamper := func(msg tss.Message) tss.Message {
+		if msg.GetFrom().Index != culprit.Index || msg.Type() != "binance.tsslib.eddsa.signing.SignRound3Message" {
+			return msg
+		}
+		r3msg := msg.(tss.ParsedMessage).Content().(*SignRound3Message)
+		return NewSignRound3Message(culprit, new(big.Int).Add(r3msg.UnmarshalS(), big.NewInt(1)), r3msg.GetMessageHash())
+	}
+	if !assert.NotNil(t, routeMessages(parties, outCh, errCh, nil, tamper), "signing must abort") {
+		return
+	}
+
+	for name, dumps := range map[string]chan *Transcript{"with secrets": withSecrets, "redacted": redacted} {
+		var tr *Transcript
+		select {
+		case tr = <-dumps:
+		case <-time.After(time.Minute):
+			assert.FailNow(t, "timed out waiting for the transcript", name)
+		}
+		assert.Equal(t, 4, tr.Round, name)
+		assert.Contains(t, tr.Error, "signature share", name)
+		assert.Equal(t, []string{culprit.String()}, tr.Culprits, name)
+		assert.Equal(t, hex.EncodeToString(big.NewInt(42).Bytes()), tr.Message, name)
+		assert.NotEmpty(t, tr.SSID, name)
+		assert.Len(t, tr.R, 64, name)
+		assert.Empty(t, tr.S, "the shares are checked before they are summed")
+		if assert.Len(t, tr.Signers, len(signPIDs), name) {
+			for j, signer := range tr.Signers {
+				assert.Equal(t, signPIDs[j].String(), signer.ID, name)
+				assert.NotEmpty(t, signer.Commitment, name)
+				assert.NotEmpty(t, signer.DeCommitment, name)
+				assert.NotEmpty(t, signer.ProofAlphaX, name)
+				assert.NotEmpty(t, signer.ProofAlphaY, name)
+				assert.NotEmpty(t, signer.ProofT, name)
+				assert.NotEmpty(t, signer.SignatureShare, name)
+			}
+		}
+
+		bz, err := json.Marshal(tr)
+		assert.NoError(t, err)
+		for _, field := range []string{`"commitment"`, `"de_commitment"`, `"proof_t"`, `"r"`, `"signature_share"`, `"culprits"`} {
+			assert.Contains(t, string(bz), field, name)
+		}
+		if name == "redacted" {
+			assert.Nil(t, tr.Secrets)
+			assert.NotContains(t, string(bz), `"secrets"`)
+		} else if assert.NotNil(t, tr.Secrets) {
+			assert.NotEmpty(t, tr.Secrets.Ri)
+			assert.NotEmpty(t, tr.Secrets.Wi)
+			assert.Contains(t, string(bz), `"ri"`)
+		}
+	}
+}
+
+func TestTranscriptOfSuccessfulSession(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh))
+	}
+	P := parties[0].(*LocalParty)
+	assert.Nil(t, P.Transcript(), "nothing is recorded by default")
+	P.RecordTranscript(func(*Transcript) { assert.Fail(t, "a successful session must not be dumped") }, false)
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			assert.FailNow(t, err.Error())
+		}
+	}
+	var data *common.SignatureData
+	done := make(chan struct{})
+	go func() {
+		for range signPIDs {
+			data = <-endCh
+		}
+		close(done)
+	}()
+	if err := routeMessages(parties, outCh, errCh, done, nil); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	tr := P.Transcript()
+	assert.Zero(t, tr.Round)
+	assert.Empty(t, tr.Error)
+	assert.Equal(t, hex.EncodeToString(data.Signature[:32]), tr.R)
+	assert.Equal(t, hex.EncodeToString(data.S), tr.S)
+	for j, signer := range tr.Signers {
+		s := parties[j].(*LocalParty).temp.signRound3Messages[j].Content().(*SignRound3Message).GetS()
+		assert.Equal(t, hex.EncodeToString(s), signer.SignatureShare)
+	}
+}
@@ -7,65 +7,36 @@
 package signing
 
 import (
-	"crypto/elliptic"
-	"io"
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/agl/ed25519/edwards25519"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
+// encodedBytesToBigInt reads the little-endian Ed25519 encoding s, see crypto.ScalarFromLE32
 func encodedBytesToBigInt(s *[32]byte) *big.Int {
-	// Use a copy so we don't screw up our original
-	// memory.
-	sCopy := new([32]byte)
-	for i := 0; i < 32; i++ {
-		sCopy[i] = s[i]
-	}
-	reverse(sCopy)
-
-	bi := new(big.Int).SetBytes(sCopy[:])
-
-	return bi
+	return crypto.ScalarFromLE32(s)
 }
 
+// wipe zeroes a buffer that held a secret. It is a variable so that tests can observe the wiped buffers.
+var wipe = common.Zeroize
+
+// bigIntToEncodedBytes returns the little-endian Ed25519 encoding of a, see crypto.ScalarToLE32, or zeros for a nil
+// a. It panics if a is negative or longer than 32 bytes, which the callers rule out, rather than truncating it.
 func bigIntToEncodedBytes(a *big.Int) *[32]byte {
-	s := new([32]byte)
 	if a == nil {
-		return s
-	}
-
-	// Caveat: a can be longer than 32 bytes.
-	s = copyBytes(a.Bytes())
-
-	// Reverse the byte string --> little endian after
-	// encoding.
-	reverse(s)
-
-	return s
-}
-
-func copyBytes(aB []byte) *[32]byte {
-	if aB == nil {
-		return nil
-	}
-	s := new([32]byte)
-
-	// If we have a short byte string, expand
-	// it so that it's long enough.
-	aBLen := len(aB)
-	if aBLen < 32 {
-		diff := 32 - aBLen
-		for i := 0; i < diff; i++ {
-			aB = append([]byte{0x00}, aB...)
-		}
+		return new([32]byte)
 	}
-
-	for i := 0; i < 32; i++ {
-		s[i] = aB[i]
+	s, err := crypto.ScalarToLE32(a, nil)
+	if err != nil {
+		panic(err)
 	}
-
 	return s
 }
 
@@ -85,45 +56,63 @@ func ecPointToEncodedBytes(x *big.Int, y *big.Int) *[32]byte {
 	return s
 }
 
-func reverse(s *[32]byte) {
-	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
-		s[i], s[j] = s[j], s[i]
+// messageToInput returns the bytes of the message m that are hashed into the challenge. With a fullBytesLen of 0 they
+// are the minimal big-endian encoding m.Bytes(), so leading zero bytes are dropped; otherwise m is big-endian and
+// left-padded with zeros to exactly fullBytesLen bytes, so that a message with leading zeros is signed in full. It
+// fails if m is nil or negative, if fullBytesLen is negative, or if m does not fit in fullBytesLen bytes.
+func messageToInput(m *big.Int, fullBytesLen int) ([]byte, error) {
+	switch {
+	case m == nil:
+		return nil, errors.New("the message to sign is nil")
+	case m.Sign() < 0:
+		return nil, errors.New("the message to sign is negative")
+	case fullBytesLen < 0:
+		return nil, fmt.Errorf("the full length of the message is negative (%d)", fullBytesLen)
+	case fullBytesLen == 0:
+		return m.Bytes(), nil
+	case m.BitLen() > 8*fullBytesLen:
+		return nil, fmt.Errorf("the message to sign does not fit in %d bytes", fullBytesLen)
 	}
+	mBytes := make([]byte, fullBytesLen)
+	m.FillBytes(mBytes)
+	return mBytes, nil
 }
 
-func addExtendedElements(p, q edwards25519.ExtendedGroupElement) edwards25519.ExtendedGroupElement {
-	var r edwards25519.CompletedGroupElement
-	var qCached edwards25519.CachedGroupElement
-	q.ToCached(&qCached)
-	edwards25519.GeAdd(&r, &p, &qCached)
-	var result edwards25519.ExtendedGroupElement
-	r.ToExtended(&result)
-	return result
+// deCommittedRjLen returns the number of values that the de-commitment of a nonce point holds: its coordinates or
+// its compressed encoding, depending on Parameters.CompressedCommitments
+func deCommittedRjLen(params *tss.Parameters) int {
+	if params.CompressedCommitments() {
+		return 1
+	}
+	return 2
 }
 
-func ecPointToExtendedElement(ec elliptic.Curve, x *big.Int, y *big.Int, rand io.Reader) edwards25519.ExtendedGroupElement {
-	encodedXBytes := bigIntToEncodedBytes(x)
-	encodedYBytes := bigIntToEncodedBytes(y)
-
-	z := common.GetRandomPositiveInt(rand, ec.Params().N)
-	encodedZBytes := bigIntToEncodedBytes(z)
-
-	var fx, fy, fxy edwards25519.FieldElement
-	edwards25519.FeFromBytes(&fx, encodedXBytes)
-	edwards25519.FeFromBytes(&fy, encodedYBytes)
-
-	var X, Y, Z, T edwards25519.FieldElement
-	edwards25519.FeFromBytes(&Z, encodedZBytes)
+// deCommittedRjBound returns the bound of the values that the de-commitment of a nonce point holds: the field prime
+// for coordinates and 2^256 for a 32-byte compressed encoding
+func deCommittedRjBound(params *tss.Parameters) *big.Int {
+	if params.CompressedCommitments() {
+		return new(big.Int).Lsh(big.NewInt(1), 256)
+	}
+	return params.EC().Params().P
+}
 
-	edwards25519.FeMul(&X, &fx, &Z)
-	edwards25519.FeMul(&Y, &fy, &Z)
-	edwards25519.FeMul(&fxy, &fx, &fy)
-	edwards25519.FeMul(&T, &fxy, &Z)
+// commitmentHasher returns the hash function of the commitments to the nonce points, see
+// Parameters.PoseidonCommitments
+func commitmentHasher(params *tss.Parameters) commitments.Hasher {
+	if params.PoseidonCommitments() {
+		return commitments.PoseidonHasher
+	}
+	return commitments.SHA512_256Hasher
+}
 
-	return edwards25519.ExtendedGroupElement{
-		X: X,
-		Y: Y,
-		Z: Z,
-		T: T,
+func deCommittedRj(params *tss.Parameters, values []*big.Int) (*crypto.ECPoint, error) {
+	if !params.CompressedCommitments() {
+		return crypto.NewECPoint(params.EC(), values[0], values[1])
+	}
+	if values[0].BitLen() > 256 {
+		return nil, errors.New("the compressed point is longer than 32 bytes")
 	}
+	encoded := make([]byte, 32)
+	values[0].FillBytes(encoded)
+	return crypto.ECPointFromCompressed(params.EC(), encoded)
 }
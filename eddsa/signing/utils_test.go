@@ -0,0 +1,53 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageToInput(t *testing.T) {
+	// a 32-byte digest whose first byte is zero, i.e. a 31-byte integer
+	digest := bytes.Repeat([]byte{0xab}, 32)
+	digest[0] = 0x00
+	m := new(big.Int).SetBytes(digest)
+
+	minimal, err := messageToInput(m, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, digest[1:], minimal, "without a full length the leading zero byte is dropped")
+
+	full, err := messageToInput(m, 32)
+	assert.NoError(t, err)
+	assert.Equal(t, digest, full, "with a full length of 32 the digest is kept as it is")
+
+	// a short message is left-padded
+	padded, err := messageToInput(big.NewInt(0x2a17), 6)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0x2a, 0x17}, padded)
+
+	empty, err := messageToInput(big.NewInt(0), 0)
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+	zeros, err := messageToInput(big.NewInt(0), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, make([]byte, 4), zeros)
+
+	_, err = messageToInput(m, 31)
+	assert.NoError(t, err, "the 31-byte integer fits in 31 bytes")
+	_, err = messageToInput(m, 30)
+	assert.Error(t, err, "a message longer than its full length is rejected")
+	_, err = messageToInput(nil, 0)
+	assert.Error(t, err)
+	_, err = messageToInput(big.NewInt(-1), 0)
+	assert.Error(t, err)
+	_, err = messageToInput(m, -1)
+	assert.Error(t, err)
+}
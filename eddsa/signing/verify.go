@@ -0,0 +1,77 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Verify checks a signature produced by the signing parties against the group public key `pub`. r and s are the R and
+// S of the SignatureData, and `m` is the signed message, padded to `fullBytesLen` bytes when the parties were given
+// one. It recomputes the challenge lambda = SHA-512(R || A || M) mod q exactly as round 3 does and checks that
+// s*G == R + lambda*A. s must be below q and R must be a canonical point encoding.
+func Verify(pub *crypto.ECPoint, m *big.Int, r, s *big.Int, fullBytesLen ...int) bool {
+	ec := tss.Edwards()
+	q := ec.Params().N
+	if pub == nil || !pub.ValidateBasic() || !tss.SameCurve(pub.Curve(), ec) {
+		return false
+	}
+	if r == nil || r.Sign() < 0 || r.BitLen() > 256 {
+		return false
+	}
+	if s == nil || s.Sign() < 0 || s.Cmp(q) >= 0 {
+		return false
+	}
+	n := 0
+	if len(fullBytesLen) > 0 && fullBytesLen[0] > 0 {
+		n = fullBytesLen[0]
+	}
+	mBytes, err := messageToInput(m, n)
+	if err != nil {
+		return false
+	}
+
+	encodedR := bigIntToEncodedBytes(r)
+	parsedR, err := edwards.ParsePubKey(encodedR[:])
+	if err != nil || !bytes.Equal(ecPointToEncodedBytes(parsedR.X, parsedR.Y)[:], encodedR[:]) {
+		return false
+	}
+	R, err := crypto.NewECPoint(ec, parsedR.X, parsedR.Y)
+	if err != nil {
+		return false
+	}
+
+	lambda := challenge(encodedR, pub, mBytes)
+	expected, err := R.Add(pub.ScalarMult(lambda))
+	if err != nil {
+		return false
+	}
+	return crypto.ScalarBaseMult(ec, s).Equal(expected)
+}
+
+// challenge returns lambda = SHA-512(R || A || M), read as a little-endian integer and reduced mod q, as computed in
+// round 3
+func challenge(encodedR *[32]byte, pub *crypto.ECPoint, m []byte) *big.Int {
+	encodedPubKey := ecPointToEncodedBytes(pub.X(), pub.Y())
+	h := sha512.New()
+	h.Write(encodedR[:])
+	h.Write(encodedPubKey[:])
+	h.Write(m)
+	digest := h.Sum(nil)
+	for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+		digest[i], digest[j] = digest[j], digest[i]
+	}
+	lambda := new(big.Int).SetBytes(digest)
+	return lambda.Mod(lambda, pub.Curve().Params().N)
+}
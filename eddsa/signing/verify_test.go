@@ -0,0 +1,48 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestE2EVerify(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	pub := keys[0].EDDSAPub
+	// a message with leading zero bytes, signed at its full length
+	msg := []byte{0x00, 0x00, 0x2a, 0x17}
+	m := new(big.Int).SetBytes(msg)
+	_, data, tssErr := signMessage(keys, signPIDs, m, nil, len(msg))
+	if tssErr != nil {
+		assert.FailNow(t, tssErr.Error())
+	}
+	r, s := new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)
+	assert.True(t, Verify(pub, m, r, s, len(msg)), "the signature must verify")
+
+	q := tss.Edwards().Params().N
+	one := big.NewInt(1)
+	assert.False(t, Verify(pub, m, r, s), "the message must be hashed at its full length")
+	assert.False(t, Verify(pub, new(big.Int).Add(m, one), r, s, len(msg)), "a tampered message must not verify")
+	assert.False(t, Verify(pub, m, r, new(big.Int).Add(s, one), len(msg)), "a tampered s must not verify")
+	assert.False(t, Verify(pub, m, r, new(big.Int).Add(s, q), len(msg)), "an unreduced s must be rejected")
+	assert.False(t, Verify(pub, m, new(big.Int).Add(r, one), s, len(msg)), "a tampered R must not verify")
+	other := crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(42))
+	assert.False(t, Verify(other, m, r, s, len(msg)), "another key must not verify")
+	assert.False(t, Verify(nil, m, r, s, len(msg)))
+	assert.False(t, Verify(pub, nil, r, s, len(msg)))
+	assert.False(t, Verify(pub, big.NewInt(1<<40), r, s, len(msg)), "a message longer than its full length is rejected")
+}
@@ -0,0 +1,150 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// runKeygen runs an EdDSA keygen between the parties and returns their save data, by the key of the party
+func runKeygen(t *testing.T, pIDs tss.SortedPartyIDs, threshold int) map[string]*keygen.LocalPartySaveData {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*keygen.LocalParty, 0, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), threshold)
+		parties = append(parties, keygen.NewLocalParty(params, outCh, endCh).(*keygen.LocalParty))
+	}
+	for _, P := range parties {
+		go func(P *keygen.LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	saves := make(map[string]*keygen.LocalPartySaveData, len(pIDs))
+	timeout := time.After(time.Minute)
+	for len(saves) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			if dest := msg.GetTo(); dest != nil {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				continue
+			}
+			for _, P := range parties {
+				if P.PartyID().Index != msg.GetFrom().Index {
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			}
+		case save := <-endCh:
+			saves[string(save.ShareID.Bytes())] = save
+		case <-timeout:
+			assert.FailNow(t, "timed out waiting for keygen")
+		}
+	}
+	return saves
+}
+
+func TestE2EWeightedParties(t *testing.T) {
+	setUp("info")
+
+	// the first party holds two shares, and any parties of a total weight of 3 can sign
+	parties := tss.GenerateTestPartyIDs(4)
+	weighted, err := tss.NewWeightedPartyIDs(parties, []int{2, 1, 1, 1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	const thresholdWeight = 3
+	assert.Len(t, weighted.IDs, 5)
+	assert.Len(t, weighted.SharesOf(parties[0]), 2)
+	for _, share := range weighted.IDs {
+		assert.NotNil(t, weighted.Owner(share))
+	}
+	saves := runKeygen(t, weighted.IDs, thresholdWeight-1)
+
+	for _, signers := range []tss.SortedPartyIDs{
+		{parties[0], parties[3]},
+		{parties[1], parties[2], parties[3]},
+	} {
+		signPIDs, weight, err := weighted.Signers(signers...)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, thresholdWeight, weight)
+		keys := make([]keygen.LocalPartySaveData, len(signPIDs))
+		for i, share := range signPIDs {
+			keys[i] = *saves[string(share.Key)]
+		}
+		msg := big.NewInt(42)
+		_, data, tssErr := signMessageWithThreshold(keys, signPIDs, msg, thresholdWeight-1)
+		if tssErr != nil {
+			assert.FailNow(t, tssErr.Error())
+		}
+		pk := edwards.PublicKey{Curve: tss.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+		sig, err := edwards.ParseSignature(data.Signature)
+		assert.NoError(t, err)
+		assert.True(t, edwards.Verify(&pk, msg.Bytes(), sig.R, sig.S), "eddsa verify must pass")
+	}
+
+	// two parties of weight 1 fall short of the threshold weight
+	signPIDs, weight, err := weighted.Signers(parties[1], parties[2])
+	assert.NoError(t, err)
+	assert.Equal(t, 2, weight)
+	keys := []keygen.LocalPartySaveData{*saves[string(signPIDs[0].Key)], *saves[string(signPIDs[1].Key)]}
+	_, _, tssErr := signMessageWithThreshold(keys, signPIDs, big.NewInt(42), thresholdWeight-1)
+	assert.NotNil(t, tssErr)
+
+	_, _, err = weighted.Signers(parties[0], parties[0])
+	assert.Error(t, err)
+	_, err = tss.NewWeightedPartyIDs(parties, []int{2, 1, 0, 1})
+	assert.Error(t, err)
+}
+
+// signMessageWithThreshold is signMessage for a session with another threshold than testThreshold
+func signMessageWithThreshold(keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, msg *big.Int,
+	threshold int) ([]tss.Party, *common.SignatureData, *tss.Error) {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); err != nil {
+			return parties, nil, err
+		}
+	}
+	var data *common.SignatureData
+	done := make(chan struct{})
+	go func() {
+		for range signPIDs {
+			data = <-endCh
+		}
+		close(done)
+	}()
+	if err := routeMessages(parties, outCh, errCh, done, nil); err != nil {
+		return parties, nil, err
+	}
+	return parties, data, nil
+}
@@ -0,0 +1,45 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/poseidon"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// ExportChallengeWitness returns the inputs and the output of the Poseidon challenge of a BabyJubJub EdDSA signature
+// with the nonce point R under the public key `pub` of the message m, as the decimal field element strings that a
+// circom witness takes. The inputs are R.x, R.y, pub.x, pub.y and m, and lambda is their poseidon.HashFieldElements,
+// i.e. the hm of circomlib's EdDSAPoseidonVerifier and of iden3's SignPoseidon, for which S*G == R + 8*lambda*pub.
+//
+// The signing rounds compute the SHA-512 challenge of RFC 8032 instead, so this only applies to signatures that are
+// made with the Poseidon challenge, e.g. by a single BabyJubJub key.
+func ExportChallengeWitness(R, pub *crypto.ECPoint, m *big.Int) (inputs []string, lambda string, err error) {
+	if R == nil || pub == nil || m == nil {
+		return nil, "", errors.New("ExportChallengeWitness() received a nil value")
+	}
+	if !tss.SameCurve(R.Curve(), tss.BabyJubJub()) || !tss.SameCurve(pub.Curve(), tss.BabyJubJub()) {
+		return nil, "", errors.New("ExportChallengeWitness() received a point that is not on BabyJubJub")
+	}
+	if m.Sign() < 0 || m.Cmp(poseidon.FieldOrder()) >= 0 {
+		return nil, "", errors.New("ExportChallengeWitness() received a message that is not a field element")
+	}
+	elems := []*big.Int{R.X(), R.Y(), pub.X(), pub.Y(), m}
+	hash, err := poseidon.HashFieldElements(elems)
+	if err != nil {
+		return nil, "", err
+	}
+	inputs = make([]string, len(elems))
+	for i, e := range elems {
+		inputs[i] = e.String()
+	}
+	return inputs, hash.String(), nil
+}
@@ -0,0 +1,59 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/poseidon"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestExportChallengeWitness(t *testing.T) {
+	ec := tss.BabyJubJub()
+	sk := babyjub.NewRandPrivKey()
+	A := sk.Public()
+	m := big.NewInt(42)
+	sig := sk.SignPoseidon(m)
+	R, err := crypto.NewECPoint(ec, sig.R8.X, sig.R8.Y)
+	assert.NoError(t, err)
+	pub, err := crypto.NewECPoint(ec, A.X, A.Y)
+	assert.NoError(t, err)
+
+	inputs, lambda, err := ExportChallengeWitness(R, pub, m)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{sig.R8.X.String(), sig.R8.Y.String(), A.X.String(), A.Y.String(), "42"}, inputs)
+
+	// the exported lambda is the challenge the signature was made with: S*G == R + 8*lambda*A
+	hm, ok := new(big.Int).SetString(lambda, 10)
+	if !assert.True(t, ok) {
+		return
+	}
+	expected, err := poseidon.HashFieldElements([]*big.Int{sig.R8.X, sig.R8.Y, A.X, A.Y, m})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hm)
+	right, err := R.Add(pub.ScalarMult(new(big.Int).Lsh(hm, 3)))
+	assert.NoError(t, err)
+	assert.True(t, crypto.ScalarBaseMult(ec, sig.S).Equals(right))
+	assert.True(t, A.VerifyPoseidon(m, sig))
+
+	_, _, err = ExportChallengeWitness(R, pub, poseidon.FieldOrder())
+	assert.Error(t, err, "a message outside of the field")
+	_, _, err = ExportChallengeWitness(R, pub, big.NewInt(-1))
+	assert.Error(t, err)
+	_, _, err = ExportChallengeWitness(crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(2)), pub, m)
+	assert.Error(t, err, "a point on another curve")
+	_, _, err = ExportChallengeWitness(R, nil, m)
+	assert.Error(t, err)
+}
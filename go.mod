@@ -9,7 +9,7 @@ require (
 	github.com/btcsuite/btcutil v1.0.2
 	github.com/decred/dcrd/dcrec/edwards/v2 v2.0.3
 	github.com/hashicorp/go-multierror v1.1.1
-	github.com/iden3/go-iden3-crypto v0.0.17 // indirect
+	github.com/iden3/go-iden3-crypto v0.0.17
 	github.com/ipfs/go-log v1.0.5
 	github.com/otiai10/primes v0.0.0-20210501021515-f1b2be525a11
 	github.com/pkg/errors v0.9.1
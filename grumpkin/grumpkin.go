@@ -0,0 +1,205 @@
+package grumpkin
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// GrumpkinCurve provides an implementation of Grumpkin, y^2 = x^3 - 17 over the scalar field of BN254, that fits the
+// ECC Curve interface from crypto/elliptic. Grumpkin and BN254 form a cycle: the base field of each is the scalar
+// field of the other, so a proof about Grumpkin points can be checked in a BN254 circuit. Its group has prime order,
+// so the cofactor is 1.
+//
+// The methods of elliptic.CurveParams assume a = -3 and must not be used; every method of the interface is
+// implemented here for a = 0. As with the curves of crypto/elliptic, (0, 0) stands for the point at infinity, which
+// is not on the curve.
+type GrumpkinCurve struct {
+	*elliptic.CurveParams
+}
+
+// grumpkin is the global instance of the Grumpkin curve
+var grumpkin = func() *GrumpkinCurve {
+	p := fromDecimal("21888242871839275222246405745257275088548364400416034343698204186575808495617")
+	return &GrumpkinCurve{
+		CurveParams: &elliptic.CurveParams{
+			P: p,
+			// N is the base field prime of BN254
+			N: fromDecimal("21888242871839275222246405745257275088696311157297823662689037894645226208583"),
+			// B = -17
+			B:       new(big.Int).Sub(p, big.NewInt(17)),
+			Gx:      big.NewInt(1),
+			Gy:      fromDecimal("17631683881184975370165255887551781615748388533673675138860"),
+			BitSize: 254,
+			Name:    "grumpkin",
+		},
+	}
+}()
+
+// Grumpkin returns a reference to the Grumpkin curve.
+func Grumpkin() *GrumpkinCurve {
+	return grumpkin
+}
+
+// Params returns the parameters for the curve.
+//
+// This is part of the elliptic.Curve interface implementation.
+func (curve *GrumpkinCurve) Params() *elliptic.CurveParams {
+	return curve.CurveParams
+}
+
+// IsOnCurve reports whether (x, y) is a point of the curve with reduced coordinates.
+//
+// This is part of the elliptic.Curve interface implementation.
+func (curve *GrumpkinCurve) IsOnCurve(x, y *big.Int) bool {
+	P := curve.P
+	if x == nil || y == nil || x.Sign() < 0 || x.Cmp(P) >= 0 || y.Sign() < 0 || y.Cmp(P) >= 0 {
+		return false
+	}
+	// y^2 == x^3 + B
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, P)
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+	x3.Add(x3, curve.B)
+	x3.Mod(x3, P)
+	return y2.Cmp(x3) == 0
+}
+
+func (curve *GrumpkinCurve) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	return curve.affine(curve.add(curve.jacobian(x1, y1), curve.jacobian(x2, y2)))
+}
+
+func (curve *GrumpkinCurve) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	return curve.affine(curve.double(curve.jacobian(x1, y1)))
+}
+
+func (curve *GrumpkinCurve) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	base := curve.jacobian(x1, y1)
+	acc := &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)} // the point at infinity
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			acc = curve.double(acc)
+			if b>>uint(bit)&1 == 1 {
+				acc = curve.add(acc, base)
+			}
+		}
+	}
+	return curve.affine(acc)
+}
+
+func (curve *GrumpkinCurve) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	return curve.ScalarMult(curve.Gx, curve.Gy, k)
+}
+
+// ----- //
+
+// jacobianPoint is (X, Y, Z) for the affine point (X/Z^2, Y/Z^3); Z = 0 is the point at infinity
+type jacobianPoint struct {
+	x, y, z *big.Int
+}
+
+func (curve *GrumpkinCurve) jacobian(x, y *big.Int) *jacobianPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return &jacobianPoint{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+func (curve *GrumpkinCurve) affine(p *jacobianPoint) (x, y *big.Int) {
+	if p.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	P := curve.P
+	zInv := new(big.Int).ModInverse(p.z, P)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+	x = new(big.Int).Mul(p.x, zInv2)
+	x.Mod(x, P)
+	y = new(big.Int).Mul(p.y, zInv2.Mul(zInv2, zInv))
+	y.Mod(y, P)
+	return x, y
+}
+
+// double is dbl-2009-l for a = 0
+func (curve *GrumpkinCurve) double(p *jacobianPoint) *jacobianPoint {
+	P := curve.P
+	if p.z.Sign() == 0 || p.y.Sign() == 0 {
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, P) }
+	A := mod(new(big.Int).Mul(p.x, p.x))
+	B := mod(new(big.Int).Mul(p.y, p.y))
+	C := mod(new(big.Int).Mul(B, B))
+	// D = 2*((X1+B)^2-A-C)
+	D := new(big.Int).Add(p.x, B)
+	D.Mul(D, D)
+	D.Sub(D, A)
+	D.Sub(D, C)
+	D = mod(D.Lsh(D, 1))
+	E := mod(new(big.Int).Mul(A, big.NewInt(3)))
+	F := mod(new(big.Int).Mul(E, E))
+	// X3 = F-2*D, Y3 = E*(D-X3)-8*C, Z3 = 2*Y1*Z1
+	x3 := mod(new(big.Int).Sub(F, new(big.Int).Lsh(D, 1)))
+	y3 := new(big.Int).Sub(D, x3)
+	y3.Mul(y3, E)
+	y3 = mod(y3.Sub(y3, new(big.Int).Lsh(C, 3)))
+	z3 := new(big.Int).Mul(p.y, p.z)
+	z3 = mod(z3.Lsh(z3, 1))
+	return &jacobianPoint{x3, y3, z3}
+}
+
+// add is add-2007-bl, which doubles when both points are the same
+func (curve *GrumpkinCurve) add(p1, p2 *jacobianPoint) *jacobianPoint {
+	if p1.z.Sign() == 0 {
+		return p2
+	}
+	if p2.z.Sign() == 0 {
+		return p1
+	}
+	P := curve.P
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, P) }
+	z1z1 := mod(new(big.Int).Mul(p1.z, p1.z))
+	z2z2 := mod(new(big.Int).Mul(p2.z, p2.z))
+	u1 := mod(new(big.Int).Mul(p1.x, z2z2))
+	u2 := mod(new(big.Int).Mul(p2.x, z1z1))
+	s1 := new(big.Int).Mul(p1.y, p2.z)
+	s1 = mod(s1.Mul(s1, z2z2))
+	s2 := new(big.Int).Mul(p2.y, p1.z)
+	s2 = mod(s2.Mul(s2, z1z1))
+	h := mod(new(big.Int).Sub(u2, u1))
+	r := mod(new(big.Int).Sub(s2, s1))
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return curve.double(p1)
+		}
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	r.Lsh(r, 1)
+	// I = (2*H)^2, J = H*I, V = U1*I
+	i := new(big.Int).Lsh(h, 1)
+	i = mod(i.Mul(i, i))
+	j := mod(new(big.Int).Mul(h, i))
+	v := mod(new(big.Int).Mul(u1, i))
+	// X3 = r^2-J-2*V, Y3 = r*(V-X3)-2*S1*J, Z3 = ((Z1+Z2)^2-Z1Z1-Z2Z2)*H
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j)
+	x3 = mod(x3.Sub(x3, new(big.Int).Lsh(v, 1)))
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r)
+	s1j := new(big.Int).Mul(s1, j)
+	y3 = mod(y3.Sub(y3, s1j.Lsh(s1j, 1)))
+	z3 := new(big.Int).Add(p1.z, p2.z)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	z3.Sub(z3, z2z2)
+	z3 = mod(z3.Mul(z3, h))
+	return &jacobianPoint{x3, y3, z3}
+}
+
+// fromDecimal is new(big.Int).SetString for the hard-coded constants; it panics on a malformed one
+func fromDecimal(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid decimal in source file: " + s)
+	}
+	return v
+}
@@ -8,25 +8,68 @@ package tss
 
 import (
 	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
 	"errors"
+	"hash"
 	"reflect"
 
 	"github.com/bnb-chain/tss-lib/v2/babyjubjub"
+	"github.com/bnb-chain/tss-lib/v2/grumpkin"
 	s256k1 "github.com/btcsuite/btcd/btcec/v2"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"golang.org/x/crypto/sha3"
 )
 
 type CurveName string
 
+// CurveID is the one-byte identifier of a curve in the self-describing encoding of a point, see
+// crypto.ECPoint.MarshalWithCurveID. The identifiers are part of the encoding and must never be reassigned.
+type CurveID byte
+
+// ChallengeHash selects the hash function that the Schnorr challenges of the proofs on a curve are computed with,
+// see RegisterCurve
+type ChallengeHash int
+
 const (
 	Secp256k1 CurveName = "secp256k1"
+	Secp256r1 CurveName = "secp256r1"
 	Ed25519   CurveName = "ed25519"
 	BabyJub   CurveName = "babyjubjub"
+	// GrumpkinBN254 is Grumpkin, the cycle partner of BN254
+	GrumpkinBN254 CurveName = "grumpkin"
+)
+
+const (
+	Secp256k1ID     CurveID = 1
+	Ed25519ID       CurveID = 2
+	BabyJubID       CurveID = 3
+	Secp256r1ID     CurveID = 4
+	GrumpkinBN254ID CurveID = 5
+)
+
+const (
+	// SHA512_256Challenge is the default: SHA512_256i_TAGGED
+	SHA512_256Challenge ChallengeHash = iota
+	SHA256Challenge
+	// Keccak256Challenge is the legacy Keccak-256 of Ethereum, not SHA3-256
+	Keccak256Challenge
 )
 
 var (
-	ec       elliptic.Curve
-	registry map[CurveName]elliptic.Curve
+	ec              elliptic.Curve
+	registry        map[CurveName]elliptic.Curve
+	aliases         map[CurveName]elliptic.Curve
+	challengeHashes map[CurveName]ChallengeHash
+
+	// curveIDs holds the CurveID of the registered curves that have one, by their registered name
+	curveIDs = map[CurveName]CurveID{
+		Secp256k1:     Secp256k1ID,
+		Ed25519:       Ed25519ID,
+		BabyJub:       BabyJubID,
+		Secp256r1:     Secp256r1ID,
+		GrumpkinBN254: GrumpkinBN254ID,
+	}
 )
 
 // Init default curve (secp256k1)
@@ -34,13 +77,54 @@ func init() {
 	ec = s256k1.S256()
 
 	registry = make(map[CurveName]elliptic.Curve)
+	aliases = make(map[CurveName]elliptic.Curve)
+	challengeHashes = make(map[CurveName]ChallengeHash)
 	registry[Secp256k1] = s256k1.S256()
+	registry[Secp256r1] = elliptic.P256()
 	registry[Ed25519] = edwards.Edwards()
 	registry[BabyJub] = babyjubjub.BabyJubJub()
+	registry[GrumpkinBN254] = grumpkin.Grumpkin()
 }
 
-func RegisterCurve(name CurveName, curve elliptic.Curve) {
-	registry[name] = curve
+// RegisterCurve makes `curve` known under `name`. If the curve is already registered under another name (e.g.
+// elliptic.P256() as secp256r1), `name` becomes an alias: GetCurveByName accepts it, but GetCurveName and so the
+// serialized points keep using the first name.
+//
+// An optional ChallengeHash selects the hash of the Schnorr challenges on the curve, under either name; without one
+// the curve keeps its current selection, SHA512_256Challenge unless it was changed. The selection applies to the
+// proofs made or verified after the call, and all the parties must select the same one.
+func RegisterCurve(name CurveName, curve elliptic.Curve, challengeHash ...ChallengeHash) {
+	existing, ok := GetCurveName(curve)
+	if !ok || existing == name {
+		registry[name] = curve
+		existing = name
+	} else {
+		aliases[name] = curve
+	}
+	if len(challengeHash) > 0 {
+		challengeHashes[existing] = challengeHash[0]
+	}
+}
+
+// GetChallengeHash returns the hash of the Schnorr challenges on the curve, SHA512_256Challenge for the curves that
+// were not registered with another one
+func GetChallengeHash(curve elliptic.Curve) ChallengeHash {
+	if name, ok := GetCurveName(curve); ok {
+		return challengeHashes[name]
+	}
+	return SHA512_256Challenge
+}
+
+// New returns a new hash.Hash of the function, with 32-byte digests
+func (h ChallengeHash) New() hash.Hash {
+	switch h {
+	case SHA256Challenge:
+		return sha256.New()
+	case Keccak256Challenge:
+		return sha3.NewLegacyKeccak256()
+	default:
+		return sha512.New512_256()
+	}
 }
 
 // return curve, exist(bool)
@@ -48,14 +132,22 @@ func GetCurveByName(name CurveName) (elliptic.Curve, bool) {
 	if val, exist := registry[name]; exist {
 		return val, true
 	}
+	if val, exist := aliases[name]; exist {
+		return val, true
+	}
 
 	return nil, false
 }
 
 // return name, exist(bool)
+// A curve matches a registered one of the same type with the same parameters, so that curves sharing an
+// implementation type (e.g. the generic *elliptic.CurveParams) are told apart.
 func GetCurveName(curve elliptic.Curve) (CurveName, bool) {
+	if curve == nil {
+		return "", false
+	}
 	for name, e := range registry {
-		if reflect.TypeOf(curve) == reflect.TypeOf(e) {
+		if reflect.TypeOf(curve) == reflect.TypeOf(e) && sameParams(curve.Params(), e.Params()) {
 			return name, true
 		}
 	}
@@ -63,6 +155,31 @@ func GetCurveName(curve elliptic.Curve) (CurveName, bool) {
 	return "", false
 }
 
+// GetCurveID returns the CurveID of a registered curve, or false for a curve without one
+func GetCurveID(curve elliptic.Curve) (CurveID, bool) {
+	name, ok := GetCurveName(curve)
+	if !ok {
+		return 0, false
+	}
+	id, ok := curveIDs[name]
+	return id, ok
+}
+
+// GetCurveByID returns the curve of a CurveID, or false for an unknown one
+func GetCurveByID(id CurveID) (elliptic.Curve, bool) {
+	for name, curveID := range curveIDs {
+		if curveID == id {
+			return GetCurveByName(name)
+		}
+	}
+	return nil, false
+}
+
+func sameParams(lhs, rhs *elliptic.CurveParams) bool {
+	return lhs.Name == rhs.Name && lhs.N.Cmp(rhs.N) == 0 && lhs.P.Cmp(rhs.P) == 0 &&
+		lhs.Gx.Cmp(rhs.Gx) == 0 && lhs.Gy.Cmp(rhs.Gy) == 0
+}
+
 // SameCurve returns true if both lhs and rhs are the same known curve
 func SameCurve(lhs, rhs elliptic.Curve) bool {
 	lName, lOk := GetCurveName(lhs)
@@ -93,6 +210,11 @@ func S256() elliptic.Curve {
 	return s256k1.S256()
 }
 
+// secp256r1, also known as NIST P-256
+func P256() elliptic.Curve {
+	return elliptic.P256()
+}
+
 func Edwards() elliptic.Curve {
 	return edwards.Edwards()
 }
@@ -100,3 +222,8 @@ func Edwards() elliptic.Curve {
 func BabyJubJub() elliptic.Curve {
 	return babyjubjub.BabyJubJub()
 }
+
+// Grumpkin returns the Grumpkin curve, whose base field is the scalar field of BN254 and the other way around
+func Grumpkin() elliptic.Curve {
+	return grumpkin.Grumpkin()
+}
@@ -8,6 +8,8 @@ package tss
 
 import (
 	"fmt"
+
+	"google.golang.org/protobuf/proto"
 )
 
 // fundamental is an error that has a message and a stack, but no caller.
@@ -15,6 +17,7 @@ type Error struct {
 	cause    error
 	task     string
 	round    int
+	msgType  string
 	victim   *PartyID
 	culprits []*PartyID
 }
@@ -29,8 +32,19 @@ func (err *Error) Cause() error { return err.cause }
 
 func (err *Error) Task() string { return err.task }
 
+// Round returns the number of the round in which the error occurred
 func (err *Error) Round() int { return err.round }
 
+// MessageType returns the type of the message that caused the error, as returned by Message.Type, or "" if the error
+// was not caused by a particular kind of message
+func (err *Error) MessageType() string { return err.msgType }
+
+// WithMessageType records that the error was caused by a message with the given content and returns err
+func (err *Error) WithMessageType(content MessageContent) *Error {
+	err.msgType = string(proto.MessageName(content))
+	return err
+}
+
 func (err *Error) Victim() *PartyID { return err.victim }
 
 func (err *Error) Culprits() []*PartyID { return err.culprits }
@@ -0,0 +1,54 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"time"
+)
+
+type (
+	// RoundObserver is notified as the rounds of a party start and complete, e.g. to export metrics. It is set with
+	// Parameters.SetRoundObserver. The methods are called with the lock of the party held, so they must return quickly
+	// and must not call back into the party; an observer shared by several parties is called concurrently.
+	RoundObserver interface {
+		// OnRoundStart is called once the Start of a round has returned without an error, so the round has sent its
+		// messages
+		OnRoundStart(task string, round int)
+		// OnRoundComplete is called when a round can proceed, before the next round starts. `duration` runs from
+		// just before the Start of the round, and `messages` is the number of messages the party accepted while the
+		// round was current, which includes those stored early for a later round.
+		OnRoundComplete(task string, round int, duration time.Duration, messages int)
+	}
+
+	// roundObservation is the state that a BaseParty keeps for its RoundObserver
+	roundObservation struct {
+		started  time.Time
+		messages int
+	}
+)
+
+func observeRoundStarting(p Party) {
+	obs := p.observation()
+	obs.started, obs.messages = time.Now(), 0
+}
+
+func observeRoundStarted(p Party, task string) {
+	if o := p.round().Params().RoundObserver(); o != nil {
+		o.OnRoundStart(task, p.round().RoundNumber())
+	}
+}
+
+func observeMessage(p Party) {
+	p.observation().messages++
+}
+
+func observeRoundComplete(p Party, task string) {
+	if o := p.round().Params().RoundObserver(); o != nil {
+		obs := p.observation()
+		o.OnRoundComplete(task, p.round().RoundNumber(), time.Since(obs.started), obs.messages)
+	}
+}
@@ -9,9 +9,14 @@ package tss
 import (
 	"crypto/elliptic"
 	"crypto/rand"
+	"errors"
+	"fmt"
 	"io"
+	"math/big"
 	"runtime"
 	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
 )
 
 type (
@@ -28,8 +33,39 @@ type (
 		// for keygen
 		noProofMod bool
 		noProofFac bool
+		safePrimes SafePrimeSource
+		// for eddsa signing
+		compressedCommitments bool
+		poseidonCommitments   bool
+		prehashed             bool
+		nonceLog              NonceLog
+		nonceGenerator        NonceGenerator
+		abortOnInvalidMessage bool
 		// random sources
 		partialKeyRand, rand io.Reader
+		// metrics
+		roundObserver RoundObserver
+	}
+
+	// SafePrimeSource supplies validated safe primes, e.g. from a pool generated ahead of time, to the keygen
+	// pre-parameter generation instead of searching for them. A source must never hand out the same prime twice.
+	SafePrimeSource interface {
+		TakeSafePrimes(count, bitLen int) ([]*common.GermainSafePrime, error)
+	}
+
+	// NonceLog records the nonce commitments that a party has signed with, so that a nonce that was already used in
+	// an earlier session is caught before it signs again. RecordNonce must record `commitment` atomically and return
+	// ErrNonceReused if it was recorded before. A log can be backed by a persistent store; it must not be shared by
+	// parties with different key shares unless the commitments of all of them are meant to be unique.
+	NonceLog interface {
+		RecordNonce(commitment []byte) error
+	}
+
+	// NonceGenerator generates the secret signing nonce of EdDSA signing outside of the process, e.g. in an HSM or a
+	// KMS. GenerateNonce must return a uniformly random scalar in [1, q) that it has never returned before; the
+	// signing round checks the range and fails on any other value. The nonce is zeroized once it has been used.
+	NonceGenerator interface {
+		GenerateNonce(q *big.Int) (*big.Int, error)
 	}
 
 	ReSharingParameters struct {
@@ -44,6 +80,9 @@ const (
 	defaultSafePrimeGenTimeout = 5 * time.Minute
 )
 
+// ErrNonceReused is returned by a NonceLog for a nonce commitment that it has already recorded
+var ErrNonceReused = errors.New("the nonce commitment was already used in an earlier signing session")
+
 // Exported, used in `tss` client
 func NewParameters(ec elliptic.Curve, ctx *PeerContext, partyID *PartyID, partyCount, threshold int) *Parameters {
 	return &Parameters{
@@ -59,6 +98,84 @@ func NewParameters(ec elliptic.Curve, ctx *PeerContext, partyID *PartyID, partyC
 	}
 }
 
+// ValidateParties checks that the parameters describe a session that the protocol rounds can run: 1 <= threshold <
+// party count, the party count matches the peer context, and the peers carry the contiguous indices 0..n-1 in their
+// sorted order, as the rounds store the messages of party j at index j. This party must be one of the peers.
+func (params *Parameters) ValidateParties() error {
+	if params.parties == nil || params.partyID == nil {
+		return errors.New("the parameters have no peer context or no party ID")
+	}
+	ids := params.parties.IDs()
+	if params.partyCount != len(ids) {
+		return fmt.Errorf("the party count is %d but the peer context holds %d parties", params.partyCount, len(ids))
+	}
+	if params.threshold < 1 || params.threshold >= params.partyCount {
+		return fmt.Errorf("the threshold must satisfy 1 <= t < n, got t=%d and n=%d", params.threshold, params.partyCount)
+	}
+	keys := make(map[string]struct{}, len(ids))
+	for i, id := range ids {
+		if id == nil || !id.ValidateBasic() {
+			return fmt.Errorf("the party at position %d is invalid", i)
+		}
+		if id.Index != i {
+			return fmt.Errorf("the party at position %d has index %d; indices must be the contiguous 0..%d", i, id.Index, len(ids)-1)
+		}
+		key := id.KeyInt().String()
+		if _, ok := keys[key]; ok {
+			return fmt.Errorf("the party at position %d has the same key as another party", i)
+		}
+		keys[key] = struct{}{}
+	}
+	if i := params.partyID.Index; i < 0 || i >= len(ids) || ids[i].KeyInt().Cmp(params.partyID.KeyInt()) != 0 {
+		return fmt.Errorf("this party %s is not in the peer context at its index", params.partyID)
+	}
+	return nil
+}
+
+// WithoutParties returns a copy of the parameters for a new session of the parties that remain once the `culprits`
+// are excluded, e.g. to retry signing after a round has named the parties at fault. The remaining PartyIDs are copied
+// and sorted again with the contiguous indices 0..n-1, so those of the aborted session are left as they were; every
+// remaining party computes the same set. It fails when this party is a culprit or when fewer than threshold+1 parties
+// would remain.
+func (params *Parameters) WithoutParties(culprits ...*PartyID) (*Parameters, error) {
+	if params.parties == nil || params.partyID == nil {
+		return nil, errors.New("the parameters have no peer context or no party ID")
+	}
+	excluded := make(map[string]struct{}, len(culprits))
+	for _, culprit := range culprits {
+		if culprit != nil {
+			excluded[culprit.KeyInt().String()] = struct{}{}
+		}
+	}
+	if _, ok := excluded[params.partyID.KeyInt().String()]; ok {
+		return nil, fmt.Errorf("this party %s is one of the culprits", params.partyID)
+	}
+	remaining := make(UnSortedPartyIDs, 0, len(params.parties.IDs()))
+	var self *PartyID
+	for _, id := range params.parties.IDs() {
+		if _, ok := excluded[id.KeyInt().String()]; ok {
+			continue
+		}
+		cp := NewPartyID(id.Id, id.Moniker, id.KeyInt())
+		if id.KeyInt().Cmp(params.partyID.KeyInt()) == 0 {
+			self = cp
+		}
+		remaining = append(remaining, cp)
+	}
+	if self == nil {
+		return nil, fmt.Errorf("this party %s is not in the peer context", params.partyID)
+	}
+	if len(remaining) < params.threshold+1 {
+		return nil, fmt.Errorf("%d parties remain without the culprits, fewer than the threshold+1=%d needed",
+			len(remaining), params.threshold+1)
+	}
+	retry := *params
+	retry.parties = NewPeerContext(SortPartyIDs(remaining))
+	retry.partyID = self
+	retry.partyCount = len(remaining)
+	return &retry, nil
+}
+
 func (params *Parameters) EC() elliptic.Curve {
 	return params.ec
 }
@@ -112,10 +229,94 @@ func (params *Parameters) SetNoProofFac() {
 	params.noProofFac = true
 }
 
+// SafePrimeSource returns the source of the safe primes used when ECDSA keygen generates its pre-parameters, or nil
+// if they are searched for during keygen
+func (params *Parameters) SafePrimeSource() SafePrimeSource {
+	return params.safePrimes
+}
+
+// SetSafePrimeSource makes ECDSA keygen take the safe primes for its pre-parameters from `source`. It has no effect
+// when pre-parameters are passed to the party.
+func (params *Parameters) SetSafePrimeSource(source SafePrimeSource) {
+	params.safePrimes = source
+}
+
+// CompressedCommitments reports whether EdDSA signing commits to the nonce points in their 32-byte compressed encoding
+// rather than as (X, Y) coordinate pairs. All the signers must use the same setting.
+func (params *Parameters) CompressedCommitments() bool {
+	return params.compressedCommitments
+}
+
+func (params *Parameters) SetCompressedCommitments() {
+	params.compressedCommitments = true
+}
+
+// PoseidonCommitments reports whether EdDSA signing commits to the nonce points with Poseidon instead of SHA-512/256,
+// so that the commitments can be opened inside a SNARK circuit. All the signers must use the same setting.
+func (params *Parameters) PoseidonCommitments() bool {
+	return params.poseidonCommitments
+}
+
+func (params *Parameters) SetPoseidonCommitments() {
+	params.poseidonCommitments = true
+}
+
+// Prehashed reports whether the EdDSA signing message is a digest that the caller has already computed. The digest is
+// signed as an opaque big-endian byte string of its full length, 32 bytes unless the party is given another length,
+// so leading zero bytes are kept. It is signed as a plain Ed25519 message, not as Ed25519ph.
+func (params *Parameters) Prehashed() bool {
+	return params.prehashed
+}
+
+func (params *Parameters) SetPrehashed() {
+	params.prehashed = true
+}
+
+// NonceLog returns the log that EdDSA signing records this party's nonce commitment Ri in before it sends its signature
+// share, or nil
+func (params *Parameters) NonceLog() NonceLog {
+	return params.nonceLog
+}
+
+func (params *Parameters) SetNonceLog(log NonceLog) {
+	params.nonceLog = log
+}
+
+// NonceGenerator returns the generator that EdDSA signing takes this party's nonce ri from, or nil to draw it from Rand
+func (params *Parameters) NonceGenerator() NonceGenerator {
+	return params.nonceGenerator
+}
+
+func (params *Parameters) SetNonceGenerator(generator NonceGenerator) {
+	params.nonceGenerator = generator
+}
+
+// AbortOnInvalidMessage reports whether EdDSA signing fails with the sender as the culprit as soon as a round holds a
+// message that it cannot accept, e.g. a broadcast that was sent point-to-point, rather than waiting for the sender to
+// replace it
+func (params *Parameters) AbortOnInvalidMessage() bool {
+	return params.abortOnInvalidMessage
+}
+
+func (params *Parameters) SetAbortOnInvalidMessage() {
+	params.abortOnInvalidMessage = true
+}
+
+// RoundObserver returns the observer notified of the rounds of the party, or nil
+func (params *Parameters) RoundObserver() RoundObserver {
+	return params.roundObserver
+}
+
+func (params *Parameters) SetRoundObserver(observer RoundObserver) {
+	params.roundObserver = observer
+}
+
 func (params *Parameters) PartialKeyRand() io.Reader {
 	return params.partialKeyRand
 }
 
+// Rand returns the source of the randomness of the protocol rounds, crypto/rand.Reader by default. It must be a
+// cryptographically secure generator. The rounds panic if reading from it fails, as they cannot go on without it.
 func (params *Parameters) Rand() io.Reader {
 	return params.rand
 }
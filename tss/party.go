@@ -7,6 +7,7 @@
 package tss
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -36,12 +37,25 @@ type Party interface {
 	advance()
 	lock()
 	unlock()
+	setCtx(context.Context)
+	ctx() context.Context
+	observation() *roundObservation
+}
+
+// MessageCounter is implemented by the parties that can tell a coordinator how many messages each of their rounds
+// waits for, so that it can buffer them before driving Update. ExpectedMessages returns the number of broadcast and
+// p2p messages from the other parties that the party must receive while in `round`, or zeros for a round that does
+// not exist or receives none.
+type MessageCounter interface {
+	ExpectedMessages(round int) (broadcast, p2p int)
 }
 
 type BaseParty struct {
 	mtx        sync.Mutex
 	rnd        Round
 	FirstRound Round
+	context    context.Context // nil unless started with StartWithContext
+	obs        roundObservation
 }
 
 func (p *BaseParty) Running() bool {
@@ -73,7 +87,7 @@ func (p *BaseParty) ValidateMessage(msg ParsedMessage) (bool, *Error) {
 		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg))
 	}
 	if !msg.ValidateBasic() {
-		return false, p.WrapError(fmt.Errorf("message failed ValidateBasic: %s", msg), msg.GetFrom())
+		return false, p.WrapError(fmt.Errorf("message failed ValidateBasic: %s", msg), msg.GetFrom()).WithMessageType(msg.Content())
 	}
 	return true, nil
 }
@@ -113,6 +127,40 @@ func (p *BaseParty) unlock() {
 	p.mtx.Unlock()
 }
 
+func (p *BaseParty) setCtx(ctx context.Context) {
+	p.context = ctx
+}
+
+func (p *BaseParty) ctx() context.Context {
+	return p.context
+}
+
+func (p *BaseParty) observation() *roundObservation {
+	return &p.obs
+}
+
+// ----- //
+
+// StartWithContext starts the party like p.Start() and ties it to `ctx`. Once `ctx` is done the party stops at the
+// next safe checkpoint: before a round starts and whenever a message is delivered. The call that hits the checkpoint
+// returns an *Error whose Cause is ctx.Err(), e.g. context.Canceled, and no further rounds run.
+func StartWithContext(ctx context.Context, p Party) *Error {
+	p.lock()
+	p.setCtx(ctx)
+	p.unlock()
+	return p.Start()
+}
+
+// checkContext returns an error if the party's context is done. The caller must hold the party's lock.
+func checkContext(p Party) *Error {
+	if ctx := p.ctx(); ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return p.WrapError(err)
+		}
+	}
+	return nil
+}
+
 // ----- //
 
 func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
@@ -136,15 +184,28 @@ func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
 			return err
 		}
 	}
+	if err := checkContext(p); err != nil {
+		return err
+	}
 	common.Logger.Infof("party %s: %s round %d starting", p.round().Params().PartyID(), task, 1)
 	defer func() {
 		common.Logger.Debugf("party %s: %s round %d finished", p.round().Params().PartyID(), task, 1)
 	}()
-	return p.round().Start()
+	observeRoundStarting(p)
+	if err := p.round().Start(); err != nil {
+		return err
+	}
+	observeRoundStarted(p, task)
+	return nil
 }
 
 // an implementation of Update that is shared across the different types of parties (keygen, signing, dynamic groups)
 func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
+	return baseUpdate(p, msg, task, false)
+}
+
+// baseUpdate is BaseUpdate; `redelivered` is set when it runs again for the same message after a round advanced
+func baseUpdate(p Party, msg ParsedMessage, task string, redelivered bool) (ok bool, err *Error) {
 	// fast-fail on an invalid message; do not lock the mutex yet
 	if _, err := p.ValidateMessage(msg); err != nil {
 		return false, err
@@ -155,6 +216,9 @@ func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
 		return ok, err
 	}
 	p.lock() // data is written to P state below
+	if err := checkContext(p); err != nil {
+		return r(false, err)
+	}
 	common.Logger.Debugf("party %s received message: %s", p.PartyID(), msg.String())
 	if p.round() != nil {
 		common.Logger.Debugf("party %s round %d update: %s", p.PartyID(), p.round().RoundNumber(), msg.String())
@@ -162,24 +226,33 @@ func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
 	if ok, err := p.StoreMessage(msg); err != nil || !ok {
 		return r(false, err)
 	}
+	if !redelivered {
+		observeMessage(p)
+	}
 	if p.round() != nil {
 		common.Logger.Debugf("party %s: %s round %d update", p.round().Params().PartyID(), task, p.round().RoundNumber())
 		if _, err := p.round().Update(); err != nil {
 			return r(false, err)
 		}
 		if p.round().CanProceed() {
+			if err := checkContext(p); err != nil {
+				return r(false, err)
+			}
+			observeRoundComplete(p, task)
 			if p.advance(); p.round() != nil {
+				observeRoundStarting(p)
 				if err := p.round().Start(); err != nil {
 					return r(false, err)
 				}
+				observeRoundStarted(p, task)
 				rndNum := p.round().RoundNumber()
 				common.Logger.Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, rndNum)
 			} else {
 				// finished! the round implementation will have sent the data through the `end` channel.
 				common.Logger.Infof("party %s: %s finished!", p.PartyID(), task)
 			}
-			p.unlock()                      // recursive so can't defer after return
-			return BaseUpdate(p, msg, task) // re-run round update or finish)
+			p.unlock()                            // recursive so can't defer after return
+			return baseUpdate(p, msg, task, true) // re-run round update or finish)
 		}
 		return r(true, nil)
 	}
@@ -7,7 +7,10 @@
 package tss
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"math/big"
 	"sort"
@@ -78,6 +81,41 @@ func SortPartyIDs(ids UnSortedPartyIDs, startAt ...int) SortedPartyIDs {
 	return sorted
 }
 
+// SortPartyIDsBy sorts a list of []*PartyID in ascending byte order of a stable identifier that every node derives in
+// the same way, e.g. the parties' public keys or monikers, and assigns the party indexes like SortPartyIDs. The rounds
+// index the messages of the parties by this order, so all the nodes of a session must sort by the same identifier;
+// the result does not depend on the order of `ids`. It fails if an identifier is empty or shared by two parties, as
+// their order would then depend on the input.
+// Exported, used in `tss` client
+func SortPartyIDsBy(ids UnSortedPartyIDs, stableKey func(*PartyID) []byte, startAt ...int) (SortedPartyIDs, error) {
+	keys := make(map[string]struct{}, len(ids))
+	for i, id := range ids {
+		if id == nil {
+			return nil, fmt.Errorf("SortPartyIDsBy(): the party at position %d is nil", i)
+		}
+		key := stableKey(id)
+		if len(key) == 0 {
+			return nil, fmt.Errorf("SortPartyIDsBy(): the party %s has an empty identifier", id)
+		}
+		if _, ok := keys[string(key)]; ok {
+			return nil, fmt.Errorf("SortPartyIDsBy(): the party %s has the same identifier as another party", id)
+		}
+		keys[string(key)] = struct{}{}
+	}
+	sorted := append(SortedPartyIDs{}, ids...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return bytes.Compare(stableKey(sorted[a]), stableKey(sorted[b])) < 0
+	})
+	frm := 0
+	if len(startAt) > 0 {
+		frm = startAt[0]
+	}
+	for i, id := range sorted {
+		id.Index = i + frm
+	}
+	return sorted, nil
+}
+
 // GenerateTestPartyIDs generates a list of mock PartyIDs for tests
 func GenerateTestPartyIDs(count int, startAt ...int) SortedPartyIDs {
 	ids := make(UnSortedPartyIDs, 0, count)
@@ -110,6 +148,19 @@ func (spids SortedPartyIDs) Keys() []*big.Int {
 	return ids
 }
 
+// Fingerprint returns a SHA-256 digest of the keys of the parties in their sorted order. Nodes can compare their
+// fingerprints before a session to check that they built the same party list in the same order.
+func (spids SortedPartyIDs) Fingerprint() []byte {
+	h := sha256.New()
+	for _, pid := range spids {
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(pid.Key)))
+		h.Write(size[:])
+		h.Write(pid.Key)
+	}
+	return h.Sum(nil)
+}
+
 func (spids SortedPartyIDs) ToUnSorted() UnSortedPartyIDs {
 	return UnSortedPartyIDs(spids)
 }
@@ -0,0 +1,42 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// ssidTag separates the session IDs of ComputeSSID from the other uses of SHA512_256i_TAGGED
+var ssidTag = []byte("tss-lib/ssid")
+
+// SSIDLen is the length of the session IDs that ComputeSSID returns
+const SSIDLen = 32
+
+// ComputeSSID derives a session ID, e.g. for ComputeAggregateR or a presignature, from what every party of the
+// session knows alike: the curve, the keys of the sorted parties, the threshold, the public key and the nonce of the
+// session, which the parties agree on out of band and which must be fresh for every session. `publicKey` holds the
+// coordinates of the public key of the session, X then Y. The party ID of `params` is not used, so all the parties
+// get the same SSIDLen bytes; it returns nil for parameters without parties.
+func ComputeSSID(params *Parameters, sessionNonce []byte, publicKey ...*big.Int) []byte {
+	if params == nil || params.EC() == nil || params.Parties() == nil || len(params.Parties().IDs()) == 0 {
+		return nil
+	}
+	ec := params.EC().Params()
+	keys := params.Parties().IDs().Keys()
+	in := make([]*big.Int, 0, 11+len(keys)+len(publicKey))
+	in = append(in, ec.P, ec.N, ec.B, ec.Gx, ec.Gy, big.NewInt(int64(ec.BitSize))) // curve
+	in = append(in, big.NewInt(int64(len(keys))))
+	in = append(in, keys...) // parties
+	in = append(in, big.NewInt(int64(params.Threshold())))
+	in = append(in, big.NewInt(int64(len(publicKey))))
+	in = append(in, publicKey...)
+	// the length keeps the leading zero bytes of the nonce
+	in = append(in, big.NewInt(int64(len(sessionNonce))), new(big.Int).SetBytes(sessionNonce))
+	return common.SHA512_256i_TAGGED(ssidTag, in...).FillBytes(make([]byte, SSIDLen))
+}
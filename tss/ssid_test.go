@@ -0,0 +1,51 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestComputeSSID(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(5)
+	ctx := NewPeerContext(pIDs)
+	pubX, pubY := S256().Params().Gx, S256().Params().Gy
+	nonce := []byte("session 1")
+	ssid := ComputeSSID(NewParameters(S256(), ctx, pIDs[0], len(pIDs), 2), nonce, pubX, pubY)
+	assert.Len(t, ssid, SSIDLen)
+
+	// every party derives the same ssid
+	for _, pID := range pIDs {
+		params := NewParameters(S256(), NewPeerContext(pIDs), pID, len(pIDs), 2)
+		assert.Equal(t, ssid, ComputeSSID(params, []byte("session 1"), new(big.Int).Set(pubX), new(big.Int).Set(pubY)))
+	}
+
+	// and any change of the inputs changes it
+	otherIDs := GenerateTestPartyIDs(5, 1)
+	for name, other := range map[string][]byte{
+		"parties":     ComputeSSID(NewParameters(S256(), NewPeerContext(otherIDs), otherIDs[0], len(otherIDs), 2), nonce, pubX, pubY),
+		"fewer":       ComputeSSID(NewParameters(S256(), NewPeerContext(pIDs[:4]), pIDs[0], 4, 2), nonce, pubX, pubY),
+		"threshold":   ComputeSSID(NewParameters(S256(), ctx, pIDs[0], len(pIDs), 3), nonce, pubX, pubY),
+		"curve":       ComputeSSID(NewParameters(P256(), ctx, pIDs[0], len(pIDs), 2), nonce, pubX, pubY),
+		"public key":  ComputeSSID(NewParameters(S256(), ctx, pIDs[0], len(pIDs), 2), nonce, pubY, pubX),
+		"no key":      ComputeSSID(NewParameters(S256(), ctx, pIDs[0], len(pIDs), 2), nonce),
+		"nonce":       ComputeSSID(NewParameters(S256(), ctx, pIDs[0], len(pIDs), 2), []byte("session 2"), pubX, pubY),
+		"zero prefix": ComputeSSID(NewParameters(S256(), ctx, pIDs[0], len(pIDs), 2), append([]byte{0}, nonce...), pubX, pubY),
+	} {
+		if assert.Len(t, other, SSIDLen, name) {
+			assert.NotEqual(t, ssid, other, name)
+		}
+	}
+
+	assert.Nil(t, ComputeSSID(nil, nonce))
+	assert.Nil(t, ComputeSSID(NewParameters(S256(), nil, pIDs[0], len(pIDs), 2), nonce))
+}
@@ -0,0 +1,110 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// weightedShareTag separates the keys of the shares of a weighted party from the other uses of SHA512_256i_TAGGED
+var weightedShareTag = []byte("tss-lib/weighted-share")
+
+// WeightedPartyIDs maps parties with integer weights onto a plain t-of-n session, for access structures in which some
+// parties count more than others. A party of weight w holds w shares, i.e. w evaluation points of the VSS polynomials,
+// and runs one party of the protocol, keygen or signing, for each of them. A set of parties can then sign if their
+// weights add up to the threshold weight; the Lagrange coefficients are those of the shares that take part.
+//
+// The session is set up with IDs as its parties and a threshold of the threshold weight minus one. The shares of a
+// party are derived from its ID and key only, so all the parties compute the same IDs.
+type WeightedPartyIDs struct {
+	// IDs holds the party IDs of all the shares, sorted
+	IDs    SortedPartyIDs
+	owners map[string]*PartyID // by the key of a share
+}
+
+// NewWeightedPartyIDs returns the shares of the parties, weights[i] of them for parties[i]. Every weight must be
+// positive. The n-th share of a party has the ID and moniker of the party followed by "#n" and a key derived from the
+// key of the party.
+func NewWeightedPartyIDs(parties SortedPartyIDs, weights []int) (*WeightedPartyIDs, error) {
+	if len(parties) == 0 || len(parties) != len(weights) {
+		return nil, fmt.Errorf("NewWeightedPartyIDs(): got %d parties and %d weights", len(parties), len(weights))
+	}
+	w := &WeightedPartyIDs{owners: make(map[string]*PartyID)}
+	seen := make(map[string]struct{}, len(parties))
+	ids := make(UnSortedPartyIDs, 0, len(parties))
+	for i, party := range parties {
+		if party == nil || party.Key == nil {
+			return nil, fmt.Errorf("NewWeightedPartyIDs(): the party at position %d is nil or has no key", i)
+		}
+		if weights[i] <= 0 {
+			return nil, fmt.Errorf("NewWeightedPartyIDs(): the party %s has a weight of %d", party, weights[i])
+		}
+		if _, ok := seen[string(party.Key)]; ok {
+			return nil, fmt.Errorf("NewWeightedPartyIDs(): the party %s has the same key as another party", party)
+		}
+		seen[string(party.Key)] = struct{}{}
+		for n := 0; n < weights[i]; n++ {
+			key := common.SHA512_256i_TAGGED(weightedShareTag, party.KeyInt(), big.NewInt(int64(n)))
+			share := NewPartyID(fmt.Sprintf("%s#%d", party.Id, n), fmt.Sprintf("%s#%d", party.Moniker, n), key)
+			if _, ok := w.owners[string(share.Key)]; ok {
+				return nil, errors.New("NewWeightedPartyIDs(): two shares have the same key")
+			}
+			w.owners[string(share.Key)] = party
+			ids = append(ids, share)
+		}
+	}
+	w.IDs = SortPartyIDs(ids)
+	return w, nil
+}
+
+// SharesOf returns the IDs of the shares of the party, in the order of IDs, or nil if it is not one of the parties
+func (w *WeightedPartyIDs) SharesOf(party *PartyID) SortedPartyIDs {
+	if party == nil {
+		return nil
+	}
+	var out SortedPartyIDs
+	for _, id := range w.IDs {
+		if owner := w.Owner(id); string(owner.Key) == string(party.Key) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Owner returns the party that holds the share, or nil if it is not one of the shares
+func (w *WeightedPartyIDs) Owner(share *PartyID) *PartyID {
+	if share == nil {
+		return nil
+	}
+	return w.owners[string(share.Key)]
+}
+
+// Signers returns new, sorted IDs of all the shares of the parties, for setting up a signing session between them,
+// together with their total weight, which must reach the threshold weight. It fails if one of them is not a party or
+// if a party is listed twice.
+func (w *WeightedPartyIDs) Signers(parties ...*PartyID) (SortedPartyIDs, int, error) {
+	seen := make(map[string]struct{}, len(parties))
+	var ids UnSortedPartyIDs
+	for _, party := range parties {
+		shares := w.SharesOf(party)
+		if shares == nil {
+			return nil, 0, fmt.Errorf("Signers(): %v is not one of the weighted parties", party)
+		}
+		if _, ok := seen[string(party.Key)]; ok {
+			return nil, 0, fmt.Errorf("Signers(): the party %s is listed twice", party)
+		}
+		seen[string(party.Key)] = struct{}{}
+		for _, share := range shares {
+			ids = append(ids, NewPartyID(share.Id, share.Moniker, share.KeyInt()))
+		}
+	}
+	return SortPartyIDs(ids), len(ids), nil
+}
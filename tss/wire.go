@@ -8,7 +8,11 @@ package tss
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -24,6 +28,117 @@ func ParseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool) (Parsed
 	return parseWrappedMessage(wire, from)
 }
 
+// DefaultTypeURLPrefix is the prefix of the type URLs in the wire encoding that WireBytes returns, as anypb.New
+// writes them
+const DefaultTypeURLPrefix = "type.googleapis.com"
+
+// TypeURL returns the type URL of the content under `urlPrefix`, i.e. the prefix, a slash and the full name of the
+// message type; an empty prefix stands for DefaultTypeURLPrefix.
+func TypeURL(urlPrefix string, content MessageContent) string {
+	return typeURLPrefix(urlPrefix) + string(content.ProtoReflect().Descriptor().FullName())
+}
+
+// WireWithTypeURL returns the wire encoding of the message, a google.protobuf.Any holding its content as WireBytes
+// does, with the type URL of the content under `urlPrefix` instead of the default one, along with its routing. A
+// router in any language can dispatch on the type URL alone; the bytes are parsed back by ParseWireWithTypeURL, or by
+// ParseWireMessage, which takes any prefix.
+func WireWithTypeURL(msg ParsedMessage, urlPrefix string) ([]byte, *MessageRouting, error) {
+	if msg == nil || msg.Content() == nil {
+		return nil, nil, errors.New("WireWithTypeURL: the message has no content")
+	}
+	any, err := anypb.New(msg.Content())
+	if err != nil {
+		return nil, nil, err
+	}
+	any.TypeUrl = TypeURL(urlPrefix, msg.Content())
+	bz, err := proto.Marshal(any)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, routing, err := msg.WireBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	return bz, routing, nil
+}
+
+// ParseWireWithTypeURL reverses WireWithTypeURL: it is ParseWireMessage for a message whose type URL must be under
+// `urlPrefix`, an empty prefix standing for DefaultTypeURLPrefix. The concrete content is looked up by the full name
+// in the type URL among the registered message types.
+func ParseWireWithTypeURL(wireBytes []byte, urlPrefix string, from *PartyID, isBroadcast bool) (ParsedMessage, error) {
+	wire := new(MessageWrapper)
+	wire.Message = new(anypb.Any)
+	wire.From = from.MessageWrapper_PartyID
+	wire.IsBroadcast = isBroadcast
+	if err := proto.Unmarshal(wireBytes, wire.Message); err != nil {
+		return nil, err
+	}
+	urlPrefix = typeURLPrefix(urlPrefix)
+	name := strings.TrimPrefix(wire.Message.GetTypeUrl(), urlPrefix)
+	if name == wire.Message.GetTypeUrl() || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("ParseWireWithTypeURL: the type URL %q is not under %q", wire.Message.GetTypeUrl(), urlPrefix)
+	}
+	return parseWrappedMessage(wire, from)
+}
+
+// typeURLPrefix returns the prefix with a single trailing slash, or the default prefix if it is empty
+func typeURLPrefix(urlPrefix string) string {
+	if urlPrefix = strings.TrimSuffix(urlPrefix, "/"); urlPrefix == "" {
+		urlPrefix = DefaultTypeURLPrefix
+	}
+	return urlPrefix + "/"
+}
+
+// ValidateWireMessage runs the cheap checks on a message received from the wire, so that a server can drop garbage
+// before it is handed to UpdateFromBytes. The sender `from` is given alongside the bytes, as for ParseWireMessage.
+//
+// It checks that `from` is one of the parties of `params`, with the index that it has among them, and that wireBytes
+// hold a registered type of message content; only the type of the content is read, not its fields. When `round` is
+// not nil, the round must also accept that type with this routing, see Round.CanAccept. The content itself is only
+// deserialized and checked with ValidateBasic by ParseWireMessage.
+func ValidateWireMessage(params *Parameters, wireBytes []byte, from *PartyID, isBroadcast bool, round Round) error {
+	if params == nil || params.Parties() == nil {
+		return errors.New("ValidateWireMessage: the parameters have no parties")
+	}
+	if from == nil || !from.ValidateBasic() {
+		return errors.New("ValidateWireMessage: the sender is nil or invalid")
+	}
+	index := -1
+	for j, Pj := range params.Parties().IDs() {
+		if Pj.KeyInt().Cmp(from.KeyInt()) == 0 {
+			index = j
+			break
+		}
+	}
+	switch {
+	case index < 0:
+		return fmt.Errorf("ValidateWireMessage: the sender %s is not a party of the session", from)
+	case index != from.Index:
+		return fmt.Errorf("ValidateWireMessage: the sender %s has the index %d in the session", from, index)
+	}
+
+	any := new(anypb.Any)
+	if err := proto.Unmarshal(wireBytes, any); err != nil {
+		return fmt.Errorf("ValidateWireMessage: %v", err)
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(any.GetTypeUrl())
+	if err != nil {
+		return fmt.Errorf("ValidateWireMessage: unknown content type %q", any.GetTypeUrl())
+	}
+	content, ok := mt.New().Interface().(MessageContent)
+	if !ok {
+		return fmt.Errorf("ValidateWireMessage: %s is not a message content", mt.Descriptor().FullName())
+	}
+	if round != nil {
+		wire := &MessageWrapper{IsBroadcast: isBroadcast, From: from.MessageWrapper_PartyID}
+		msg := NewMessage(MessageRouting{From: from, IsBroadcast: isBroadcast}, content, wire)
+		if !round.CanAccept(msg) {
+			return fmt.Errorf("ValidateWireMessage: round %d does not accept a %s", round.RoundNumber(), msg.Type())
+		}
+	}
+	return nil
+}
+
 func parseWrappedMessage(wire *MessageWrapper, from *PartyID) (ParsedMessage, error) {
 	m, err := wire.Message.UnmarshalNew()
 	if err != nil {
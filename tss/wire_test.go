@@ -0,0 +1,72 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func wireBytes(t *testing.T, msg Message) []byte {
+	bz, _, err := msg.WireBytes()
+	assert.NoError(t, err)
+	return bz
+}
+
+func TestValidateWireMessage(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	params := NewParameters(Edwards(), NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	round := keygen.NewLocalParty(params, make(chan Message, 1), make(chan *keygen.LocalPartySaveData, 1)).FirstRound()
+	sender := pIDs[1]
+	r1msg := wireBytes(t, keygen.NewKGRound1Message(sender, big.NewInt(1)))
+
+	assert.NoError(t, ValidateWireMessage(params, r1msg, sender, true, round))
+	assert.NoError(t, ValidateWireMessage(params, r1msg, sender, true, nil))
+
+	// the sender must be a party of the session, with its index
+	outsider := GenerateTestPartyIDs(4)[3]
+	assert.Error(t, ValidateWireMessage(params, r1msg, outsider, true, round), "a sender outside of the party set")
+	spoofed := NewPartyID(sender.Id, sender.Moniker, sender.KeyInt())
+	spoofed.Index = 2
+	assert.Error(t, ValidateWireMessage(params, r1msg, spoofed, true, round), "a sender with another party's index")
+	assert.Error(t, ValidateWireMessage(params, r1msg, nil, true, round))
+	assert.Error(t, ValidateWireMessage(nil, r1msg, sender, true, round))
+
+	// the content must be of a known type, and one that the round accepts with this routing
+	x := big.NewInt(5)
+	X := crypto.ScalarBaseMult(Edwards(), x)
+	proof, err := schnorr.NewZKProof([]byte("session"), x, X, params.Rand())
+	assert.NoError(t, err)
+	r2msg := wireBytes(t, keygen.NewKGRound2Message2(sender, []*big.Int{big.NewInt(1)}, proof))
+	assert.NoError(t, ValidateWireMessage(params, r2msg, sender, true, nil))
+	assert.Error(t, ValidateWireMessage(params, r2msg, sender, true, round), "round 1 does not accept round 2 content")
+	assert.Error(t, ValidateWireMessage(params, r1msg, sender, false, round), "the commitment must be broadcast")
+
+	notContent, err := anypb.New(wrapperspb.String("garbage"))
+	assert.NoError(t, err)
+	notContentBz, err := proto.Marshal(notContent)
+	assert.NoError(t, err)
+	unknownBz, err := proto.Marshal(&anypb.Any{TypeUrl: "type.googleapis.com/no.such.Message", Value: []byte{1}})
+	assert.NoError(t, err)
+	for name, bz := range map[string][]byte{
+		"garbage":         {0xff, 0xff, 0xff},
+		"unknown type":    unknownBz,
+		"not tss content": notContentBz,
+	} {
+		assert.Error(t, ValidateWireMessage(params, bz, sender, true, nil), name)
+	}
+}